@@ -1,13 +1,33 @@
 package main
 
 import (
+	"context"
 	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 
 	"github.com/dkumancev/avito-pvz/config"
+	"github.com/dkumancev/avito-pvz/pkg/application/auth"
+	"github.com/dkumancev/avito-pvz/pkg/application/ratelimit"
+	"github.com/dkumancev/avito-pvz/pkg/application/services/apikey"
 	"github.com/dkumancev/avito-pvz/pkg/application/services/pvz"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/buildinfo"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/grpc/interceptors"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/grpc/server"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/lifecycle"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/logger"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/metrics"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/migrations"
+	apikeyrepository "github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/apikey"
+	auditrepository "github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/audit"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+	outboxrepository "github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/outbox"
 	pgzvrepository "github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/pvz"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/tracing"
+	"google.golang.org/grpc"
 )
 
 func main() {
@@ -16,21 +36,140 @@ func main() {
 		log.Fatalf("Ошибка загрузки конфигурации: %v", err)
 	}
 
+	err = cfg.Validate()
+	if err != nil {
+		log.Fatalf("Неверная конфигурация:\n%v", err)
+	}
+
+	appLogger, err := logger.NewFromConfig(cfg)
+	if err != nil {
+		log.Fatalf("Ошибка настройки логов: %v", err)
+	}
+	defer appLogger.Close()
+	slog.SetDefault(appLogger.Logger)
+
+	build := buildinfo.Get()
+	appLogger.Info("Запуск gRPC сервиса для управления ПВЗ",
+		"environment", cfg.App.Environment,
+		"version", build.Version,
+		"commit", build.Commit,
+		"go_version", build.GoVersion)
+
+	policy, err := auth.NewPolicyFromConfig(cfg.Auth.RolePermissions)
+	if err != nil {
+		log.Fatalf("Ошибка разбора прав ролей: %v", err)
+	}
+	policy.RegisterRoles()
+
+	err = policy.RequireTwoFactor(auth.ParseRoles(cfg.Auth.TwoFactorRoles)...)
+	if err != nil {
+		log.Fatalf("Ошибка настройки двухфакторной аутентификации: %v", err)
+	}
+
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing)
+	if err != nil {
+		log.Fatalf("Ошибка настройки трассировки: %v", err)
+	}
+
 	dbConn, err := db.New(cfg.Postgres)
 	if err != nil {
 		log.Fatalf("Ошибка подключения к базе данных: %v", err)
 	}
-	defer dbConn.Close()
+
+	err = metrics.RegisterDBStats(dbConn.DB, cfg.Postgres.DBName)
+	if err != nil {
+		log.Fatalf("Ошибка регистрации метрик пула соединений: %v", err)
+	}
+	db.SetQueryInstrumentation(metrics.NewDBMetrics(), cfg.Postgres.SlowQueryThreshold,
+		appLogger.Component(logger.ComponentDB))
+	db.SetRetryPolicy(db.RetryPolicyFromConfig(cfg.Postgres), appLogger.Component(logger.ComponentDB))
+
+	err = migrations.NewRunner(dbConn.DB, appLogger.Component(logger.ComponentDB)).CheckVersion()
+	if err != nil {
+		log.Fatalf("Схема базы данных не готова: %v", err)
+	}
 
 	pvzRepo := pgzvrepository.NewRepository(dbConn)
 
-	pvzService := pvz.New(pvzRepo)
+	auditRepo := auditrepository.NewRepository(dbConn)
+	transactor := db.NewTransactor(dbConn)
 
-	port := 50051
-	grpcServer := server.NewGRPCServer(pvzService, port)
+	pvzService := pvz.New(pvzRepo, auditRepo, outboxrepository.NewRepository(dbConn), transactor, metrics.NewBusinessMetrics(),
+		appLogger.Component(logger.ComponentServices))
+	apiKeyService := apikey.New(apikeyrepository.NewRepository(dbConn), auditRepo, transactor, policy,
+		appLogger.Component(logger.ComponentServices))
+
+	port, err := strconv.Atoi(cfg.GRPC.Port)
+	if err != nil {
+		log.Fatalf("Неверный порт gRPC сервера: %v", err)
+	}
+	grpcServer := server.NewGRPCServer(pvzService, port,
+		interceptors.TracingUnaryInterceptor(),
+		interceptors.RequestIDUnaryInterceptor(),
+		interceptors.LoggingUnaryInterceptor(appLogger.Component(logger.ComponentGRPC)),
+		interceptors.AuthUnaryInterceptor(auth.NewTokenParser([]byte(cfg.Auth.JWTSecret), cfg.Auth.DummyLoginEnabled), apiKeyService),
+		rateLimitInterceptor(cfg.RateLimit),
+		interceptors.PermissionUnaryInterceptor(policy, server.MethodPermissions),
+	)
 	log.Printf("Запуск gRPC сервера на порту %d...", port)
-	if err := grpcServer.Start(); err != nil {
-		log.Fatalf("Ошибка запуска gRPC сервера: %v", err)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- grpcServer.Start()
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	var runErr error
+	select {
+	case sig := <-quit:
+		appLogger.Info("Получен сигнал завершения", "signal", sig.String())
+	case runErr = <-serveErr:
+		appLogger.Error("Ошибка gRPC сервера", "error", runErr)
 	}
+
+	// тот же порядок, что и в HTTP сервере: снять готовность, дать
+	// балансировщику время, дождаться вызовов и только потом закрыть БД
+	shutdown := lifecycle.NewShutdown(cfg.Shutdown.Timeout, appLogger.Logger)
+	shutdown.Add("readiness", func(ctx context.Context) error {
+		grpcServer.SetNotServing()
+		return nil
+	})
+	if runErr == nil {
+		shutdown.Add("pre_stop_delay", lifecycle.Delay(cfg.Shutdown.PreStopDelay))
+	}
+	shutdown.Add("grpc", grpcServer.Shutdown)
+	shutdown.Add("tracing", shutdownTracing)
+	shutdown.Add("database", func(ctx context.Context) error {
+		return dbConn.Close()
+	})
+
+	err = shutdown.Run()
+	if runErr != nil || err != nil {
+		appLogger.Close()
+		os.Exit(1)
+	}
+}
+
+// rateLimitInterceptor создает ограничение частоты вызовов по настройкам.
+// Если ограничение выключено, вызовы проходят без изменений
+func rateLimitInterceptor(cfg config.RateLimitConfig) grpc.UnaryServerInterceptor {
+	if !cfg.Enabled {
+		return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			return handler(ctx, req)
+		}
+	}
+
+	routes := make(map[string]ratelimit.Limit, len(cfg.Routes))
+	for route, limit := range cfg.Routes {
+		routes[route] = ratelimit.Limit(limit)
+	}
+	limiter := ratelimit.New(ratelimit.Config{
+		Default: ratelimit.Limit(cfg.Default),
+		Public:  ratelimit.Limit(cfg.Public),
+		Routes:  routes,
+	})
+
+	return interceptors.RateLimitUnaryInterceptor(limiter, metrics.NewRateLimitMetrics())
 }
- 
\ No newline at end of file
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"fmt"
 	"log"
+	"os"
 
 	"github.com/dkumancev/avito-pvz/config"
 	"github.com/dkumancev/avito-pvz/internal/server"
@@ -13,8 +15,41 @@ func main() {
 		log.Fatalf("Ошибка загрузки конфигурации: %v", err)
 	}
 
-	srv := server.NewServer(cfg)
+	// config print выводит итоговую конфигурацию без запуска сервера
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		os.Exit(runConfigCommand(cfg, os.Args[2:]))
+	}
+
+	err = cfg.Validate()
+	if err != nil {
+		log.Fatalf("Неверная конфигурация:\n%v", err)
+	}
+
+	srv, err := server.NewServer(cfg)
+	if err != nil {
+		log.Fatalf("Ошибка создания сервера: %v", err)
+	}
 	if err := srv.Start(); err != nil {
 		log.Fatalf("Ошибка запуска сервера: %v", err)
 	}
 }
+
+func runConfigCommand(cfg *config.Config, args []string) int {
+	if len(args) != 1 || args[0] != "print" {
+		fmt.Fprintln(os.Stderr, "Использование: config print")
+		return 2
+	}
+
+	err := cfg.Print(os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка вывода конфигурации: %v\n", err)
+		return 1
+	}
+
+	err = cfg.Validate()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Конфигурация не пройдет проверку при запуске:\n%v\n", err)
+		return 1
+	}
+	return 0
+}
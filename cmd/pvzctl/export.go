@@ -0,0 +1,269 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/internal/api/v1/handlers"
+	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+// exportPageSize - размер страницы при обходе ПВЗ
+const exportPageSize = 100
+
+const (
+	exportFormatJSON = "json"
+	exportFormatCSV  = "csv"
+)
+
+// exportWriter пишет ПВЗ с приемками в выбранном формате по одному
+type exportWriter interface {
+	Write(item handlers.PVZWithReceptionsResponse) error
+	Close() error
+}
+
+func runExport(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet(a, "export")
+	from := fs.String("from", "", "начало периода приемок в RFC3339")
+	to := fs.String("to", "", "конец периода приемок в RFC3339")
+	format := fs.String("format", exportFormatJSON, "формат: json или csv")
+	output := fs.String("output", "", "файл для выгрузки, по умолчанию stdout")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	filter := repositories.PVZFilter{Page: 1, Limit: exportPageSize}
+	filter.ReceptionStartDate, err = parseTimeFlag("from", *from)
+	if err != nil {
+		return err
+	}
+	filter.ReceptionEndDate, err = parseTimeFlag("to", *to)
+	if err != nil {
+		return err
+	}
+
+	out := a.stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("ошибка создания файла выгрузки: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	var w exportWriter
+	switch *format {
+	case exportFormatJSON:
+		w = newJSONExportWriter(out)
+	case exportFormatCSV:
+		w, err = newCSVExportWriter(out)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: неизвестный формат %q", errUsage, *format)
+	}
+
+	count, err := exportPVZs(ctx, a, filter, w)
+	if err != nil {
+		return err
+	}
+	err = w.Close()
+	if err != nil {
+		return fmt.Errorf("ошибка записи выгрузки: %w", err)
+	}
+
+	fmt.Fprintf(a.stderr, "Выгружено ПВЗ: %d\n", count)
+	return nil
+}
+
+func parseTimeFlag(name, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: --%s ожидается в формате RFC3339: %v", errUsage, name, err)
+	}
+	return &parsed, nil
+}
+
+// exportPVZs постранично обходит ПВЗ и выгружает их с приемками за период
+// и товарами. Данные читаются через сервисы, как в GET /pvz
+func exportPVZs(ctx context.Context, a *app, filter repositories.PVZFilter, w exportWriter) (int, error) {
+	count := 0
+	for {
+		page, err := a.pvzs.ListPVZs(ctx, filter)
+		if err != nil {
+			return count, err
+		}
+
+		for _, p := range page {
+			item, err := exportPVZ(ctx, a, p, filter)
+			if err != nil {
+				return count, err
+			}
+			err = w.Write(item)
+			if err != nil {
+				return count, fmt.Errorf("ошибка записи выгрузки: %w", err)
+			}
+			count++
+		}
+
+		if len(page) < filter.Limit {
+			return count, nil
+		}
+		filter.Page++
+	}
+}
+
+func exportPVZ(ctx context.Context, a *app, p *domain.PVZ, filter repositories.PVZFilter) (handlers.PVZWithReceptionsResponse, error) {
+	item := handlers.PVZWithReceptionsResponse{
+		PVZ: handlers.PVZResponse{
+			ID:               p.ID,
+			RegistrationDate: p.RegistrationDate,
+			City:             p.City,
+		},
+		Receptions: []handlers.ReceptionWithProducts{},
+	}
+
+	receptions, err := a.receptions.GetReceptionsByPVZID(ctx, p.ID)
+	if err != nil {
+		return item, err
+	}
+
+	for _, r := range receptions {
+		if filter.ReceptionStartDate != nil && r.DateTime.Before(*filter.ReceptionStartDate) ||
+			filter.ReceptionEndDate != nil && r.DateTime.After(*filter.ReceptionEndDate) {
+			continue
+		}
+
+		products, err := a.receptions.GetProductsByReceptionID(ctx, r.ID)
+		if err != nil {
+			return item, err
+		}
+
+		entry := handlers.ReceptionWithProducts{
+			Reception: handlers.ReceptionResponse{
+				ID:       r.ID,
+				DateTime: r.DateTime,
+				PVZID:    r.PVZID,
+				Status:   r.Status,
+			},
+			Products: make([]handlers.ProductResponse, 0, len(products)),
+		}
+		for _, product := range products {
+			entry.Products = append(entry.Products, handlers.ProductResponse{
+				ID:          product.ID,
+				DateTime:    product.DateTime,
+				Type:        product.Type,
+				ReceptionID: product.ReceptionID,
+			})
+		}
+		item.Receptions = append(item.Receptions, entry)
+	}
+
+	return item, nil
+}
+
+// jsonExportWriter пишет JSON массив в формате ответа GET /pvz, не собирая
+// всю выгрузку в памяти
+type jsonExportWriter struct {
+	w     io.Writer
+	count int
+}
+
+func newJSONExportWriter(w io.Writer) *jsonExportWriter {
+	return &jsonExportWriter{w: w}
+}
+
+func (j *jsonExportWriter) Write(item handlers.PVZWithReceptionsResponse) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+
+	prefix := ",\n"
+	if j.count == 0 {
+		prefix = "[\n"
+	}
+	j.count++
+
+	_, err = fmt.Fprintf(j.w, "%s%s", prefix, data)
+	return err
+}
+
+func (j *jsonExportWriter) Close() error {
+	if j.count == 0 {
+		_, err := io.WriteString(j.w, "[]\n")
+		return err
+	}
+	_, err := io.WriteString(j.w, "\n]\n")
+	return err
+}
+
+// csvExportWriter пишет по строке на товар. Приемки без товаров и ПВЗ без
+// приемок выгружаются строкой с пустыми колонками
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+var csvExportHeader = []string{
+	"pvz_id", "pvz_city", "pvz_registration_date",
+	"reception_id", "reception_date", "reception_status",
+	"product_id", "product_date", "product_type",
+}
+
+func newCSVExportWriter(w io.Writer) (*csvExportWriter, error) {
+	writer := csv.NewWriter(w)
+	err := writer.Write(csvExportHeader)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка записи выгрузки: %w", err)
+	}
+	return &csvExportWriter{w: writer}, nil
+}
+
+func (c *csvExportWriter) Write(item handlers.PVZWithReceptionsResponse) error {
+	pvzColumns := []string{item.PVZ.ID, item.PVZ.City, formatExportTime(item.PVZ.RegistrationDate)}
+	if len(item.Receptions) == 0 {
+		return c.w.Write(append(pvzColumns, "", "", "", "", "", ""))
+	}
+
+	for _, r := range item.Receptions {
+		receptionColumns := append(append([]string(nil), pvzColumns...),
+			r.Reception.ID, formatExportTime(r.Reception.DateTime), r.Reception.Status)
+		if len(r.Products) == 0 {
+			err := c.w.Write(append(receptionColumns, "", "", ""))
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, product := range r.Products {
+			err := c.w.Write(append(append([]string(nil), receptionColumns...),
+				product.ID, formatExportTime(product.DateTime), product.Type))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *csvExportWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func formatExportTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
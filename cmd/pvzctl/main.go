@@ -0,0 +1,212 @@
+// pvzctl - утилита администрирования сервиса ПВЗ. Работает с той же БД и
+// через те же сервисы, что и API, поэтому бизнес-правила и журнал аудита
+// применяются одинаково
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/config"
+	"github.com/dkumancev/avito-pvz/pkg/application/auth"
+	"github.com/dkumancev/avito-pvz/pkg/application/services"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/logger"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/metrics"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/migrations"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/assignment"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/audit"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/outbox"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/product"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/pvz"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/reception"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/twofactor"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/user"
+	"github.com/jmoiron/sqlx"
+)
+
+const usage = `Использование: pvzctl <команда> [параметры]
+
+Команды:
+  migrate up|down|status|version [--to N]  управление схемой БД (down без --to
+                                           откатывает одну миграцию)
+  migrate reset --yes                      откат всех миграций с удалением данных
+  user create --email E --password P [--role R]
+                                           создание пользователя или модератора
+  pvz create --city C                      создание ПВЗ
+  reception close --pvz ID                 закрытие активной приемки ПВЗ
+  reception close-stale --older-than D [--dry-run]
+                                           закрытие зависших приемок
+  export [--from T] [--to T] [--format json|csv] [--output FILE]
+                                           выгрузка ПВЗ с приемками и товарами
+
+Конфигурация читается так же, как сервисом: из переменных окружения и файла CONFIG_FILE
+`
+
+// commandTimeout ограничивает время выполнения одной команды
+const commandTimeout = 30 * time.Minute
+
+// errUsage - неверные аргументы команды
+var errUsage = errors.New("неверные аргументы")
+
+type command func(ctx context.Context, app *app, args []string) error
+
+var commands = map[string]command{
+	"migrate":   runMigrate,
+	"user":      runUser,
+	"pvz":       runPVZ,
+	"reception": runReception,
+	"export":    runExport,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "Неизвестная команда %q\n\n%s", args[0], usage)
+		return 2
+	}
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		fmt.Fprintf(stderr, "Ошибка загрузки конфигурации: %v\n", err)
+		return 1
+	}
+	err = cfg.Validate()
+	if err != nil {
+		fmt.Fprintf(stderr, "Неверная конфигурация:\n%v\n", err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	app, err := newApp(cfg, stdout, stderr)
+	if err != nil {
+		fmt.Fprintf(stderr, "Ошибка инициализации: %v\n", logger.SanitizeError(err))
+		return 1
+	}
+	defer app.Close()
+
+	err = cmd(ctx, app, args[1:])
+	if errors.Is(err, errUsage) || errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintf(stderr, "%v\n\n%s", err, usage)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "Ошибка: %v\n", logger.SanitizeError(err))
+		return 1
+	}
+	return 0
+}
+
+// app - зависимости команд. Собираются так же, как в api.Router
+type app struct {
+	cfg       *config.Config
+	db        *sqlx.DB
+	appLogger *logger.Logger
+	stdout    io.Writer
+	stderr    io.Writer
+
+	runner     *migrations.Runner
+	users      services.UserService
+	pvzs       services.PVZService
+	receptions services.ReceptionService
+}
+
+func newApp(cfg *config.Config, stdout, stderr io.Writer) (*app, error) {
+	// вывод команд идет в stdout, поэтому логи пишутся в stderr
+	componentLevels, err := logger.ParseComponentLevels(cfg.Log.ComponentLevels)
+	if err != nil {
+		return nil, err
+	}
+	appLogger, err := logger.New(logger.Config{
+		Level:           cfg.App.LogLevel,
+		Format:          cfg.Log.Format,
+		Output:          stderr,
+		ComponentLevels: componentLevels,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания логгера: %w", err)
+	}
+	// логгер по умолчанию используют библиотеки и повторы подключения к БД
+	slog.SetDefault(appLogger.Logger)
+
+	dbConn, err := db.New(cfg.Postgres)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка подключения к базе данных: %w", err)
+	}
+
+	policy, err := auth.NewPolicyFromConfig(cfg.Auth.RolePermissions)
+	if err != nil {
+		dbConn.Close()
+		return nil, fmt.Errorf("ошибка разбора прав ролей: %w", err)
+	}
+	policy.RegisterRoles()
+
+	userRepo := user.New(dbConn)
+	pvzRepo := pvz.New(dbConn)
+	receptionRepo := reception.New(dbConn)
+	productRepo := product.New(dbConn)
+	assignmentRepo := assignment.New(dbConn)
+	auditRepo := audit.New(dbConn)
+	outboxRepo := outbox.New(dbConn)
+	db.SetRetryPolicy(db.RetryPolicyFromConfig(cfg.Postgres), appLogger.Component(logger.ComponentDB))
+	transactor := db.NewTransactor(dbConn)
+	business := metrics.NewBusinessMetrics()
+	serviceLogger := appLogger.Component(logger.ComponentServices)
+
+	return &app{
+		cfg:       cfg,
+		db:        dbConn,
+		appLogger: appLogger,
+		stdout:    stdout,
+		stderr:    stderr,
+
+		runner: migrations.NewRunner(dbConn.DB, appLogger.Component(logger.ComponentDB)),
+		users: services.NewUserService(userRepo, twofactor.New(dbConn), auditRepo, transactor,
+			[]byte(cfg.Auth.JWTSecret), cfg.Auth.TokenTTL),
+		pvzs: services.NewPVZService(pvzRepo, auditRepo, outboxRepo, transactor, business, serviceLogger),
+		receptions: services.NewReceptionService(pvzRepo, receptionRepo, productRepo, assignmentRepo,
+			auditRepo, outboxRepo, transactor, policy, business, serviceLogger),
+	}, nil
+}
+
+func (a *app) Close() {
+	a.db.Close()
+	a.appLogger.Close()
+}
+
+// newFlagSet создает набор флагов подкоманды, ошибки разбора которого
+// возвращаются, а не завершают процесс
+func newFlagSet(a *app, name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	return fs
+}
+
+// subcommand отделяет имя подкоманды от ее флагов
+func subcommand(args []string) (string, []string) {
+	if len(args) == 0 {
+		return "", nil
+	}
+	return args[0], args[1:]
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+	"time"
+)
+
+func runMigrate(ctx context.Context, a *app, args []string) error {
+	name, args := subcommand(args)
+	fs := newFlagSet(a, "migrate "+name)
+	to := fs.Int64("to", 0, "версия, до которой выполнить или откатить миграции (up и down)")
+	yes := fs.Bool("yes", false, "подтверждение удаления всей схемы (только для reset)")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	switch name {
+	case "up":
+		if *to > 0 {
+			return a.runner.RunMigrationToVersion(ctx, *to)
+		}
+		return a.runner.RunMigrationsUp(ctx)
+	case "down":
+		// без --to откатывается одна миграция, чтобы опечатка не стоила всей схемы
+		if *to > 0 {
+			return a.runner.RollbackToVersion(ctx, *to)
+		}
+		return a.runner.RollbackMigration(ctx)
+	case "reset":
+		if !*yes {
+			return fmt.Errorf("%w: migrate reset удаляет все таблицы и данные, подтвердите флагом --yes", errUsage)
+		}
+		return a.runner.RunMigrationsDown(ctx)
+	case "version":
+		current, err := a.runner.GetCurrentVersion()
+		if err != nil {
+			return err
+		}
+		expected, err := a.runner.ExpectedVersion()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(a.stdout, "текущая версия: %d\nпоследняя встроенная: %d\n", current, expected)
+		return nil
+	case "status":
+		return printMigrationStatus(ctx, a)
+	default:
+		return fmt.Errorf("%w: migrate %q", errUsage, name)
+	}
+}
+
+func printMigrationStatus(ctx context.Context, a *app) error {
+	statuses, err := a.runner.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(a.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tAPPLIED AT\tMIGRATION")
+	for _, status := range statuses {
+		appliedAt := "ожидает"
+		if status.Applied {
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", status.Version, appliedAt, status.Name)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+func runPVZ(ctx context.Context, a *app, args []string) error {
+	name, args := subcommand(args)
+	if name != "create" {
+		return fmt.Errorf("%w: pvz %q", errUsage, name)
+	}
+
+	fs := newFlagSet(a, "pvz create")
+	city := fs.String("city", "", "город ПВЗ")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if *city == "" {
+		return fmt.Errorf("%w: нужен --city", errUsage)
+	}
+
+	created, err := a.pvzs.CreatePVZ(ctx, *city)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "Создан ПВЗ %s в городе %s, зарегистрирован %s\n",
+		created.ID, created.City, created.RegistrationDate.Format(time.RFC3339))
+	return nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+func runReception(ctx context.Context, a *app, args []string) error {
+	name, args := subcommand(args)
+	switch name {
+	case "close":
+		return closeReception(ctx, a, args)
+	case "close-stale":
+		return closeStaleReceptions(ctx, a, args)
+	default:
+		return fmt.Errorf("%w: reception %q", errUsage, name)
+	}
+}
+
+func closeReception(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet(a, "reception close")
+	pvzID := fs.String("pvz", "", "ID ПВЗ")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if *pvzID == "" {
+		return fmt.Errorf("%w: нужен --pvz", errUsage)
+	}
+
+	closed, err := a.receptions.CloseReception(ctx, *pvzID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "Закрыта приемка %s ПВЗ %s\n", closed.ID, closed.PVZID)
+	return nil
+}
+
+// closeStaleReceptions закрывает от имени системы приемки, открытые дольше
+// olderThan. Ошибка закрытия одной приемки не прерывает остальные
+func closeStaleReceptions(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet(a, "reception close-stale")
+	olderThan := fs.Duration("older-than", 0, "возраст приемки, после которого она считается зависшей, например 24h")
+	dryRun := fs.Bool("dry-run", false, "только показать приемки, не закрывая их")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if *olderThan <= 0 {
+		return fmt.Errorf("%w: нужен положительный --older-than", errUsage)
+	}
+
+	stale, err := a.receptions.ListStaleReceptions(ctx, time.Now().Add(-*olderThan))
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, r := range stale {
+		age := time.Since(r.DateTime).Round(time.Minute)
+		if *dryRun {
+			fmt.Fprintf(a.stdout, "%s\tПВЗ %s\tоткрыта %s назад\n", r.ID, r.PVZID, age)
+			continue
+		}
+
+		reason := fmt.Sprintf("закрыта через pvzctl: открыта дольше %s", *olderThan)
+		_, err = a.receptions.CloseStaleReception(ctx, r.ID, reason)
+		if err != nil {
+			failed++
+			fmt.Fprintf(a.stderr, "Не удалось закрыть приемку %s ПВЗ %s: %v\n", r.ID, r.PVZID, err)
+			continue
+		}
+		fmt.Fprintf(a.stdout, "Закрыта приемка %s ПВЗ %s, была открыта %s\n", r.ID, r.PVZID, age)
+	}
+
+	if *dryRun {
+		fmt.Fprintf(a.stdout, "Найдено зависших приемок: %d\n", len(stale))
+		return nil
+	}
+	if failed > 0 {
+		return fmt.Errorf("не закрыто %d из %d приемок", failed, len(stale))
+	}
+	fmt.Fprintf(a.stdout, "Закрыто приемок: %d\n", len(stale))
+	return nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+func runUser(ctx context.Context, a *app, args []string) error {
+	name, args := subcommand(args)
+	if name != "create" {
+		return fmt.Errorf("%w: user %q", errUsage, name)
+	}
+
+	fs := newFlagSet(a, "user create")
+	email := fs.String("email", "", "email пользователя")
+	password := fs.String("password", "", "пароль пользователя")
+	role := fs.String("role", string(domain.EmployeeRole), "роль: employee, moderator или роль из AUTH_ROLE_PERMISSIONS")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if *email == "" || *password == "" {
+		return fmt.Errorf("%w: нужны --email и --password", errUsage)
+	}
+
+	// проверки email, пароля и роли выполняет сервис, как при регистрации через API
+	created, err := a.users.Register(ctx, *email, *password, domain.UserRole(*role))
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "Создан пользователь %s (%s), id %s\n", created.Email, created.Role, created.ID)
+	return nil
+}
@@ -6,25 +6,65 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	App      AppConfig
-	Postgres PostgresConfig
-	HTTP     HTTPConfig
-	GRPC     GRPCConfig
-	Metrics  MetricsConfig
-	Auth     AuthConfig
+	App       AppConfig
+	Log       LogConfig
+	Postgres  PostgresConfig
+	HTTP      HTTPConfig
+	GRPC      GRPCConfig
+	Metrics   MetricsConfig
+	Auth      AuthConfig
+	Health    HealthConfig
+	Tracing   TracingConfig
+	SLO       SLOConfig
+	Reception ReceptionConfig
+	Shutdown  ShutdownConfig
+	RateLimit RateLimitConfig
+	Cache     CacheConfig
+	Outbox    OutboxConfig
+	Webhook   WebhookConfig
+
+	file     string             // путь к файлу конфигурации, если он задан
+	settings map[string]setting // итоговые значения параметров для Print
+	invalid  []string           // параметры с неверными значениями
 }
 
+const (
+	EnvironmentDevelopment = "development"
+	EnvironmentStaging     = "staging"
+	EnvironmentProduction  = "production"
+)
+
+// DefaultJWTSecret - секрет по умолчанию для локальной разработки.
+// В production Validate не пропускает его
+const DefaultJWTSecret = "your-secret-key"
+
 // общие настройки приложения
 type AppConfig struct {
-	Environment   string // development, staging, production
-	LogLevel      string // debug, info, warn, error
-	MigrationsDir string // путь к директории с миграциями
+	Environment string // development, staging, production
+	LogLevel    string // debug, info, warn, error
+	// MigrateOnStart применяет встроенные миграции при запуске сервера
+	MigrateOnStart bool
+}
+
+// настройки вывода логов; уровень задается в AppConfig.LogLevel
+type LogConfig struct {
+	Format    string // json, text
+	AddSource bool   // добавлять файл и строку вызова
+	// File - файл для логов с ротацией; пустой - писать в stdout
+	File       string
+	MaxSizeMB  int
+	MaxBackups int
+	MaxAgeDays int
+	Compress   bool
+	// ComponentLevels уровни отдельных компонентов: "db=warn,http=debug"
+	ComponentLevels string
 }
 
 type PostgresConfig struct {
@@ -38,6 +78,18 @@ type PostgresConfig struct {
 	ConnMaxLifetime time.Duration
 	MaxIdleConns    int
 	ConnMaxIdleTime time.Duration
+	// запросы репозиториев дольше порога логируются как медленные; 0 - не логировать
+	SlowQueryThreshold time.Duration
+
+	// попытки подключения при старте: БД может подниматься позже сервиса
+	ConnectAttempts   int
+	ConnectBackoff    time.Duration // задержка перед второй попыткой, дальше растет вдвое
+	ConnectMaxBackoff time.Duration
+
+	// повторы чтений и транзакций при временных ошибках БД; 1 - без повторов
+	RetryAttempts   int
+	RetryBackoff    time.Duration
+	RetryMaxBackoff time.Duration
 }
 
 func (c PostgresConfig) ConnURI() string {
@@ -56,11 +108,115 @@ type GRPCConfig struct {
 
 type MetricsConfig struct {
 	Port string
+	// DebugEnabled включает pprof, дамп горутин и сведения о сборке на сервере метрик
+	DebugEnabled bool
+	// AdminToken - bearer токен для отладочных эндпоинтов и /log/level.
+	// Обязателен, если DebugEnabled; без него /log/level не подключается
+	AdminToken string
+}
+
+type HealthConfig struct {
+	CheckTimeout time.Duration // таймаут каждой проверки /readyz
+	GRPCTarget   string        // адрес gRPC сервера для проверки готовности; пустой - не проверять
+}
+
+type TracingConfig struct {
+	Exporter     string  // none, stdout, otlp
+	OTLPEndpoint string  // адрес OTLP/gRPC коллектора
+	ServiceName  string  // имя сервиса в трассах
+	SampleRatio  float64 // доля трассируемых запросов (0..1)
+}
+
+// цели по задержке и доступности HTTP API
+type SLOConfig struct {
+	LatencyThreshold   time.Duration   // запрос быстрее порога считается уложившимся в SLO
+	LatencyTarget      float64         // доля запросов быстрее порога (0.99 - p99)
+	AvailabilityTarget float64         // доля запросов без ошибок 5xx
+	Windows            []time.Duration // скользящие окна расчета бюджета ошибок
+}
+
+// Действия с зависшими приемками
+const (
+	StaleActionClose = "close" // закрыть от имени системы
+	StaleActionFlag  = "flag"  // только отметить зависшей
+)
+
+// обработка зависших приемок, которые не закрыли вовремя
+type ReceptionConfig struct {
+	StaleJobEnabled    bool                     // запускать фоновую задачу в API сервере
+	StaleAfter         time.Duration            // возраст, после которого незакрытая приемка считается зависшей
+	StaleAfterByCity   map[string]time.Duration // возраст для отдельных городов: "Москва=12h,Казань=48h"
+	StaleAction        string                   // close или flag
+	StaleCheckInterval time.Duration            // период проверки
+}
+
+// завершение работы по SIGTERM
+type ShutdownConfig struct {
+	Timeout      time.Duration // общее время на все шаги остановки, включая паузу
+	PreStopDelay time.Duration // пауза между снятием готовности и остановкой серверов
+}
+
+// RateLimit - не больше Requests запросов за Period; нулевое значение не ограничивает
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ограничение частоты запросов пользователей, API-ключей и публичных маршрутов
+type RateLimitConfig struct {
+	Enabled           bool
+	Default           RateLimit            // для пользователей и API-ключей
+	Public            RateLimit            // для публичных маршрутов, по IP клиента
+	Routes            map[string]RateLimit // "POST /products=10/1s,POST /login=5/1m"
+	TrustForwardedFor bool                 // брать IP клиента из X-Forwarded-For, если сервис за прокси
+}
+
+// кэш чтения ПВЗ в памяти процесса
+type CacheConfig struct {
+	PVZEnabled bool
+	PVZSize    int           // максимальное количество ПВЗ в кэше
+	PVZTTL     time.Duration // время жизни записи
+}
+
+// Способы публикации событий из outbox
+const (
+	OutboxPublisherMemory = "memory" // подписчики внутри процесса
+	OutboxPublisherFile   = "file"   // JSONL-файл
+	OutboxPublisherKafka  = "kafka"  // Kafka-совместимый брокер
+)
+
+// публикация доменных событий из outbox
+type OutboxConfig struct {
+	RelayEnabled bool          // запускать публикацию в API сервере
+	Publisher    string        // memory, file или kafka
+	PollInterval time.Duration // период проверки новых событий
+	BatchSize    int           // событий за одну транзакцию
+	Retention    time.Duration // срок хранения опубликованных событий, 0 - не удалять
+	FilePath     string        // файл для publisher=file
+	KafkaBrokers []string      // адреса брокеров через запятую
+	KafkaTopic   string
+}
+
+// доставка вебхуков. Доставки создаются задачей публикации outbox
+type WebhookConfig struct {
+	Enabled      bool          // запускать отправку в API сервере
+	PollInterval time.Duration // период проверки доставок, которым пора отправляться
+	BatchSize    int           // доставок за одну выборку
+	Timeout      time.Duration // ожидание ответа получателя
+	MaxAttempts  int           // после стольких неудач доставка попадает в dead
+	BackoffBase  time.Duration // задержка перед первым повтором, дальше удваивается
+	BackoffMax   time.Duration // предельная задержка между повторами
 }
 
 type AuthConfig struct {
-	JWTSecret string
-	TokenTTL  time.Duration
+	JWTSecret       string
+	TokenTTL        time.Duration
+	RolePermissions string // дополнительные роли и их права: "role=perm1,perm2;role2=perm3"
+	// DummyLoginEnabled включает /dummyLogin и прием выданных им токенов.
+	// По умолчанию включен только в окружении development
+	DummyLoginEnabled bool
+	// TwoFactorRoles роли через запятую, которым доступ к API дается только после входа с TOTP кодом
+	TwoFactorRoles string
 }
 
 
@@ -97,70 +253,357 @@ func LoadEnv() {
 func NewConfig() (*Config, error) {
 	LoadEnv()
 
+	src, err := newSource(os.Getenv(ConfigFileEnv))
+	if err != nil {
+		return nil, err
+	}
+
 	// Настройки приложения
-	environment := getEnv("APP_ENVIRONMENT", "development")
-	logLevel := getEnv("APP_LOG_LEVEL", "info")
-	migrationsDir := getEnv("APP_MIGRATIONS_DIR", "./migrations")
+	environment := src.get("APP_ENVIRONMENT", "development")
+	logLevel := src.get("APP_LOG_LEVEL", "info")
+	migrateOnStart, err := strconv.ParseBool(src.get("APP_MIGRATE_ON_START", "false"))
+	if err != nil {
+		src.invalidValue("APP_MIGRATE_ON_START", err)
+		migrateOnStart = false
+	}
+
+	// Настройки логов
+	logFormatDefault := "text"
+	if environment == "production" {
+		logFormatDefault = "json"
+	}
+	logFormat := src.get("LOG_FORMAT", logFormatDefault)
+	logAddSource, err := strconv.ParseBool(src.get("LOG_ADD_SOURCE", "false"))
+	if err != nil {
+		src.invalidValue("LOG_ADD_SOURCE", err)
+		logAddSource = false
+	}
+	logFile := src.get("LOG_FILE", "")
+	logMaxSize, err := strconv.Atoi(src.get("LOG_FILE_MAX_SIZE_MB", "100"))
+	if err != nil {
+		src.invalidValue("LOG_FILE_MAX_SIZE_MB", err)
+		logMaxSize = 100
+	}
+	logMaxBackups, err := strconv.Atoi(src.get("LOG_FILE_MAX_BACKUPS", "5"))
+	if err != nil {
+		src.invalidValue("LOG_FILE_MAX_BACKUPS", err)
+		logMaxBackups = 5
+	}
+	logMaxAge, err := strconv.Atoi(src.get("LOG_FILE_MAX_AGE_DAYS", "30"))
+	if err != nil {
+		src.invalidValue("LOG_FILE_MAX_AGE_DAYS", err)
+		logMaxAge = 30
+	}
+	logCompress, err := strconv.ParseBool(src.get("LOG_FILE_COMPRESS", "true"))
+	if err != nil {
+		src.invalidValue("LOG_FILE_COMPRESS", err)
+		logCompress = true
+	}
+	logComponentLevels := src.get("LOG_COMPONENT_LEVELS", "")
 
 	// Настройки PostgreSQL
-	pgHost := getEnv("POSTGRES_HOST", "localhost")
-	pgPort := getEnv("POSTGRES_PORT", "5432")
-	pgUser := getEnv("POSTGRES_USER", "postgres")
-	pgPassword := getEnv("POSTGRES_PASSWORD", "postgres")
-	pgDBName := getEnv("POSTGRES_DB", "pvz")
-	pgSSLMode := getEnv("POSTGRES_SSLMODE", "disable")
+	pgHost := src.get("POSTGRES_HOST", "localhost")
+	pgPort := src.get("POSTGRES_PORT", "5432")
+	pgUser := src.get("POSTGRES_USER", "postgres")
+	pgPassword := src.get("POSTGRES_PASSWORD", "postgres")
+	pgDBName := src.get("POSTGRES_DB", "pvz")
+	pgSSLMode := src.get("POSTGRES_SSLMODE", "disable")
 
-	pgMaxOpenConns, err := strconv.Atoi(getEnv("POSTGRES_MAX_OPEN_CONNS", "60"))
+	pgMaxOpenConns, err := strconv.Atoi(src.get("POSTGRES_MAX_OPEN_CONNS", "60"))
 	if err != nil {
-		log.Printf("Неверное значение POSTGRES_MAX_OPEN_CONNS, используется значение по умолчанию: %v", err)
+		src.invalidValue("POSTGRES_MAX_OPEN_CONNS", err)
 		pgMaxOpenConns = 60
 	}
 
-	pgConnMaxLifetime, err := time.ParseDuration(getEnv("POSTGRES_CONN_MAX_LIFETIME", "120s"))
+	pgConnMaxLifetime, err := time.ParseDuration(src.get("POSTGRES_CONN_MAX_LIFETIME", "120s"))
 	if err != nil {
-		log.Printf("Неверное значение POSTGRES_CONN_MAX_LIFETIME, используется значение по умолчанию: %v", err)
+		src.invalidValue("POSTGRES_CONN_MAX_LIFETIME", err)
 		pgConnMaxLifetime = 120 * time.Second
 	}
 
-	pgMaxIdleConns, err := strconv.Atoi(getEnv("POSTGRES_MAX_IDLE_CONNS", "30"))
+	pgMaxIdleConns, err := strconv.Atoi(src.get("POSTGRES_MAX_IDLE_CONNS", "30"))
 	if err != nil {
-		log.Printf("Неверное значение POSTGRES_MAX_IDLE_CONNS, используется значение по умолчанию: %v", err)
+		src.invalidValue("POSTGRES_MAX_IDLE_CONNS", err)
 		pgMaxIdleConns = 30
 	}
 
-	pgConnMaxIdleTime, err := time.ParseDuration(getEnv("POSTGRES_CONN_MAX_IDLE_TIME", "20s"))
+	pgConnMaxIdleTime, err := time.ParseDuration(src.get("POSTGRES_CONN_MAX_IDLE_TIME", "20s"))
 	if err != nil {
-		log.Printf("Неверное значение POSTGRES_CONN_MAX_IDLE_TIME, используется значение по умолчанию: %v", err)
+		src.invalidValue("POSTGRES_CONN_MAX_IDLE_TIME", err)
 		pgConnMaxIdleTime = 20 * time.Second
 	}
 
+	pgSlowQueryThreshold, err := time.ParseDuration(src.get("POSTGRES_SLOW_QUERY_THRESHOLD", "200ms"))
+	if err != nil {
+		src.invalidValue("POSTGRES_SLOW_QUERY_THRESHOLD", err)
+		pgSlowQueryThreshold = 200 * time.Millisecond
+	}
+	pgConnectAttempts, err := strconv.Atoi(src.get("POSTGRES_CONNECT_ATTEMPTS", "10"))
+	if err != nil {
+		src.invalidValue("POSTGRES_CONNECT_ATTEMPTS", err)
+		pgConnectAttempts = 10
+	}
+	pgConnectBackoff, err := time.ParseDuration(src.get("POSTGRES_CONNECT_BACKOFF", "500ms"))
+	if err != nil {
+		src.invalidValue("POSTGRES_CONNECT_BACKOFF", err)
+		pgConnectBackoff = 500 * time.Millisecond
+	}
+	pgConnectMaxBackoff, err := time.ParseDuration(src.get("POSTGRES_CONNECT_MAX_BACKOFF", "10s"))
+	if err != nil {
+		src.invalidValue("POSTGRES_CONNECT_MAX_BACKOFF", err)
+		pgConnectMaxBackoff = 10 * time.Second
+	}
+	pgRetryAttempts, err := strconv.Atoi(src.get("POSTGRES_RETRY_ATTEMPTS", "3"))
+	if err != nil {
+		src.invalidValue("POSTGRES_RETRY_ATTEMPTS", err)
+		pgRetryAttempts = 3
+	}
+	pgRetryBackoff, err := time.ParseDuration(src.get("POSTGRES_RETRY_BACKOFF", "50ms"))
+	if err != nil {
+		src.invalidValue("POSTGRES_RETRY_BACKOFF", err)
+		pgRetryBackoff = 50 * time.Millisecond
+	}
+	pgRetryMaxBackoff, err := time.ParseDuration(src.get("POSTGRES_RETRY_MAX_BACKOFF", "1s"))
+	if err != nil {
+		src.invalidValue("POSTGRES_RETRY_MAX_BACKOFF", err)
+		pgRetryMaxBackoff = time.Second
+	}
+
 	// Настройки HTTP сервера
-	httpPort := getEnv("HTTP_PORT", "8080")
-	httpTimeout, err := time.ParseDuration(getEnv("HTTP_TIMEOUT", "30s"))
+	httpPort := src.get("HTTP_PORT", "8080")
+	httpTimeout, err := time.ParseDuration(src.get("HTTP_TIMEOUT", "30s"))
 	if err != nil {
-		log.Printf("Неверное значение HTTP_TIMEOUT, используется значение по умолчанию: %v", err)
+		src.invalidValue("HTTP_TIMEOUT", err)
 		httpTimeout = 30 * time.Second
 	}
 
 	// Настройки GRPC сервера
-	grpcPort := getEnv("GRPC_PORT", "3000")
+	grpcPort := src.get("GRPC_PORT", "3000")
 
 	// Настройки метрик
-	metricsPort := getEnv("METRICS_PORT", "9000")
+	metricsPort := src.get("METRICS_PORT", "9000")
+	metricsDebugEnabled, err := strconv.ParseBool(src.get("METRICS_DEBUG_ENABLED", "false"))
+	if err != nil {
+		src.invalidValue("METRICS_DEBUG_ENABLED", err)
+		metricsDebugEnabled = false
+	}
+	metricsAdminToken := src.get("METRICS_ADMIN_TOKEN", "")
+
+	// Настройки проверок здоровья
+	healthCheckTimeout, err := time.ParseDuration(src.get("HEALTH_CHECK_TIMEOUT", "2s"))
+	if err != nil {
+		src.invalidValue("HEALTH_CHECK_TIMEOUT", err)
+		healthCheckTimeout = 2 * time.Second
+	}
+	healthGRPCTarget := src.get("HEALTH_GRPC_TARGET", "")
+
+	// Настройки трассировки
+	tracingExporter := src.get("TRACING_EXPORTER", "none")
+	tracingEndpoint := src.get("OTEL_EXPORTER_OTLP_ENDPOINT", "localhost:4317")
+	tracingServiceName := src.get("OTEL_SERVICE_NAME", "pvz-api")
+	tracingSampleRatio, err := strconv.ParseFloat(src.get("TRACING_SAMPLE_RATIO", "1"), 64)
+	if err != nil {
+		src.invalidValue("TRACING_SAMPLE_RATIO", err)
+		tracingSampleRatio = 1
+	}
+
+	// Настройки SLO
+	sloLatencyThreshold, err := time.ParseDuration(src.get("SLO_LATENCY_THRESHOLD", "100ms"))
+	if err != nil {
+		src.invalidValue("SLO_LATENCY_THRESHOLD", err)
+		sloLatencyThreshold = 100 * time.Millisecond
+	}
+	sloLatencyTarget, err := strconv.ParseFloat(src.get("SLO_LATENCY_TARGET", "0.99"), 64)
+	if err != nil {
+		src.invalidValue("SLO_LATENCY_TARGET", err)
+		sloLatencyTarget = 0.99
+	}
+	sloAvailabilityTarget, err := strconv.ParseFloat(src.get("SLO_AVAILABILITY_TARGET", "0.9999"), 64)
+	if err != nil {
+		src.invalidValue("SLO_AVAILABILITY_TARGET", err)
+		sloAvailabilityTarget = 0.9999
+	}
+	sloWindows, err := parseDurations(src.get("SLO_WINDOWS", "5m,1h,24h"))
+	if err != nil {
+		src.invalidValue("SLO_WINDOWS", err)
+		sloWindows = []time.Duration{5 * time.Minute, time.Hour, 24 * time.Hour}
+	}
+
+	// Настройки обработки зависших приемок
+	staleJobEnabled, err := strconv.ParseBool(src.get("RECEPTION_STALE_JOB_ENABLED", "false"))
+	if err != nil {
+		src.invalidValue("RECEPTION_STALE_JOB_ENABLED", err)
+		staleJobEnabled = false
+	}
+	staleAfter, err := time.ParseDuration(src.get("RECEPTION_STALE_AFTER", "24h"))
+	if err != nil {
+		src.invalidValue("RECEPTION_STALE_AFTER", err)
+		staleAfter = 24 * time.Hour
+	}
+	staleAfterByCity, err := parseCityDurations(src.get("RECEPTION_STALE_AFTER_BY_CITY", ""))
+	if err != nil {
+		src.invalidValue("RECEPTION_STALE_AFTER_BY_CITY", err)
+		staleAfterByCity = nil
+	}
+	staleAction := src.get("RECEPTION_STALE_ACTION", StaleActionClose)
+	staleCheckInterval, err := time.ParseDuration(src.get("RECEPTION_STALE_CHECK_INTERVAL", "5m"))
+	if err != nil {
+		src.invalidValue("RECEPTION_STALE_CHECK_INTERVAL", err)
+		staleCheckInterval = 5 * time.Minute
+	}
 
 	// Настройки авторизации
-	jwtSecret := getEnv("JWT_SECRET", "your-secret-key") // TODO: в продакшене secret key должен быть задан
-	tokenTTL, err := time.ParseDuration(getEnv("TOKEN_TTL", "24h"))
+	jwtSecret := src.get("JWT_SECRET", DefaultJWTSecret)
+	tokenTTL, err := time.ParseDuration(src.get("TOKEN_TTL", "24h"))
 	if err != nil {
-		log.Printf("Неверное значение TOKEN_TTL, используется значение по умолчанию: %v", err)
+		src.invalidValue("TOKEN_TTL", err)
 		tokenTTL = 24 * time.Hour
 	}
+	rolePermissions := src.get("AUTH_ROLE_PERMISSIONS", "")
+
+	dummyLoginDefault := strconv.FormatBool(environment == "development")
+	dummyLoginEnabled, err := strconv.ParseBool(src.get("AUTH_DUMMY_LOGIN_ENABLED", dummyLoginDefault))
+	if err != nil {
+		src.invalidValue("AUTH_DUMMY_LOGIN_ENABLED", err)
+		dummyLoginEnabled = environment == "development"
+	}
+
+	twoFactorRoles := src.get("AUTH_2FA_REQUIRED_ROLES", "")
+
+	// Настройки ограничения частоты запросов
+	rateLimitEnabled, err := strconv.ParseBool(src.get("RATELIMIT_ENABLED", "true"))
+	if err != nil {
+		src.invalidValue("RATELIMIT_ENABLED", err)
+		rateLimitEnabled = true
+	}
+	rateLimitDefault, err := parseRateLimit(src.get("RATELIMIT_DEFAULT", "20/1s"))
+	if err != nil {
+		src.invalidValue("RATELIMIT_DEFAULT", err)
+		rateLimitDefault = RateLimit{Requests: 20, Period: time.Second}
+	}
+	rateLimitPublic, err := parseRateLimit(src.get("RATELIMIT_PUBLIC", "30/1m"))
+	if err != nil {
+		src.invalidValue("RATELIMIT_PUBLIC", err)
+		rateLimitPublic = RateLimit{Requests: 30, Period: time.Minute}
+	}
+	rateLimitRoutes, err := parseRouteRateLimits(src.get("RATELIMIT_ROUTES", "POST /products=10/1s"))
+	if err != nil {
+		src.invalidValue("RATELIMIT_ROUTES", err)
+		rateLimitRoutes = map[string]RateLimit{"POST /products": {Requests: 10, Period: time.Second}}
+	}
+	rateLimitTrustForwardedFor, err := strconv.ParseBool(src.get("RATELIMIT_TRUST_FORWARDED_FOR", "false"))
+	if err != nil {
+		src.invalidValue("RATELIMIT_TRUST_FORWARDED_FOR", err)
+		rateLimitTrustForwardedFor = false
+	}
+
+	// Настройки кэша
+	cachePVZEnabled, err := strconv.ParseBool(src.get("CACHE_PVZ_ENABLED", "true"))
+	if err != nil {
+		src.invalidValue("CACHE_PVZ_ENABLED", err)
+		cachePVZEnabled = true
+	}
+	cachePVZSize, err := strconv.Atoi(src.get("CACHE_PVZ_SIZE", "10000"))
+	if err != nil {
+		src.invalidValue("CACHE_PVZ_SIZE", err)
+		cachePVZSize = 10000
+	}
+	cachePVZTTL, err := time.ParseDuration(src.get("CACHE_PVZ_TTL", "5m"))
+	if err != nil {
+		src.invalidValue("CACHE_PVZ_TTL", err)
+		cachePVZTTL = 5 * time.Minute
+	}
+
+	// Настройки публикации событий
+	outboxRelayEnabled, err := strconv.ParseBool(src.get("OUTBOX_RELAY_ENABLED", "true"))
+	if err != nil {
+		src.invalidValue("OUTBOX_RELAY_ENABLED", err)
+		outboxRelayEnabled = true
+	}
+	outboxPublisher := src.get("OUTBOX_PUBLISHER", OutboxPublisherMemory)
+	outboxPollInterval, err := time.ParseDuration(src.get("OUTBOX_POLL_INTERVAL", "1s"))
+	if err != nil {
+		src.invalidValue("OUTBOX_POLL_INTERVAL", err)
+		outboxPollInterval = time.Second
+	}
+	outboxBatchSize, err := strconv.Atoi(src.get("OUTBOX_BATCH_SIZE", "100"))
+	if err != nil {
+		src.invalidValue("OUTBOX_BATCH_SIZE", err)
+		outboxBatchSize = 100
+	}
+	outboxRetention, err := time.ParseDuration(src.get("OUTBOX_RETENTION", "168h"))
+	if err != nil {
+		src.invalidValue("OUTBOX_RETENTION", err)
+		outboxRetention = 168 * time.Hour
+	}
+	outboxFilePath := src.get("OUTBOX_FILE_PATH", "events.jsonl")
+	outboxKafkaBrokers := parseList(src.get("OUTBOX_KAFKA_BROKERS", "localhost:9092"))
+	outboxKafkaTopic := src.get("OUTBOX_KAFKA_TOPIC", "pvz.events")
+
+	// Настройки вебхуков
+	webhookEnabled, err := strconv.ParseBool(src.get("WEBHOOK_ENABLED", "true"))
+	if err != nil {
+		src.invalidValue("WEBHOOK_ENABLED", err)
+		webhookEnabled = true
+	}
+	webhookPollInterval, err := time.ParseDuration(src.get("WEBHOOK_POLL_INTERVAL", "5s"))
+	if err != nil {
+		src.invalidValue("WEBHOOK_POLL_INTERVAL", err)
+		webhookPollInterval = 5 * time.Second
+	}
+	webhookBatchSize, err := strconv.Atoi(src.get("WEBHOOK_BATCH_SIZE", "50"))
+	if err != nil {
+		src.invalidValue("WEBHOOK_BATCH_SIZE", err)
+		webhookBatchSize = 50
+	}
+	webhookTimeout, err := time.ParseDuration(src.get("WEBHOOK_TIMEOUT", "10s"))
+	if err != nil {
+		src.invalidValue("WEBHOOK_TIMEOUT", err)
+		webhookTimeout = 10 * time.Second
+	}
+	webhookMaxAttempts, err := strconv.Atoi(src.get("WEBHOOK_MAX_ATTEMPTS", "8"))
+	if err != nil {
+		src.invalidValue("WEBHOOK_MAX_ATTEMPTS", err)
+		webhookMaxAttempts = 8
+	}
+	webhookBackoffBase, err := time.ParseDuration(src.get("WEBHOOK_BACKOFF_BASE", "30s"))
+	if err != nil {
+		src.invalidValue("WEBHOOK_BACKOFF_BASE", err)
+		webhookBackoffBase = 30 * time.Second
+	}
+	webhookBackoffMax, err := time.ParseDuration(src.get("WEBHOOK_BACKOFF_MAX", "1h"))
+	if err != nil {
+		src.invalidValue("WEBHOOK_BACKOFF_MAX", err)
+		webhookBackoffMax = time.Hour
+	}
 
-	return &Config{
+	// Настройки завершения работы
+	shutdownTimeout, err := time.ParseDuration(src.get("SHUTDOWN_TIMEOUT", "15s"))
+	if err != nil {
+		src.invalidValue("SHUTDOWN_TIMEOUT", err)
+		shutdownTimeout = 15 * time.Second
+	}
+	shutdownPreStopDelay, err := time.ParseDuration(src.get("SHUTDOWN_PRE_STOP_DELAY", "0s"))
+	if err != nil {
+		src.invalidValue("SHUTDOWN_PRE_STOP_DELAY", err)
+		shutdownPreStopDelay = 0
+	}
+
+	cfg := &Config{
 		App: AppConfig{
-			Environment:   environment,
-			LogLevel:      logLevel,
-			MigrationsDir: migrationsDir,
+			Environment:    environment,
+			LogLevel:       logLevel,
+			MigrateOnStart: migrateOnStart,
+		},
+		Log: LogConfig{
+			Format:          logFormat,
+			AddSource:       logAddSource,
+			File:            logFile,
+			MaxSizeMB:       logMaxSize,
+			MaxBackups:      logMaxBackups,
+			MaxAgeDays:      logMaxAge,
+			Compress:        logCompress,
+			ComponentLevels: logComponentLevels,
 		},
 		Postgres: PostgresConfig{
 			Host:            pgHost,
@@ -173,6 +616,15 @@ func NewConfig() (*Config, error) {
 			ConnMaxLifetime: pgConnMaxLifetime,
 			MaxIdleConns:    pgMaxIdleConns,
 			ConnMaxIdleTime: pgConnMaxIdleTime,
+
+			SlowQueryThreshold: pgSlowQueryThreshold,
+
+			ConnectAttempts:   pgConnectAttempts,
+			ConnectBackoff:    pgConnectBackoff,
+			ConnectMaxBackoff: pgConnectMaxBackoff,
+			RetryAttempts:     pgRetryAttempts,
+			RetryBackoff:      pgRetryBackoff,
+			RetryMaxBackoff:   pgRetryMaxBackoff,
 		},
 		HTTP: HTTPConfig{
 			Port:    httpPort,
@@ -182,19 +634,206 @@ func NewConfig() (*Config, error) {
 			Port: grpcPort,
 		},
 		Metrics: MetricsConfig{
-			Port: metricsPort,
+			Port:         metricsPort,
+			DebugEnabled: metricsDebugEnabled,
+			AdminToken:   metricsAdminToken,
 		},
 		Auth: AuthConfig{
-			JWTSecret: jwtSecret,
-			TokenTTL:  tokenTTL,
+			JWTSecret:         jwtSecret,
+			TokenTTL:          tokenTTL,
+			RolePermissions:   rolePermissions,
+			DummyLoginEnabled: dummyLoginEnabled,
+			TwoFactorRoles:    twoFactorRoles,
+		},
+		Health: HealthConfig{
+			CheckTimeout: healthCheckTimeout,
+			GRPCTarget:   healthGRPCTarget,
+		},
+		Tracing: TracingConfig{
+			Exporter:     tracingExporter,
+			OTLPEndpoint: tracingEndpoint,
+			ServiceName:  tracingServiceName,
+			SampleRatio:  tracingSampleRatio,
+		},
+		SLO: SLOConfig{
+			LatencyThreshold:   sloLatencyThreshold,
+			LatencyTarget:      sloLatencyTarget,
+			AvailabilityTarget: sloAvailabilityTarget,
+			Windows:            sloWindows,
+		},
+		Reception: ReceptionConfig{
+			StaleJobEnabled:    staleJobEnabled,
+			StaleAfter:         staleAfter,
+			StaleAfterByCity:   staleAfterByCity,
+			StaleAction:        staleAction,
+			StaleCheckInterval: staleCheckInterval,
+		},
+		Shutdown: ShutdownConfig{
+			Timeout:      shutdownTimeout,
+			PreStopDelay: shutdownPreStopDelay,
+		},
+		Cache: CacheConfig{
+			PVZEnabled: cachePVZEnabled,
+			PVZSize:    cachePVZSize,
+			PVZTTL:     cachePVZTTL,
 		},
-	}, nil
+		Outbox: OutboxConfig{
+			RelayEnabled: outboxRelayEnabled,
+			Publisher:    outboxPublisher,
+			PollInterval: outboxPollInterval,
+			BatchSize:    outboxBatchSize,
+			Retention:    outboxRetention,
+			FilePath:     outboxFilePath,
+			KafkaBrokers: outboxKafkaBrokers,
+			KafkaTopic:   outboxKafkaTopic,
+		},
+		Webhook: WebhookConfig{
+			Enabled:      webhookEnabled,
+			PollInterval: webhookPollInterval,
+			BatchSize:    webhookBatchSize,
+			Timeout:      webhookTimeout,
+			MaxAttempts:  webhookMaxAttempts,
+			BackoffBase:  webhookBackoffBase,
+			BackoffMax:   webhookBackoffMax,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:           rateLimitEnabled,
+			Default:           rateLimitDefault,
+			Public:            rateLimitPublic,
+			Routes:            rateLimitRoutes,
+			TrustForwardedFor: rateLimitTrustForwardedFor,
+		},
+		file:     src.path,
+		settings: src.settings,
+		invalid:  src.invalid,
+	}
+
+	if unknown := src.unknownKeys(); len(unknown) > 0 {
+		return nil, fmt.Errorf("неизвестные параметры в файле конфигурации: %s", strings.Join(unknown, ", "))
+	}
+
+	return cfg, nil
+}
+
+// parseList разбирает список через запятую, пропуская пустые элементы
+func parseList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// parseDurations разбирает список длительностей через запятую: "5m,1h"
+func parseDurations(value string) ([]time.Duration, error) {
+	var result []time.Duration
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		duration, err := time.ParseDuration(item)
+		if err != nil {
+			return nil, err
+		}
+		if duration <= 0 {
+			return nil, fmt.Errorf("длительность должна быть положительной: %s", item)
+		}
+		result = append(result, duration)
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("список пуст")
+	}
+	return result, nil
+}
+
+// parseCityDurations разбирает длительности по городам: "Москва=12h,Казань=48h"
+func parseCityDurations(value string) (map[string]time.Duration, error) {
+	result := make(map[string]time.Duration)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		city, raw, ok := strings.Cut(item, "=")
+		city = strings.TrimSpace(city)
+		if !ok || city == "" {
+			return nil, fmt.Errorf("ожидается город=длительность: %s", item)
+		}
+
+		duration, err := time.ParseDuration(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, err
+		}
+		if duration <= 0 {
+			return nil, fmt.Errorf("длительность должна быть положительной: %s", item)
+		}
+		result[city] = duration
+	}
+	return result, nil
 }
 
-func getEnv(key, defaultValue string) string {
-	value := os.Getenv(key)
-	if value == "" {
-		return defaultValue
+// parseRateLimit разбирает ограничение вида "10/1s" или "100/m": количество
+// запросов и период. "off" отключает ограничение
+func parseRateLimit(value string) (RateLimit, error) {
+	value = strings.TrimSpace(value)
+	if value == "off" {
+		return RateLimit{}, nil
+	}
+
+	rawRequests, rawPeriod, ok := strings.Cut(value, "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("ожидается запросы/период: %s", value)
+	}
+
+	requests, err := strconv.Atoi(strings.TrimSpace(rawRequests))
+	if err != nil || requests <= 0 {
+		return RateLimit{}, fmt.Errorf("неверное количество запросов: %s", value)
+	}
+
+	// "m" - то же, что "1m"
+	rawPeriod = strings.TrimSpace(rawPeriod)
+	if rawPeriod != "" && (rawPeriod[0] < '0' || rawPeriod[0] > '9') {
+		rawPeriod = "1" + rawPeriod
+	}
+	period, err := time.ParseDuration(rawPeriod)
+	if err != nil {
+		return RateLimit{}, err
+	}
+	if period <= 0 {
+		return RateLimit{}, fmt.Errorf("период должен быть больше 0: %s", value)
+	}
+
+	return RateLimit{Requests: requests, Period: period}, nil
+}
+
+// parseRouteRateLimits разбирает ограничения маршрутов:
+// "POST /products=10/1s,/pvz.v1.PVZService/GetPVZList=off"
+func parseRouteRateLimits(value string) (map[string]RateLimit, error) {
+	result := make(map[string]RateLimit)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		route, raw, ok := strings.Cut(item, "=")
+		route = strings.Join(strings.Fields(route), " ")
+		if !ok || route == "" {
+			return nil, fmt.Errorf("ожидается маршрут=ограничение: %s", item)
+		}
+
+		limit, err := parseRateLimit(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", route, err)
+		}
+		result[route] = limit
 	}
-	return value
+	return result, nil
 }
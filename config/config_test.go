@@ -0,0 +1,186 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestNewConfig_FileUnderEnv(t *testing.T) {
+	path := writeConfigFile(t, `
+app:
+  environment: staging
+postgres:
+  host: db.internal
+  max_open_conns: 80
+http:
+  port: 8081
+slo:
+  windows: [10m, 2h]
+reception:
+  stale_after_by_city: "Москва=12h, Казань=48h"
+shutdown:
+  pre_stop_delay: 5s
+`)
+	t.Setenv(ConfigFileEnv, path)
+	t.Setenv("POSTGRES_HOST", "")
+	t.Setenv("HTTP_PORT", "9090")
+
+	cfg, err := NewConfig()
+	require.NoError(t, err)
+
+	assert.Equal(t, EnvironmentStaging, cfg.App.Environment)
+	assert.Equal(t, "db.internal", cfg.Postgres.Host)
+	assert.Equal(t, 80, cfg.Postgres.MaxOpenConns)
+	assert.Equal(t, "9090", cfg.HTTP.Port, "переменная окружения перекрывает файл")
+	assert.Equal(t, []time.Duration{10 * time.Minute, 2 * time.Hour}, cfg.SLO.Windows)
+	assert.Equal(t, map[string]time.Duration{"Москва": 12 * time.Hour, "Казань": 48 * time.Hour},
+		cfg.Reception.StaleAfterByCity)
+	assert.Equal(t, StaleActionClose, cfg.Reception.StaleAction)
+	assert.Equal(t, 5*time.Second, cfg.Shutdown.PreStopDelay)
+	assert.Equal(t, 15*time.Second, cfg.Shutdown.Timeout)
+}
+
+func TestNewConfig_UnknownFileKey(t *testing.T) {
+	t.Setenv(ConfigFileEnv, writeConfigFile(t, "postgres:\n  hots: db\n"))
+
+	_, err := NewConfig()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "POSTGRES_HOTS")
+}
+
+func TestConfig_PrintRedactsSecrets(t *testing.T) {
+	t.Setenv(ConfigFileEnv, writeConfigFile(t, "auth:\n  dummy_login_enabled: false\n"))
+	t.Setenv("POSTGRES_PASSWORD", "super-secret-password")
+	t.Setenv("JWT_SECRET", "jwt-secret-value")
+
+	cfg, err := NewConfig()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, cfg.Print(&buf))
+	output := buf.String()
+
+	assert.NotContains(t, output, "super-secret-password")
+	assert.NotContains(t, output, "jwt-secret-value")
+	assert.Contains(t, output, "postgres:\n")
+	assert.Contains(t, output, "  password: '[REDACTED]' # env")
+	assert.Contains(t, output, "  dummy_login_enabled: \"false\" # file")
+	assert.Contains(t, output, "  max_open_conns: \"60\" # default")
+}
+
+func validProductionConfig() *Config {
+	return &Config{
+		App: AppConfig{Environment: EnvironmentProduction, LogLevel: "info"},
+		Log: LogConfig{Format: "json"},
+		Postgres: PostgresConfig{
+			SSLMode:         "require",
+			MaxOpenConns:    10,
+			MaxIdleConns:    5,
+			ConnectAttempts: 10,
+			RetryAttempts:   3,
+		},
+		HTTP:    HTTPConfig{Port: "8080", Timeout: time.Second},
+		GRPC:    GRPCConfig{Port: "3000"},
+		Metrics: MetricsConfig{Port: "9000"},
+		Auth: AuthConfig{
+			JWTSecret: strings.Repeat("s", minSecretLength),
+			TokenTTL:  time.Hour,
+		},
+		Tracing: TracingConfig{Exporter: "otlp", SampleRatio: 0.1},
+		SLO:     SLOConfig{LatencyThreshold: 100 * time.Millisecond, LatencyTarget: 0.99, AvailabilityTarget: 0.9999},
+		Reception: ReceptionConfig{
+			StaleAfter:         24 * time.Hour,
+			StaleAction:        StaleActionClose,
+			StaleCheckInterval: 5 * time.Minute,
+		},
+		Shutdown: ShutdownConfig{Timeout: 15 * time.Second, PreStopDelay: 5 * time.Second},
+		Outbox:   OutboxConfig{Publisher: OutboxPublisherMemory, PollInterval: time.Second, BatchSize: 100},
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	require.NoError(t, validProductionConfig().Validate())
+
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		want   string
+	}{
+		{"секрет по умолчанию", func(c *Config) { c.Auth.JWTSecret = DefaultJWTSecret }, "JWT_SECRET"},
+		{"короткий секрет", func(c *Config) { c.Auth.JWTSecret = "short" }, "JWT_SECRET"},
+		{"тестовый вход", func(c *Config) { c.Auth.DummyLoginEnabled = true }, "AUTH_DUMMY_LOGIN_ENABLED"},
+		{"БД без TLS", func(c *Config) { c.Postgres.SSLMode = "disable" }, "POSTGRES_SSLMODE"},
+		{"неверные значения", func(c *Config) { c.invalid = []string{"HTTP_TIMEOUT"} }, "HTTP_TIMEOUT"},
+		{"отладка без токена", func(c *Config) { c.Metrics.DebugEnabled = true }, "METRICS_ADMIN_TOKEN"},
+		{"короткий токен администратора", func(c *Config) { c.Metrics.AdminToken = "short" }, "METRICS_ADMIN_TOKEN"},
+		{"совпадающие порты", func(c *Config) { c.Metrics.Port = "8080" }, "METRICS_PORT"},
+		{"пул соединений", func(c *Config) { c.Postgres.MaxIdleConns = 20 }, "POSTGRES_MAX_IDLE_CONNS"},
+		{"попытки подключения", func(c *Config) { c.Postgres.ConnectAttempts = 0 }, "POSTGRES_CONNECT_ATTEMPTS"},
+		{"доля сэмплирования", func(c *Config) { c.Tracing.SampleRatio = 2 }, "TRACING_SAMPLE_RATIO"},
+		{"действие с зависшими приемками", func(c *Config) { c.Reception.StaleAction = "delete" }, "RECEPTION_STALE_ACTION"},
+		{"пауза дольше таймаута", func(c *Config) { c.Shutdown.PreStopDelay = time.Minute }, "SHUTDOWN_PRE_STOP_DELAY"},
+		{"размер кэша", func(c *Config) { c.Cache = CacheConfig{PVZEnabled: true, PVZTTL: time.Minute} }, "CACHE_PVZ_SIZE"},
+		{"способ публикации событий", func(c *Config) { c.Outbox.Publisher = "nats" }, "OUTBOX_PUBLISHER"},
+		{"брокер без адресов", func(c *Config) { c.Outbox.Publisher = OutboxPublisherKafka }, "OUTBOX_KAFKA_BROKERS"},
+		{"вебхуки без outbox", func(c *Config) {
+			c.Webhook = WebhookConfig{Enabled: true, PollInterval: time.Second, BatchSize: 1, Timeout: time.Second,
+				MaxAttempts: 1, BackoffBase: time.Second, BackoffMax: time.Second}
+		}, "OUTBOX_RELAY_ENABLED"},
+		{"задержка повтора вебхука", func(c *Config) {
+			c.Outbox.RelayEnabled = true
+			c.Webhook = WebhookConfig{Enabled: true, PollInterval: time.Second, BatchSize: 1, Timeout: time.Second,
+				MaxAttempts: 1, BackoffBase: time.Minute, BackoffMax: time.Second}
+		}, "WEBHOOK_BACKOFF_MAX"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validProductionConfig()
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestConfig_ValidateDevelopmentAllowsDefaults(t *testing.T) {
+	cfg := validProductionConfig()
+	cfg.App.Environment = EnvironmentDevelopment
+	cfg.Auth.JWTSecret = DefaultJWTSecret
+	cfg.Auth.DummyLoginEnabled = true
+	cfg.Postgres.SSLMode = "disable"
+
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestParseRouteRateLimits(t *testing.T) {
+	limits, err := parseRouteRateLimits("POST  /products=10/1s, POST /login=5/m,/pvz.v1.PVZService/GetPVZList=off")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]RateLimit{
+		"POST /products":                {Requests: 10, Period: time.Second},
+		"POST /login":                   {Requests: 5, Period: time.Minute},
+		"/pvz.v1.PVZService/GetPVZList": {},
+	}, limits)
+
+	for _, value := range []string{"POST /products", "POST /products=10", "POST /products=0/1s", "POST /products=10/0s", "=10/1s"} {
+		_, err := parseRouteRateLimits(value)
+		assert.Error(t, err, value)
+	}
+}
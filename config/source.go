@@ -0,0 +1,184 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Источники значения параметра в порядке возрастания приоритета
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+)
+
+// ConfigFileEnv - переменная окружения с путем к YAML файлу конфигурации
+const ConfigFileEnv = "CONFIG_FILE"
+
+// setting - итоговое значение параметра и его источник
+type setting struct {
+	value  string
+	source string
+}
+
+// source собирает значения параметров: переменные окружения перекрывают
+// файл конфигурации, файл перекрывает значения по умолчанию
+type source struct {
+	path     string
+	file     map[string]string
+	settings map[string]setting
+	invalid  []string
+}
+
+// newSource читает файл конфигурации, если путь задан. Файл состоит из
+// секций с параметрами; параметр секции соответствует переменной окружения
+// SECTION_KEY:
+//
+//	postgres:
+//	  host: db           # POSTGRES_HOST
+//	  max_open_conns: 60 # POSTGRES_MAX_OPEN_CONNS
+func newSource(path string) (*source, error) {
+	src := &source{
+		path:     path,
+		file:     make(map[string]string),
+		settings: make(map[string]setting),
+	}
+	if path == "" {
+		return src, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения файла конфигурации: %w", err)
+	}
+
+	var sections map[string]map[string]any
+	err = yaml.Unmarshal(data, &sections)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка разбора файла конфигурации %s: %w", path, err)
+	}
+
+	for section, values := range sections {
+		for key, value := range values {
+			name := strings.ToUpper(section + "_" + key)
+			src.file[name], err = formatFileValue(value)
+			if err != nil {
+				return nil, fmt.Errorf("параметр %s.%s: %w", section, key, err)
+			}
+		}
+	}
+
+	return src, nil
+}
+
+// formatFileValue приводит значение из YAML к строке в формате переменных
+// окружения. Списки объединяются через запятую
+func formatFileValue(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			formatted, err := formatFileValue(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, formatted)
+		}
+		return strings.Join(items, ","), nil
+	case map[string]any:
+		return "", fmt.Errorf("вложенные секции не поддерживаются")
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
+
+// get возвращает значение параметра с учетом приоритета источников
+func (s *source) get(key, defaultValue string) string {
+	result := setting{value: defaultValue, source: SourceDefault}
+	if value, ok := s.file[key]; ok && value != "" {
+		result = setting{value: value, source: SourceFile}
+	}
+	if value := os.Getenv(key); value != "" {
+		result = setting{value: value, source: SourceEnv}
+	}
+
+	s.settings[key] = result
+	return result.value
+}
+
+// invalidValue отмечает параметр с неверным значением, вместо которого
+// используется значение по умолчанию
+func (s *source) invalidValue(key string, err error) {
+	log.Printf("Неверное значение %s, используется значение по умолчанию: %v", key, err)
+	s.invalid = append(s.invalid, key)
+}
+
+// unknownKeys возвращает параметры из файла, которые не читаются конфигурацией
+func (s *source) unknownKeys() []string {
+	var unknown []string
+	for key := range s.file {
+		if _, ok := s.settings[key]; !ok {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// isSecret сообщает, что значение параметра нельзя показывать
+func isSecret(key string) bool {
+	for _, marker := range []string{"PASSWORD", "SECRET", "TOKEN"} {
+		if strings.Contains(key, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// Print выводит итоговую конфигурацию в формате файла конфигурации с
+// источником каждого значения. Секреты заменяются на [REDACTED]
+func (c *Config) Print(w io.Writer) error {
+	keys := make([]string, 0, len(c.settings))
+	for key := range c.settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if c.file != "" {
+		fmt.Fprintf(w, "# файл конфигурации: %s\n", c.file)
+	}
+
+	section := ""
+	for _, key := range keys {
+		name, param, _ := strings.Cut(strings.ToLower(key), "_")
+		if name != section {
+			section = name
+			fmt.Fprintf(w, "%s:\n", section)
+		}
+
+		current := c.settings[key]
+		value := current.value
+		if isSecret(key) && value != "" {
+			value = "[REDACTED]"
+		}
+
+		formatted, err := yaml.Marshal(value)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "  %s: %s # %s\n", param, strings.TrimSpace(string(formatted)), current.source)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
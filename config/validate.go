@@ -0,0 +1,137 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// минимальная длина секретов в production
+const minSecretLength = 32
+
+// IsProduction сообщает, что приложение запущено в production
+func (c *Config) IsProduction() bool {
+	return c.App.Environment == EnvironmentProduction
+}
+
+// Validate проверяет согласованность настроек. В production дополнительно
+// запрещены небезопасные значения: секрет по умолчанию, тестовый вход,
+// соединение с БД без TLS и параметры с неверными значениями
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	switch c.App.Environment {
+	case EnvironmentDevelopment, EnvironmentStaging, EnvironmentProduction:
+	default:
+		check(false, "APP_ENVIRONMENT: неизвестное окружение %q", c.App.Environment)
+	}
+	check(oneOf(c.App.LogLevel, "debug", "info", "warn", "error"),
+		"APP_LOG_LEVEL: неизвестный уровень %q", c.App.LogLevel)
+	check(oneOf(c.Log.Format, "json", "text"), "LOG_FORMAT: неизвестный формат %q", c.Log.Format)
+
+	check(validPort(c.HTTP.Port), "HTTP_PORT: неверный порт %q", c.HTTP.Port)
+	check(validPort(c.GRPC.Port), "GRPC_PORT: неверный порт %q", c.GRPC.Port)
+	check(validPort(c.Metrics.Port), "METRICS_PORT: неверный порт %q", c.Metrics.Port)
+	check(c.HTTP.Port != c.Metrics.Port, "HTTP_PORT и METRICS_PORT совпадают")
+	check(c.HTTP.Timeout > 0, "HTTP_TIMEOUT должен быть больше 0")
+
+	check(c.Postgres.MaxOpenConns > 0, "POSTGRES_MAX_OPEN_CONNS должен быть больше 0")
+	check(c.Postgres.MaxIdleConns <= c.Postgres.MaxOpenConns,
+		"POSTGRES_MAX_IDLE_CONNS (%d) больше POSTGRES_MAX_OPEN_CONNS (%d)",
+		c.Postgres.MaxIdleConns, c.Postgres.MaxOpenConns)
+
+	check(c.Postgres.ConnectAttempts > 0, "POSTGRES_CONNECT_ATTEMPTS должен быть больше 0")
+	check(c.Postgres.ConnectBackoff <= c.Postgres.ConnectMaxBackoff,
+		"POSTGRES_CONNECT_BACKOFF больше POSTGRES_CONNECT_MAX_BACKOFF")
+	check(c.Postgres.RetryAttempts > 0, "POSTGRES_RETRY_ATTEMPTS должен быть больше 0")
+	check(c.Postgres.RetryBackoff <= c.Postgres.RetryMaxBackoff,
+		"POSTGRES_RETRY_BACKOFF больше POSTGRES_RETRY_MAX_BACKOFF")
+
+	check(c.Auth.TokenTTL > 0, "TOKEN_TTL должен быть больше 0")
+	check(c.Auth.JWTSecret != "", "JWT_SECRET не задан")
+
+	check(oneOf(c.Tracing.Exporter, "none", "stdout", "otlp"),
+		"TRACING_EXPORTER: неизвестный экспортер %q", c.Tracing.Exporter)
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1,
+		"TRACING_SAMPLE_RATIO должен быть от 0 до 1")
+
+	check(c.SLO.LatencyThreshold > 0, "SLO_LATENCY_THRESHOLD должен быть больше 0")
+	check(c.SLO.LatencyTarget > 0 && c.SLO.LatencyTarget < 1, "SLO_LATENCY_TARGET должен быть между 0 и 1")
+	check(c.SLO.AvailabilityTarget > 0 && c.SLO.AvailabilityTarget < 1,
+		"SLO_AVAILABILITY_TARGET должен быть между 0 и 1")
+
+	check(oneOf(c.Reception.StaleAction, StaleActionClose, StaleActionFlag),
+		"RECEPTION_STALE_ACTION: неизвестное действие %q", c.Reception.StaleAction)
+	check(c.Reception.StaleAfter > 0, "RECEPTION_STALE_AFTER должен быть больше 0")
+	check(c.Reception.StaleCheckInterval > 0, "RECEPTION_STALE_CHECK_INTERVAL должен быть больше 0")
+
+	check(!c.Cache.PVZEnabled || c.Cache.PVZSize > 0, "CACHE_PVZ_SIZE должен быть больше 0")
+	check(!c.Cache.PVZEnabled || c.Cache.PVZTTL > 0, "CACHE_PVZ_TTL должен быть больше 0")
+
+	check(oneOf(c.Outbox.Publisher, OutboxPublisherMemory, OutboxPublisherFile, OutboxPublisherKafka),
+		"OUTBOX_PUBLISHER: неизвестный способ публикации %q", c.Outbox.Publisher)
+	check(c.Outbox.PollInterval > 0, "OUTBOX_POLL_INTERVAL должен быть больше 0")
+	check(c.Outbox.BatchSize > 0, "OUTBOX_BATCH_SIZE должен быть больше 0")
+	check(c.Outbox.Retention >= 0, "OUTBOX_RETENTION не может быть отрицательным")
+	check(c.Outbox.Publisher != OutboxPublisherFile || c.Outbox.FilePath != "",
+		"OUTBOX_FILE_PATH обязателен для OUTBOX_PUBLISHER=file")
+	check(c.Outbox.Publisher != OutboxPublisherKafka || len(c.Outbox.KafkaBrokers) > 0,
+		"OUTBOX_KAFKA_BROKERS обязателен для OUTBOX_PUBLISHER=kafka")
+	check(c.Outbox.Publisher != OutboxPublisherKafka || c.Outbox.KafkaTopic != "",
+		"OUTBOX_KAFKA_TOPIC обязателен для OUTBOX_PUBLISHER=kafka")
+
+	if c.Webhook.Enabled {
+		// доставки создает задача публикации outbox
+		check(c.Outbox.RelayEnabled, "WEBHOOK_ENABLED требует OUTBOX_RELAY_ENABLED")
+		check(c.Webhook.PollInterval > 0, "WEBHOOK_POLL_INTERVAL должен быть больше 0")
+		check(c.Webhook.BatchSize > 0, "WEBHOOK_BATCH_SIZE должен быть больше 0")
+		check(c.Webhook.Timeout > 0, "WEBHOOK_TIMEOUT должен быть больше 0")
+		check(c.Webhook.MaxAttempts > 0, "WEBHOOK_MAX_ATTEMPTS должен быть больше 0")
+		check(c.Webhook.BackoffBase > 0, "WEBHOOK_BACKOFF_BASE должен быть больше 0")
+		check(c.Webhook.BackoffMax >= c.Webhook.BackoffBase,
+			"WEBHOOK_BACKOFF_MAX должен быть не меньше WEBHOOK_BACKOFF_BASE")
+	}
+
+	check(c.Shutdown.Timeout > 0, "SHUTDOWN_TIMEOUT должен быть больше 0")
+	check(c.Shutdown.PreStopDelay >= 0, "SHUTDOWN_PRE_STOP_DELAY не может быть отрицательным")
+	check(c.Shutdown.PreStopDelay < c.Shutdown.Timeout,
+		"SHUTDOWN_PRE_STOP_DELAY должен быть меньше SHUTDOWN_TIMEOUT")
+
+	check(!c.Metrics.DebugEnabled || c.Metrics.AdminToken != "",
+		"METRICS_DEBUG_ENABLED требует METRICS_ADMIN_TOKEN")
+
+	if c.IsProduction() {
+		check(len(c.invalid) == 0, "неверные значения параметров: %s", strings.Join(c.invalid, ", "))
+		check(c.Auth.JWTSecret != DefaultJWTSecret, "JWT_SECRET: в production нельзя использовать значение по умолчанию")
+		check(len(c.Auth.JWTSecret) >= minSecretLength,
+			"JWT_SECRET: в production нужен секрет не короче %d символов", minSecretLength)
+		check(!c.Auth.DummyLoginEnabled, "AUTH_DUMMY_LOGIN_ENABLED: тестовый вход запрещен в production")
+		check(c.Postgres.SSLMode != "disable", "POSTGRES_SSLMODE: в production соединение с БД должно использовать TLS")
+		check(c.Tracing.Exporter != "stdout", "TRACING_EXPORTER: stdout не подходит для production")
+		// токен открывает и отладку, и смену уровней логирования через /log/level
+		check((!c.Metrics.DebugEnabled && c.Metrics.AdminToken == "") || len(c.Metrics.AdminToken) >= minSecretLength,
+			"METRICS_ADMIN_TOKEN: в production нужен токен не короче %d символов", minSecretLength)
+	}
+
+	return errors.Join(errs...)
+}
+
+func oneOf(value string, allowed ...string) bool {
+	for _, item := range allowed {
+		if strings.EqualFold(value, item) {
+			return true
+		}
+	}
+	return false
+}
+
+func validPort(value string) bool {
+	port, err := strconv.Atoi(value)
+	return err == nil && port > 0 && port < 65536
+}
@@ -4,15 +4,17 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 	"time"
 
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/grpc/pb"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 )
 
 func main() {
-	addr := "localhost:50051"
+	addr := "localhost:3000"
 	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
 		log.Fatalf("Failed to connect: %v", err)
@@ -24,6 +26,9 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	// токен пользователя с правом pvz:read (например, полученный через /dummyLogin)
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+os.Getenv("PVZ_TOKEN"))
+
 	resp, err := client.GetPVZList(ctx, &pb.GetPVZListRequest{})
 	if err != nil {
 		log.Fatalf("Failed to get PVZ list: %v", err)
@@ -6,124 +6,85 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/dkumancev/avito-pvz/internal/api/response"
+	"github.com/dkumancev/avito-pvz/pkg/application/auth"
 	"github.com/dkumancev/avito-pvz/pkg/domain"
-	"github.com/golang-jwt/jwt/v5"
-)
-
-type contextKey string
-
-const (
-	userContextKey contextKey = "user"
 )
 
 func GetUserFromContext(ctx context.Context) (*domain.User, error) {
-	user, ok := ctx.Value(userContextKey).(*domain.User)
+	user, ok := auth.UserFromContext(ctx)
 	if !ok {
 		return nil, errors.New("пользователь не найден в контексте")
 	}
 	return user, nil
 }
 
-func AuthMiddleware(jwtSecret []byte, next http.Handler) http.Handler {
+// AuthMiddleware аутентифицирует запрос по JWT токену пользователя или,
+// если передан заголовок X-API-Key, по API-ключу внешней системы
+func AuthMiddleware(tokens *auth.TokenParser, apiKeys auth.APIKeyAuthenticator, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			http.Error(w, `{"message":"Отсутствует токен авторизации"}`, http.StatusUnauthorized)
-			return
-		}
-
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			http.Error(w, `{"message":"Неверный формат токена авторизации"}`, http.StatusUnauthorized)
-			return
-		}
-
-		tokenString := parts[1]
-
-		// Парсим токен
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, errors.New("неподдерживаемый метод подписи")
+		if apiKey := r.Header.Get("X-API-Key"); apiKey != "" && apiKeys != nil {
+			principal, err := apiKeys.Authenticate(r.Context(), apiKey)
+			if err != nil {
+				// сбой проверки - не неудачная попытка входа: 401 учитывается ограничением перебора
+				if !errors.Is(err, auth.ErrInvalidAPIKey) {
+					response.Error(w, r, http.StatusInternalServerError, "Ошибка проверки API-ключа")
+					return
+				}
+				response.Error(w, r, http.StatusUnauthorized, "Недействительный API-ключ")
+				return
 			}
-			return jwtSecret, nil
-		})
-
-		if err != nil {
-			http.Error(w, `{"message":"Недействительный токен"}`, http.StatusUnauthorized)
-			return
-		}
-
-		if !token.Valid {
-			http.Error(w, `{"message":"Недействительный токен"}`, http.StatusUnauthorized)
-			return
-		}
 
-		// Извлекаем данные пользователя из токена
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			http.Error(w, `{"message":"Недействительный токен"}`, http.StatusUnauthorized)
+			next.ServeHTTP(w, r.WithContext(auth.WithServicePrincipal(r.Context(), principal)))
 			return
 		}
 
-		userID, ok := claims["id"].(string)
-		if !ok {
-			http.Error(w, `{"message":"Недействительный токен"}`, http.StatusUnauthorized)
-			return
-		}
-
-		email, ok := claims["email"].(string)
-		if !ok {
-			http.Error(w, `{"message":"Недействительный токен"}`, http.StatusUnauthorized)
+		authHeader := r.Header.Get("Authorization")
+		if authHeader == "" {
+			response.Error(w, r, http.StatusUnauthorized, "Отсутствует токен авторизации")
 			return
 		}
 
-		roleStr, ok := claims["role"].(string)
-		if !ok {
-			http.Error(w, `{"message":"Недействительный токен"}`, http.StatusUnauthorized)
+		parts := strings.Split(authHeader, " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			response.Error(w, r, http.StatusUnauthorized, "Неверный формат токена авторизации")
 			return
 		}
 
-		var role domain.UserRole
-		switch roleStr {
-		case string(domain.EmployeeRole):
-			role = domain.EmployeeRole
-		case string(domain.ModeratorRole):
-			role = domain.ModeratorRole
-		default:
-			http.Error(w, `{"message":"Неизвестная роль пользователя"}`, http.StatusUnauthorized)
+		// Парсим токен и сохраняем данные пользователя в контексте
+		ctx, err := tokens.Authenticate(r.Context(), parts[1])
+		if err != nil {
+			if errors.Is(err, auth.ErrUnknownRole) {
+				response.Error(w, r, http.StatusUnauthorized, "Неизвестная роль пользователя")
+				return
+			}
+			if errors.Is(err, auth.ErrDummyToken) {
+				response.Error(w, r, http.StatusUnauthorized, "Тестовые токены отключены")
+				return
+			}
+			response.Error(w, r, http.StatusUnauthorized, "Недействительный токен")
 			return
 		}
 
-		user := &domain.User{
-			ID:    userID,
-			Email: email,
-			Role:  role,
-		}
-
-		ctx := context.WithValue(r.Context(), userContextKey, user)
-
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
-func RoleMiddleware(allowedRoles []domain.UserRole, next http.Handler) http.Handler {
+// RequirePermission пропускает запрос, только если у роли пользователя
+// или у API-ключа есть указанное право
+func RequirePermission(policy *auth.Policy, permission auth.Permission, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		user, err := GetUserFromContext(r.Context())
-		if err != nil {
-			http.Error(w, `{"message":"Ошибка авторизации"}`, http.StatusUnauthorized)
+		err := auth.Authorize(r.Context(), policy, permission)
+		if errors.Is(err, auth.ErrUnauthenticated) {
+			response.Error(w, r, http.StatusUnauthorized, "Ошибка авторизации")
 			return
 		}
-
-		allowed := false
-		for _, role := range allowedRoles {
-			if user.Role == role {
-				allowed = true
-				break
-			}
+		if errors.Is(err, auth.ErrTwoFactorRequired) {
+			response.Error(w, r, http.StatusForbidden, "Для вашей роли требуется двухфакторная аутентификация")
+			return
 		}
-
-		if !allowed {
-			http.Error(w, `{"message":"Недостаточно прав для выполнения операции"}`, http.StatusForbidden)
+		if err != nil {
+			response.Error(w, r, http.StatusForbidden, "Недостаточно прав для выполнения операции")
 			return
 		}
 
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 	"strings"
@@ -37,7 +38,7 @@ func LoggerMiddleware(log *slog.Logger) func(http.Handler) http.Handler {
 			path := r.URL.Path
 			query := sanitizeQuery(r.URL.RawQuery)
 
-			log.Debug("Получен HTTP запрос",
+			log.DebugContext(r.Context(), "Получен HTTP запрос",
 				"method", r.Method,
 				"path", path,
 				"query", query,
@@ -50,18 +51,18 @@ func LoggerMiddleware(log *slog.Logger) func(http.Handler) http.Handler {
 			duration := time.Since(start)
 
 			// уровень логирования в зависимости от статус-кода
-			var logFunc func(msg string, args ...any)
+			var logFunc func(ctx context.Context, msg string, args ...any)
 			switch {
 			case wrapped.statusCode >= 500:
-				logFunc = log.Error
+				logFunc = log.ErrorContext
 			case wrapped.statusCode >= 400:
-				logFunc = log.Warn
+				logFunc = log.WarnContext
 			default:
-				logFunc = log.Info
+				logFunc = log.InfoContext
 			}
 
 			// Логируем результат запроса
-			logFunc("Завершен HTTP запрос",
+			logFunc(r.Context(), "Завершен HTTP запрос",
 				"method", r.Method,
 				"path", path,
 				"query", query,
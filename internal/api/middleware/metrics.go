@@ -6,13 +6,34 @@ import (
 	"time"
 
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/metrics"
+	"github.com/gorilla/mux"
 )
 
+// UnmatchedRoute значение метки для запросов, не совпавших ни с одним маршрутом
+const UnmatchedRoute = "unmatched"
+
+// OtherMethod значение метки для нестандартных HTTP методов
+const OtherMethod = "OTHER"
+
+// knownMethods - методы, которые попадают в метку как есть
+var knownMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+}
+
 func MetricsMiddleware(m *metrics.HTTPMetrics) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
 
+			m.RequestsInFlight.Inc()
+			defer m.RequestsInFlight.Dec()
+
 			wrapped := &responseWriter{
 				ResponseWriter: w,
 				statusCode:     http.StatusOK,
@@ -20,20 +41,49 @@ func MetricsMiddleware(m *metrics.HTTPMetrics) func(http.Handler) http.Handler {
 
 			next.ServeHTTP(wrapped, r)
 
-			duration := time.Since(start).Seconds()
+			elapsed := time.Since(start)
+			duration := elapsed.Seconds()
 
-			path := normalizePath(r.URL.Path)
+			path := routeTemplate(r)
+			method := methodLabel(r.Method)
 
 			status := strconv.Itoa(wrapped.statusCode)
 
-			m.RequestsTotal.WithLabelValues(r.Method, path, status).Inc()
+			m.RequestsTotal.WithLabelValues(method, path, status).Inc()
+
+			m.RequestDuration.WithLabelValues(method, path, status).Observe(duration)
 
-			m.RequestDuration.WithLabelValues(r.Method, path, status).Observe(duration)
+			m.ResponseSize.WithLabelValues(method, path, status).Observe(float64(wrapped.size))
+
+			// несовпавшие маршруты не относятся к API и не расходуют бюджет ошибок
+			if m.SLO != nil && path != UnmatchedRoute {
+				m.SLO.Observe(method, path, wrapped.statusCode, elapsed)
+			}
 		})
 	}
 }
 
-func normalizePath(path string) string {
-	// простая реализация, которую можно расширить для конкретных маршрутов
-	return path
+// methodLabel сводит нестандартные методы к одной метке: клиент может прислать
+// любой токен в качестве метода, и каждый порождал бы новые серии метрик
+func methodLabel(method string) string {
+	if knownMethods[method] {
+		return method
+	}
+	return OtherMethod
+}
+
+// routeTemplate возвращает шаблон маршрута mux (например, /pvz/{pvzId}/close_last_reception),
+// чтобы идентификаторы из пути не порождали новые серии метрик
+func routeTemplate(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return UnmatchedRoute
+	}
+
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return UnmatchedRoute
+	}
+
+	return template
 }
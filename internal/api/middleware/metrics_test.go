@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/config"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/metrics"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// newTestHTTPMetrics создает метрики без регистрации в глобальном реестре
+func newTestHTTPMetrics() *metrics.HTTPMetrics {
+	labels := []string{"method", "path", "status"}
+	return &metrics.HTTPMetrics{
+		RequestsTotal:    prometheus.NewCounterVec(prometheus.CounterOpts{Name: "requests_total"}, labels),
+		RequestDuration:  prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "request_duration"}, labels),
+		RequestsInFlight: prometheus.NewGauge(prometheus.GaugeOpts{Name: "in_flight"}),
+		ResponseSize:     prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "response_size"}, labels),
+	}
+}
+
+func TestMetricsMiddleware_RouteTemplateLabels(t *testing.T) {
+	m := newTestHTTPMetrics()
+
+	router := mux.NewRouter()
+	router.Use(MetricsMiddleware(m))
+	router.NotFoundHandler = MetricsMiddleware(m)(http.NotFoundHandler())
+	router.MethodNotAllowedHandler = MetricsMiddleware(m)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	router.HandleFunc("/pvz/{pvzId}/close_last_reception", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"close"}`))
+	}).Methods(http.MethodPost)
+
+	requests := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodPost, "/pvz/11111111-1111-1111-1111-111111111111/close_last_reception"},
+		{http.MethodPost, "/pvz/22222222-2222-2222-2222-222222222222/close_last_reception"},
+		{http.MethodGet, "/pvz/33333333-3333-3333-3333-333333333333/close_last_reception"},
+		{http.MethodGet, "/unknown/1"},
+		{http.MethodGet, "/unknown/2"},
+	}
+	for _, req := range requests {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(req.method, req.path, nil))
+	}
+
+	// разные ID в пути дают одну серию
+	if got := testutil.ToFloat64(m.RequestsTotal.WithLabelValues(http.MethodPost, "/pvz/{pvzId}/close_last_reception", "200")); got != 2 {
+		t.Errorf("Ожидалось 2 запроса по шаблону маршрута, получено %v", got)
+	}
+
+	// 404 и 405 сводятся к одной метке
+	if got := testutil.ToFloat64(m.RequestsTotal.WithLabelValues(http.MethodGet, UnmatchedRoute, "404")); got != 2 {
+		t.Errorf("Ожидалось 2 несовпавших запроса, получено %v", got)
+	}
+	if got := testutil.ToFloat64(m.RequestsTotal.WithLabelValues(http.MethodGet, UnmatchedRoute, "405")); got != 1 {
+		t.Errorf("Ожидался 1 запрос с неподдерживаемым методом, получено %v", got)
+	}
+
+	if got := testutil.CollectAndCount(m.RequestsTotal); got != 3 {
+		t.Errorf("Ожидалось 3 серии метрики, получено %d", got)
+	}
+
+	if got := testutil.ToFloat64(m.RequestsInFlight); got != 0 {
+		t.Errorf("После завершения запросов счетчик активных должен быть 0, получено %v", got)
+	}
+}
+
+func TestMetricsMiddleware_UnknownMethodsShareLabel(t *testing.T) {
+	m := newTestHTTPMetrics()
+
+	router := mux.NewRouter()
+	router.Use(MetricsMiddleware(m))
+	router.NotFoundHandler = MetricsMiddleware(m)(http.NotFoundHandler())
+	router.MethodNotAllowedHandler = MetricsMiddleware(m)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	router.HandleFunc("/pvz", func(w http.ResponseWriter, r *http.Request) {}).Methods(http.MethodGet)
+
+	for _, method := range []string{"FOO1", "FOO2", "FOO3"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/pvz", nil))
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/unknown", nil))
+	}
+
+	if got := testutil.ToFloat64(m.RequestsTotal.WithLabelValues(OtherMethod, UnmatchedRoute, "405")); got != 3 {
+		t.Errorf("Ожидалось 3 запроса с нестандартным методом к существующему маршруту, получено %v", got)
+	}
+	if got := testutil.ToFloat64(m.RequestsTotal.WithLabelValues(OtherMethod, UnmatchedRoute, "404")); got != 3 {
+		t.Errorf("Ожидалось 3 запроса с нестандартным методом к неизвестному маршруту, получено %v", got)
+	}
+	if got := testutil.CollectAndCount(m.RequestsTotal); got != 2 {
+		t.Errorf("Нестандартные методы не должны порождать новые серии, получено %d", got)
+	}
+}
+
+func TestMetricsMiddleware_ObservesSLO(t *testing.T) {
+	m := newTestHTTPMetrics()
+	m.SLO = metrics.NewSLOTracker(config.SLOConfig{
+		LatencyThreshold:   100 * time.Millisecond,
+		LatencyTarget:      0.99,
+		AvailabilityTarget: 0.9999,
+		Windows:            []time.Duration{5 * time.Minute},
+	})
+
+	router := mux.NewRouter()
+	router.Use(MetricsMiddleware(m))
+	router.NotFoundHandler = MetricsMiddleware(m)(http.NotFoundHandler())
+	router.HandleFunc("/pvz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}).Methods(http.MethodGet)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/pvz", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unknown", nil))
+
+	report := m.SLO.Report()
+	if len(report.Routes) != 1 {
+		t.Fatalf("Несовпавшие маршруты не должны учитываться в SLO, получено %+v", report.Routes)
+	}
+	window := report.Routes[0].Windows[0]
+	if report.Routes[0].Path != "/pvz" || window.Requests != 1 || window.Errors != 1 {
+		t.Errorf("Ожидалась одна ошибка по /pvz, получено %+v", report.Routes[0])
+	}
+}
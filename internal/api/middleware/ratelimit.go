@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/internal/api/response"
+	"github.com/dkumancev/avito-pvz/pkg/application/metrics"
+	"github.com/dkumancev/avito-pvz/pkg/application/ratelimit"
+)
+
+// RateLimitMiddleware ограничивает частоту запросов к маршруту. Аутентифицированные
+// запросы считаются по пользователю или API-ключу, поэтому middleware ставится
+// после AuthMiddleware; остальные - по IP клиента. X-Forwarded-For учитывается,
+// только если trustForwardedFor: иначе клиент может подставить любой адрес
+func RateLimitMiddleware(limiter *ratelimit.Limiter, m metrics.RateLimitMetrics, trustForwardedFor bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := r.Method + " " + routeTemplate(r)
+			subject := ratelimit.SubjectFromContext(r.Context(), clientIP(r, trustForwardedFor))
+
+			decision := limiter.Allow(route, subject)
+			if decision.Limit.Unlimited() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// заголовки по draft-ietf-httpapi-ratelimit-headers
+			header := w.Header()
+			header.Set("RateLimit-Limit", strconv.Itoa(decision.Limit.Requests))
+			header.Set("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+			header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(decision.Reset)))
+			header.Set("RateLimit-Policy", strconv.Itoa(decision.Limit.Requests)+";w="+
+				strconv.Itoa(ceilSeconds(decision.Limit.Period)))
+
+			if !decision.Allowed {
+				m.Throttled("http", route, string(subject.Kind))
+				header.Set("Retry-After", strconv.Itoa(max(ceilSeconds(decision.RetryAfter), 1)))
+				response.Error(w, r, http.StatusTooManyRequests, "Слишком много запросов, повторите позже")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// authFailuresRoute - корзина неудачных попыток аутентификации, общая для
+// всех маршрутов
+const authFailuresRoute = "auth_failures"
+
+// AuthFailureLimitMiddleware ограничивает по IP клиента число запросов с
+// неверным токеном или API-ключом. Ставится перед AuthMiddleware: до проверки
+// учетных данных вызывающая сторона известна только по адресу. Списываются
+// только ответы 401, поэтому клиенты с действующими учетными данными лимит
+// не расходуют, а перебор после его исчерпания отклоняется без проверки
+func AuthFailureLimitMiddleware(limiter *ratelimit.Limiter, m metrics.RateLimitMetrics, trustForwardedFor bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			subject := ratelimit.Subject{Kind: ratelimit.SubjectIP, ID: clientIP(r, trustForwardedFor)}
+
+			decision := limiter.Check(authFailuresRoute, subject)
+			if decision.Limit.Unlimited() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !decision.Allowed {
+				m.Throttled("http", authFailuresRoute, string(subject.Kind))
+				w.Header().Set("Retry-After", strconv.Itoa(max(ceilSeconds(decision.RetryAfter), 1)))
+				response.Error(w, r, http.StatusTooManyRequests, "Слишком много неудачных попыток аутентификации, повторите позже")
+				return
+			}
+
+			wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(wrapped, r)
+
+			if wrapped.statusCode == http.StatusUnauthorized {
+				limiter.Allow(authFailuresRoute, subject)
+			}
+		})
+	}
+}
+
+// clientIP возвращает IP клиента. За прокси адрес клиента - последний в
+// X-Forwarded-For: его дописал ближайший прокси, остальные мог подставить клиент
+func clientIP(r *http.Request, trustForwardedFor bool) string {
+	if trustForwardedFor {
+		forwarded := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+		if ip := strings.TrimSpace(forwarded[len(forwarded)-1]); ip != "" {
+			return ip
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/auth"
+	"github.com/dkumancev/avito-pvz/pkg/application/ratelimit"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/gorilla/mux"
+)
+
+type fakeRateLimitMetrics struct {
+	throttled map[string]int
+}
+
+func (m *fakeRateLimitMetrics) Throttled(transport, route, subject string) {
+	m.throttled[transport+" "+route+" "+subject]++
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.Config{
+		Default: ratelimit.Limit{Requests: 100, Period: time.Second},
+		Public:  ratelimit.Limit{Requests: 1, Period: time.Minute},
+		Routes: map[string]ratelimit.Limit{
+			"POST /products": {Requests: 2, Period: time.Second},
+		},
+	})
+	m := &fakeRateLimitMetrics{throttled: make(map[string]int)}
+	limited := RateLimitMiddleware(limiter, m, true)
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	// пользователь подставляется так же, как это делает AuthMiddleware
+	withUser := func(id string, next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := auth.WithUser(r.Context(), &domain.User{ID: id, Role: domain.EmployeeRole})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+
+	router := mux.NewRouter()
+	router.Handle("/products", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		withUser(r.Header.Get("X-Test-User"), limited(ok)).ServeHTTP(w, r)
+	})).Methods(http.MethodPost)
+	router.Handle("/login", limited(ok)).Methods(http.MethodPost)
+
+	send := func(path, user, forwardedFor string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set("X-Test-User", user)
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// ограничение маршрута считается по пользователю
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		rec := send("/products", "u1", "")
+		if rec.Code != want {
+			t.Fatalf("Запрос %d: ожидался статус %d, получен %d", i, want, rec.Code)
+		}
+	}
+	if rec := send("/products", "u2", ""); rec.Code != http.StatusOK {
+		t.Errorf("У другого пользователя свое ограничение, получен статус %d", rec.Code)
+	}
+
+	rec := send("/products", "u1", "")
+	if rec.Header().Get("Retry-After") != "1" {
+		t.Errorf("Неверный Retry-After: %q", rec.Header().Get("Retry-After"))
+	}
+	if rec.Header().Get("RateLimit-Limit") != "2" || rec.Header().Get("RateLimit-Remaining") != "0" {
+		t.Errorf("Неверные заголовки RateLimit: %v", rec.Header())
+	}
+	if rec.Header().Get("RateLimit-Policy") != "2;w=1" {
+		t.Errorf("Неверный RateLimit-Policy: %q", rec.Header().Get("RateLimit-Policy"))
+	}
+
+	// публичный маршрут считается по IP клиента из X-Forwarded-For
+	if rec := send("/login", "", "203.0.113.1, 10.0.0.1"); rec.Code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", rec.Code)
+	}
+	rec = send("/login", "", "203.0.113.2, 10.0.0.1")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("Адрес клиента - последний в X-Forwarded-For, ожидался статус 429, получен %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") != "60" {
+		t.Errorf("Неверный Retry-After: %q", rec.Header().Get("Retry-After"))
+	}
+	if rec := send("/login", "", "10.0.0.2"); rec.Code != http.StatusOK {
+		t.Errorf("У другого IP свое ограничение, получен статус %d", rec.Code)
+	}
+
+	if m.throttled["http POST /products user"] != 2 || m.throttled["http POST /login ip"] != 1 {
+		t.Errorf("Неверные метрики отклоненных запросов: %v", m.throttled)
+	}
+}
+
+func TestAuthFailureLimitMiddleware(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.Config{
+		Public: ratelimit.Limit{Requests: 2, Period: time.Minute},
+	})
+	m := &fakeRateLimitMetrics{throttled: make(map[string]int)}
+	limited := AuthFailureLimitMiddleware(limiter, m, false)
+
+	handler := limited(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer valid" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	send := func(token, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/pvz", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// успешные запросы лимит не расходуют
+	for i := 0; i < 5; i++ {
+		if rec := send("valid", "10.0.0.1:1234"); rec.Code != http.StatusOK {
+			t.Fatalf("Запрос %d: ожидался статус 200, получен %d", i, rec.Code)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		if rec := send("guessed", "10.0.0.1:1234"); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("Попытка %d: ожидался статус 401, получен %d", i, rec.Code)
+		}
+	}
+
+	// после исчерпания лимита адрес отклоняется даже с верным токеном
+	rec := send("valid", "10.0.0.1:1234")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("Ожидался статус 429, получен %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") != "30" {
+		t.Errorf("Неверный Retry-After: %q", rec.Header().Get("Retry-After"))
+	}
+	if rec := send("guessed", "10.0.0.2:1234"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("У другого IP свое ограничение, получен статус %d", rec.Code)
+	}
+
+	if m.throttled["http auth_failures ip"] != 1 {
+		t.Errorf("Неверные метрики отклоненных запросов: %v", m.throttled)
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "192.0.2.10:51234"
+	req.Header.Set("X-Forwarded-For", "203.0.113.1")
+
+	if ip := clientIP(req, false); ip != "192.0.2.10" {
+		t.Errorf("Без доверия к прокси ожидался адрес соединения, получен %q", ip)
+	}
+	if ip := clientIP(req, true); ip != "203.0.113.1" {
+		t.Errorf("Ожидался адрес из X-Forwarded-For, получен %q", ip)
+	}
+}
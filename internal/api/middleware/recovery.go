@@ -5,6 +5,8 @@ import (
 	"log/slog"
 	"net/http"
 	"runtime/debug"
+
+	"github.com/dkumancev/avito-pvz/internal/api/response"
 )
 
 // RecoveryMiddleware - middleware для восстановления после паники
@@ -17,15 +19,13 @@ func RecoveryMiddleware(log *slog.Logger) func(http.Handler) http.Handler {
 					stack := debug.Stack()
 
 					// Логируем ошибку с контекстом HTTP запроса
-					log.Error("PANIC RECOVERED",
+					log.ErrorContext(r.Context(), "PANIC RECOVERED",
 						"error", fmt.Sprintf("%v", err),
 						"path", r.URL.Path,
 						"method", r.Method,
 						"stack", string(stack))
 
-					w.Header().Set("Content-Type", "application/json")
-					w.WriteHeader(http.StatusInternalServerError)
-					w.Write([]byte(`{"error": "Internal server error"}`))
+					response.Error(w, r, http.StatusInternalServerError, "Внутренняя ошибка сервера")
 				}
 			}()
 
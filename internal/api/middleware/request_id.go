@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/requestctx"
+	"github.com/gorilla/mux"
+)
+
+// RequestIDHeader - заголовок с идентификатором запроса
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDMiddleware берет идентификатор запроса из заголовка X-Request-ID
+// или генерирует новый, сохраняет его в контексте и возвращает в ответе.
+// ПВЗ из пути запроса сразу попадает в сведения о запросе для логов
+func RequestIDMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(RequestIDHeader)
+			if !requestctx.IsValidID(requestID) {
+				requestID = requestctx.NewID()
+			}
+
+			ctx := requestctx.New(r.Context(), requestID)
+			if pvzID := mux.Vars(r)["pvzId"]; pvzID != "" {
+				requestctx.SetPVZID(ctx, pvzID)
+			}
+
+			w.Header().Set(RequestIDHeader, requestID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dkumancev/avito-pvz/internal/api/response"
+	"github.com/dkumancev/avito-pvz/pkg/application/requestctx"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRequestIDRouter(t *testing.T, seen *requestctx.Values) *mux.Router {
+	t.Helper()
+
+	router := mux.NewRouter()
+	router.Use(RequestIDMiddleware())
+	router.HandleFunc("/pvz/{pvzId}/close_last_reception", func(w http.ResponseWriter, r *http.Request) {
+		*seen, _ = requestctx.FromContext(r.Context())
+		response.Error(w, r, http.StatusBadRequest, "нет активной приемки")
+	}).Methods(http.MethodPost)
+
+	return router
+}
+
+func TestRequestIDMiddleware_AcceptsClientID(t *testing.T) {
+	var seen requestctx.Values
+	router := newRequestIDRouter(t, &seen)
+
+	req := httptest.NewRequest(http.MethodPost, "/pvz/pvz-1/close_last_reception", nil)
+	req.Header.Set(RequestIDHeader, "client-request-1")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, "client-request-1", rec.Header().Get(RequestIDHeader))
+	assert.Equal(t, "client-request-1", seen.RequestID)
+	assert.Equal(t, "pvz-1", seen.PVZID)
+
+	var body response.ErrorResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	assert.Equal(t, "нет активной приемки", body.Message)
+	assert.Equal(t, "client-request-1", body.RequestID)
+}
+
+func TestRequestIDMiddleware_GeneratesID(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{name: "без заголовка", header: ""},
+		{name: "недопустимые символы", header: "id with spaces\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen requestctx.Values
+			router := newRequestIDRouter(t, &seen)
+
+			req := httptest.NewRequest(http.MethodPost, "/pvz/pvz-1/close_last_reception", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			requestID := rec.Header().Get(RequestIDHeader)
+			assert.True(t, requestctx.IsValidID(requestID))
+			assert.NotEqual(t, tt.header, requestID)
+			assert.Equal(t, requestID, seen.RequestID)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/dkumancev/avito-pvz/internal/api"
+
+// TracingMiddleware начинает серверный span запроса, продолжая трассу из заголовка traceparent
+func TracingMiddleware() func(http.Handler) http.Handler {
+	tracer := otel.Tracer(tracerName)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+			route := routeTemplate(r)
+			ctx, span := tracer.Start(ctx, r.Method+" "+route,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("http.request.method", r.Method),
+					attribute.String("http.route", route),
+					attribute.String("url.path", r.URL.Path),
+				),
+			)
+			defer span.End()
+
+			wrapped := &responseWriter{
+				ResponseWriter: w,
+				statusCode:     http.StatusOK,
+			}
+
+			next.ServeHTTP(wrapped, r.WithContext(ctx))
+
+			span.SetAttributes(attribute.Int("http.response.status_code", wrapped.statusCode))
+			if wrapped.statusCode >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(wrapped.statusCode))
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package response
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// JSONWithETag отдает JSON с ETag, вычисленным по телу ответа. Если ETag
+// совпадает с If-None-Match, отвечает 304 без тела: клиент использует
+// сохраненную копию
+func JSONWithETag(w http.ResponseWriter, r *http.Request, data interface{}) {
+	var body bytes.Buffer
+	err := json.NewEncoder(&body).Encode(data)
+	if err != nil {
+		Error(w, r, http.StatusInternalServerError, "Ошибка формирования ответа")
+		return
+	}
+
+	sum := sha256.Sum256(body.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	header := w.Header()
+	header.Set("ETag", etag)
+	// клиент может хранить ответ, но перед использованием должен проверить его
+	header.Set("Cache-Control", "private, no-cache")
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	header.Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body.Bytes())
+}
+
+// etagMatches проверяет If-None-Match: список ETag через запятую или "*".
+// Для GET сравнение слабое, поэтому префикс W/ не учитывается
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestJSONWithETag(t *testing.T) {
+	data := []map[string]string{{"city": "Москва"}}
+
+	first := httptest.NewRecorder()
+	JSONWithETag(first, httptest.NewRequest(http.MethodGet, "/pvz", nil), data)
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("Ожидался ответ 200 с ETag, получено: %d %q", first.Code, etag)
+	}
+	if first.Body.String() != "[{\"city\":\"Москва\"}]\n" {
+		t.Errorf("Неверное тело ответа: %q", first.Body.String())
+	}
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		want        int
+	}{
+		{"совпадающий ETag", etag, http.StatusNotModified},
+		{"слабый ETag в списке", `"other", W/` + etag, http.StatusNotModified},
+		{"любой", "*", http.StatusNotModified},
+		{"устаревший ETag", `"other"`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/pvz", nil)
+			req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			rec := httptest.NewRecorder()
+
+			JSONWithETag(rec, req, data)
+			if rec.Code != tt.want {
+				t.Fatalf("Ожидался статус %d, получен %d", tt.want, rec.Code)
+			}
+			if tt.want == http.StatusNotModified && rec.Body.Len() != 0 {
+				t.Errorf("Ответ 304 не должен содержать тело: %q", rec.Body.String())
+			}
+			if rec.Header().Get("ETag") != etag {
+				t.Errorf("ETag не должен зависеть от запроса: %q", rec.Header().Get("ETag"))
+			}
+		})
+	}
+}
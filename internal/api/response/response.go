@@ -3,10 +3,15 @@ package response
 import (
 	"encoding/json"
 	"net/http"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/requestctx"
 )
 
+// ErrorResponse - тело ответа с ошибкой. RequestID совпадает с заголовком
+// X-Request-ID и позволяет найти запрос в логах
 type ErrorResponse struct {
-	Message string `json:"message"`
+	Message   string `json:"message"`
+	RequestID string `json:"requestId,omitempty"`
 }
 
 func JSON(w http.ResponseWriter, statusCode int, data interface{}) {
@@ -18,6 +23,9 @@ func JSON(w http.ResponseWriter, statusCode int, data interface{}) {
 	}
 }
 
-func Error(w http.ResponseWriter, statusCode int, message string) {
-	JSON(w, statusCode, ErrorResponse{Message: message})
+func Error(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	JSON(w, statusCode, ErrorResponse{
+		Message:   message,
+		RequestID: requestctx.RequestID(r.Context()),
+	})
 }
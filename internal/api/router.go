@@ -5,16 +5,28 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/dkumancev/avito-pvz/config"
 	"github.com/dkumancev/avito-pvz/internal/api/middleware"
 	"github.com/dkumancev/avito-pvz/internal/api/v1/handlers"
+	"github.com/dkumancev/avito-pvz/pkg/application/auth"
+	"github.com/dkumancev/avito-pvz/pkg/application/ratelimit"
 	"github.com/dkumancev/avito-pvz/pkg/application/services"
-	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/cache"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/health"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/logger"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/metrics"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/apikey"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/assignment"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/audit"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/outbox"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/product"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/pvz"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/reception"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/twofactor"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/user"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/webhook"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/webhookdelivery"
 	"github.com/gorilla/mux"
 	"github.com/jmoiron/sqlx"
 )
@@ -22,97 +34,266 @@ import (
 type Router struct {
 	router    *mux.Router
 	db        *sqlx.DB
+	cfg       *config.Config
 	jwtSecret []byte
+	tokens    *auth.TokenParser
+	policy    *auth.Policy
+	apiKeys   auth.APIKeyAuthenticator
 	logger    *slog.Logger
+	services  *slog.Logger
 	metrics   *metrics.HTTPMetrics
-}
+	business  *metrics.BusinessMetrics
+	health    *health.Health
+	rateLimit func(http.Handler) http.Handler
+	// ограничение неудачных попыток аутентификации по IP
+	authFailureLimit func(http.Handler) http.Handler
 
-func NewRouter(db *sqlx.DB, jwtSecret []byte) *Router {
-	apiLogger := logger.NewLogger(logger.Config{
-		Level:  logger.LevelInfo,
-		Format: "text",
-	})
+	// сервисы, собранные в Setup; нужны фоновым задачам сервера
+	pvzService       services.PVZService
+	receptionService services.ReceptionService
+}
 
+func NewRouter(db *sqlx.DB, cfg *config.Config, policy *auth.Policy, checks *health.Health, appLogger *logger.Logger, slo *metrics.SLOTracker) *Router {
 	httpMetrics := metrics.NewHTTPMetrics()
+	httpMetrics.SLO = slo
+	businessMetrics := metrics.NewBusinessMetrics()
+	jwtSecret := []byte(cfg.Auth.JWTSecret)
+
+	// счетчик отклоненных запросов регистрируется в Prometheus один раз на оба ограничения
+	rateLimitMetrics := metrics.NewRateLimitMetrics()
+	rateLimit := newRateLimit(cfg.RateLimit, rateLimitMetrics)
+	authFailureLimit := newAuthFailureLimit(cfg.RateLimit, rateLimitMetrics)
 
 	return &Router{
 		router:    mux.NewRouter(),
 		db:        db,
+		cfg:       cfg,
 		jwtSecret: jwtSecret,
-		logger:    apiLogger,
+		tokens:    auth.NewTokenParser(jwtSecret, cfg.Auth.DummyLoginEnabled),
+		policy:    policy,
+		logger:    appLogger.Component(logger.ComponentHTTP),
+		services:  appLogger.Component(logger.ComponentServices),
 		metrics:   httpMetrics,
+		business:  businessMetrics,
+		health:    checks,
+		rateLimit: rateLimit,
+
+		authFailureLimit: authFailureLimit,
+	}
+}
+
+// newRateLimit создает middleware ограничения частоты запросов по настройкам.
+// Если ограничение выключено, запросы проходят без изменений
+func newRateLimit(cfg config.RateLimitConfig, m *metrics.RateLimitMetrics) func(http.Handler) http.Handler {
+	if !cfg.Enabled {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	routes := make(map[string]ratelimit.Limit, len(cfg.Routes))
+	for route, limit := range cfg.Routes {
+		routes[route] = ratelimit.Limit(limit)
+	}
+	limiter := ratelimit.New(ratelimit.Config{
+		Default: ratelimit.Limit(cfg.Default),
+		Public:  ratelimit.Limit(cfg.Public),
+		Routes:  routes,
+	})
+
+	return middleware.RateLimitMiddleware(limiter, m, cfg.TrustForwardedFor)
+}
+
+// newAuthFailureLimit создает ограничение неудачных попыток аутентификации
+// по IP клиента с лимитом публичных маршрутов, чтобы перебор токенов и
+// API-ключей упирался в лимит еще до проверки учетных данных
+func newAuthFailureLimit(cfg config.RateLimitConfig, m *metrics.RateLimitMetrics) func(http.Handler) http.Handler {
+	if !cfg.Enabled {
+		return func(next http.Handler) http.Handler { return next }
 	}
+
+	limiter := ratelimit.New(ratelimit.Config{Public: ratelimit.Limit(cfg.Public)})
+	return middleware.AuthFailureLimitMiddleware(limiter, m, cfg.TrustForwardedFor)
+}
+
+// PVZService возвращает сервис ПВЗ, собранный в Setup
+func (r *Router) PVZService() services.PVZService {
+	return r.pvzService
+}
+
+// ReceptionService возвращает сервис приемок, собранный в Setup
+func (r *Router) ReceptionService() services.ReceptionService {
+	return r.receptionService
 }
 
 func (r *Router) Setup() http.Handler {
+	// Роли из конфигурации становятся допустимыми для регистрации и токенов
+	r.policy.RegisterRoles()
+
 	// Репозитории
 	userRepo := user.New(r.db)
 	pvzRepo := pvz.New(r.db)
+	// ПВЗ читается при каждой операции с приемками и товарами
+	if r.cfg.Cache.PVZEnabled {
+		pvzRepo = cache.NewPVZRepository(pvzRepo, r.cfg.Cache.PVZSize, r.cfg.Cache.PVZTTL)
+	}
 	receptionRepo := reception.New(r.db)
 	productRepo := product.New(r.db)
+	assignmentRepo := assignment.New(r.db)
+	apiKeyRepo := apikey.New(r.db)
+	twoFactorRepo := twofactor.New(r.db)
+	auditRepo := audit.New(r.db)
+	outboxRepo := outbox.New(r.db)
+	webhookRepo := webhook.New(r.db)
+	deliveryRepo := webhookdelivery.New(r.db)
+	transactor := db.NewTransactor(r.db)
 
 	// Сервисы
-	userService := services.NewUserService(userRepo, r.jwtSecret, 24*time.Hour)
-	pvzService := services.NewPVZService(pvzRepo)
-	receptionService := services.NewReceptionService(pvzRepo, receptionRepo, productRepo)
+	userService := services.NewUserService(userRepo, twoFactorRepo, auditRepo, transactor, r.jwtSecret, 24*time.Hour)
+	pvzService := services.NewPVZService(pvzRepo, auditRepo, outboxRepo, transactor, r.business, r.services)
+	receptionService := services.NewReceptionService(pvzRepo, receptionRepo, productRepo, assignmentRepo, auditRepo, outboxRepo, transactor, r.policy, r.business, r.services)
+	assignmentService := services.NewAssignmentService(pvzRepo, userRepo, assignmentRepo, auditRepo, transactor, r.policy)
+	apiKeyService := services.NewAPIKeyService(apiKeyRepo, auditRepo, transactor, r.policy, r.services)
+	auditService := services.NewAuditService(auditRepo)
+	webhookService := services.NewWebhookService(webhookRepo, deliveryRepo, auditRepo, transactor)
+	r.apiKeys = apiKeyService
+	r.pvzService = pvzService
+	r.receptionService = receptionService
 
 	// Хендлеры
 	userHandler := handlers.NewUserHandler(userService)
 	pvzHandler := handlers.NewPVZHandler(pvzService, receptionService)
 	receptionHandler := handlers.NewReceptionHandler(receptionService)
 	productHandler := handlers.NewProductHandler(receptionService)
+	assignmentHandler := handlers.NewAssignmentHandler(assignmentService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
 
 	// Глобальные middleware 
-	r.router.Use(middleware.RecoveryMiddleware(r.logger)) // Сначала восстановление
+	r.router.Use(middleware.RequestIDMiddleware())        // Сначала идентификатор запроса
+	r.router.Use(middleware.RecoveryMiddleware(r.logger)) // Затем восстановление
+	r.router.Use(middleware.TracingMiddleware())          // Затем трассировка
 	r.router.Use(middleware.MetricsMiddleware(r.metrics)) // Затем сбор метрик
 	r.router.Use(middleware.LoggerMiddleware(r.logger))   // Затем логирование
 
-	// Health check
-	r.router.HandleFunc("/health", func(w http.ResponseWriter, req *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Header().Set("Content-Type", "application/json")
-		w.Write([]byte(`{"status": "ok", "db": "connected"}`))
-	}).Methods(http.MethodGet)
+	// Middleware mux не вызываются для несовпавших маршрутов, поэтому
+	// 404 и 405 учитываются в метриках отдельно под меткой "unmatched"
+	// и тоже получают идентификатор запроса
+	unmatched := func(h http.Handler) http.Handler {
+		return middleware.RequestIDMiddleware()(middleware.MetricsMiddleware(r.metrics)(h))
+	}
+	r.router.NotFoundHandler = unmatched(http.NotFoundHandler())
+	r.router.MethodNotAllowedHandler = unmatched(http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}))
+
+	// Проверки здоровья. /health оставлен для совместимости и совпадает с /readyz
+	r.router.Handle("/livez", r.health.LivenessHandler()).Methods(http.MethodGet)
+	r.router.Handle("/readyz", r.health.ReadinessHandler()).Methods(http.MethodGet)
+	r.router.Handle("/health", r.health.ReadinessHandler()).Methods(http.MethodGet)
+
+	// Публичные маршруты ограничиваются по IP клиента
+	r.router.Handle("/register", r.public(userHandler.Register)).Methods(http.MethodPost)
+	r.router.Handle("/login", r.public(userHandler.Login)).Methods(http.MethodPost)
+	r.router.Handle("/login/2fa", r.public(userHandler.LoginTwoFactor)).Methods(http.MethodPost)
+	// Тестовый вход доступен, только если явно включен в конфигурации
+	if r.cfg.Auth.DummyLoginEnabled {
+		r.router.Handle("/dummyLogin", r.public(userHandler.DummyLogin)).Methods(http.MethodPost)
+	}
+
+	// Настройка 2FA текущим пользователем. Требует только аутентификации,
+	// чтобы роль с обязательной 2FA могла ее включить
+	r.router.Handle("/me/2fa/enroll", r.authenticated(userHandler.EnrollTwoFactor)).Methods(http.MethodPost)
+	r.router.Handle("/me/2fa/verify", r.authenticated(userHandler.VerifyTwoFactor)).Methods(http.MethodPost)
+
+	// Защищенные маршруты. Доступ определяется правами роли пользователя
+	// или правами API-ключа внешней системы
+
+	// ПВЗ - создание (pvz:create), просмотр (pvz:read)
+	r.router.Handle("/pvz", r.protected(auth.PermissionPVZCreate,
+		pvzHandler.CreatePVZ)).Methods(http.MethodPost)
+
+	r.router.Handle("/pvz", r.protected(auth.PermissionPVZRead,
+		pvzHandler.ListPVZ)).Methods(http.MethodGet)
+
+	// Закрепление сотрудников за ПВЗ (users:manage)
+	r.router.Handle("/pvz/{pvzId}/employees", r.protected(auth.PermissionUsersManage,
+		assignmentHandler.ListEmployees)).Methods(http.MethodGet)
+
+	r.router.Handle("/pvz/{pvzId}/employees", r.protected(auth.PermissionUsersManage,
+		assignmentHandler.AssignEmployee)).Methods(http.MethodPost)
+
+	r.router.Handle("/pvz/{pvzId}/employees/{userId}", r.protected(auth.PermissionUsersManage,
+		assignmentHandler.UnassignEmployee)).Methods(http.MethodDelete)
 
-	// Публичные маршруты
-	r.router.HandleFunc("/register", userHandler.Register).Methods(http.MethodPost)
-	r.router.HandleFunc("/login", userHandler.Login).Methods(http.MethodPost)
-	r.router.HandleFunc("/dummyLogin", userHandler.DummyLogin).Methods(http.MethodPost)
+	// API-ключи для интеграций (users:manage)
+	r.router.Handle("/api-keys", r.protected(auth.PermissionUsersManage,
+		apiKeyHandler.ListAPIKeys)).Methods(http.MethodGet)
 
-	// Защищенные маршруты
+	r.router.Handle("/api-keys", r.protected(auth.PermissionUsersManage,
+		apiKeyHandler.CreateAPIKey)).Methods(http.MethodPost)
 
-	// ПВЗ - модератор может создавать, все авторизованные могут просматривать
-	r.router.Handle("/pvz", middleware.AuthMiddleware(r.jwtSecret,
-		middleware.RoleMiddleware([]domain.UserRole{domain.ModeratorRole},
-			http.HandlerFunc(pvzHandler.CreatePVZ)))).Methods(http.MethodPost)
+	r.router.Handle("/api-keys/{keyId}", r.protected(auth.PermissionUsersManage,
+		apiKeyHandler.RevokeAPIKey)).Methods(http.MethodDelete)
 
-	r.router.Handle("/pvz", middleware.AuthMiddleware(r.jwtSecret,
-		http.HandlerFunc(pvzHandler.ListPVZ))).Methods(http.MethodGet)
+	// Журнал аудита (audit:read)
+	r.router.Handle("/audit", r.protected(auth.PermissionAuditRead,
+		auditHandler.ListAuditEntries)).Methods(http.MethodGet)
 
-	// Закрытие приемки - только сотрудник
-	r.router.Handle("/pvz/{pvzId}/close_last_reception", middleware.AuthMiddleware(r.jwtSecret,
-		middleware.RoleMiddleware([]domain.UserRole{domain.EmployeeRole},
-			http.HandlerFunc(pvzHandler.CloseLastReception)))).Methods(http.MethodPost)
+	// Вебхуки и журнал их доставок (webhooks:manage)
+	r.router.Handle("/webhooks", r.protected(auth.PermissionWebhooksManage,
+		webhookHandler.ListWebhooks)).Methods(http.MethodGet)
 
-	// Удаление последнего товара - только сотрудник
-	r.router.Handle("/pvz/{pvzId}/delete_last_product", middleware.AuthMiddleware(r.jwtSecret,
-		middleware.RoleMiddleware([]domain.UserRole{domain.EmployeeRole},
-			http.HandlerFunc(pvzHandler.DeleteLastProduct)))).Methods(http.MethodPost)
+	r.router.Handle("/webhooks", r.protected(auth.PermissionWebhooksManage,
+		webhookHandler.CreateWebhook)).Methods(http.MethodPost)
 
-	// Создание приемки - только сотрудник
-	r.router.Handle("/receptions", middleware.AuthMiddleware(r.jwtSecret,
-		middleware.RoleMiddleware([]domain.UserRole{domain.EmployeeRole},
-			http.HandlerFunc(receptionHandler.CreateReception)))).Methods(http.MethodPost)
+	r.router.Handle("/webhooks/{webhookId}", r.protected(auth.PermissionWebhooksManage,
+		webhookHandler.DeleteWebhook)).Methods(http.MethodDelete)
 
-	// Добавление товара - только сотрудник
-	r.router.Handle("/products", middleware.AuthMiddleware(r.jwtSecret,
-		middleware.RoleMiddleware([]domain.UserRole{domain.EmployeeRole},
-			http.HandlerFunc(productHandler.AddProduct)))).Methods(http.MethodPost)
+	r.router.Handle("/webhooks/{webhookId}/deliveries", r.protected(auth.PermissionWebhooksManage,
+		webhookHandler.ListDeliveries)).Methods(http.MethodGet)
+
+	r.router.Handle("/webhooks/deliveries/{deliveryId}/redeliver", r.protected(auth.PermissionWebhooksManage,
+		webhookHandler.Redeliver)).Methods(http.MethodPost)
+
+	// Закрытие приемки (reception:close)
+	r.router.Handle("/pvz/{pvzId}/close_last_reception", r.protected(auth.PermissionReceptionClose,
+		pvzHandler.CloseLastReception)).Methods(http.MethodPost)
+
+	// Удаление последнего товара (product:delete)
+	r.router.Handle("/pvz/{pvzId}/delete_last_product", r.protected(auth.PermissionProductDelete,
+		pvzHandler.DeleteLastProduct)).Methods(http.MethodPost)
+
+	// Создание приемки (reception:create)
+	r.router.Handle("/receptions", r.protected(auth.PermissionReceptionCreate,
+		receptionHandler.CreateReception)).Methods(http.MethodPost)
+
+	// Добавление товара (product:add)
+	r.router.Handle("/products", r.protected(auth.PermissionProductAdd,
+		productHandler.AddProduct)).Methods(http.MethodPost)
 
 	r.logger.Info("API маршрутизатор настроен", "routes_count", muxRoutesCount(r.router))
 	return r.router
 }
 
+// protected оборачивает хендлер в ограничение неудачных попыток по IP, проверку токена
+// или API-ключа, ограничение частоты по пользователю и проверку права доступа
+func (r *Router) protected(permission auth.Permission, handler http.HandlerFunc) http.Handler {
+	return r.authFailureLimit(middleware.AuthMiddleware(r.tokens, r.apiKeys,
+		r.rateLimit(middleware.RequirePermission(r.policy, permission, handler))))
+}
+
+// authenticated оборачивает хендлер только в ограничение неудачных попыток по IP,
+// проверку токена или API-ключа и ограничение частоты по пользователю
+func (r *Router) authenticated(handler http.HandlerFunc) http.Handler {
+	return r.authFailureLimit(middleware.AuthMiddleware(r.tokens, r.apiKeys, r.rateLimit(handler)))
+}
+
+// public оборачивает хендлер в ограничение частоты по IP клиента
+func (r *Router) public(handler http.HandlerFunc) http.Handler {
+	return r.rateLimit(handler)
+}
+
 // количество зарегистрированных маршрутов
 func muxRoutesCount(router *mux.Router) int {
 	count := 0
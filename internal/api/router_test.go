@@ -0,0 +1,51 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/config"
+	"github.com/dkumancev/avito-pvz/pkg/application/auth"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter_ProtectedThrottlesInvalidCredentials(t *testing.T) {
+	cfg := config.RateLimitConfig{
+		Enabled: true,
+		Default: config.RateLimit{Requests: 100, Period: time.Minute},
+		Public:  config.RateLimit{Requests: 3, Period: time.Minute},
+	}
+	rateLimitMetrics := metrics.NewRateLimitMetrics()
+	r := &Router{
+		tokens:           auth.NewTokenParser([]byte("test-secret"), false),
+		policy:           auth.NewPolicy(auth.DefaultRolePermissions()),
+		rateLimit:        newRateLimit(cfg, rateLimitMetrics),
+		authFailureLimit: newAuthFailureLimit(cfg, rateLimitMetrics),
+	}
+
+	handler := r.protected(auth.PermissionPVZRead, func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	send := func(remoteAddr string) int {
+		req := httptest.NewRequest(http.MethodGet, "/pvz", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("Authorization", "Bearer guessed-token")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// неверный токен отклоняется, пока не исчерпан лимит адреса
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusUnauthorized, send("10.0.0.1:1234"), "попытка %d", i+1)
+	}
+	// дальше перебор упирается в ограничение, не доходя до проверки токена
+	assert.Equal(t, http.StatusTooManyRequests, send("10.0.0.1:1234"))
+
+	// другой адрес считается отдельно
+	assert.Equal(t, http.StatusUnauthorized, send("10.0.0.2:1234"))
+}
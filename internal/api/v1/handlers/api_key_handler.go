@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/internal/api/response"
+	"github.com/dkumancev/avito-pvz/pkg/application/auth"
+	"github.com/dkumancev/avito-pvz/pkg/application/services"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/gorilla/mux"
+)
+
+type APIKeyHandler struct {
+	apiKeyService services.APIKeyService
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+type APIKeyResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+// CreateAPIKeyResponse содержит открытое значение ключа, которое больше нигде не сохраняется
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+func NewAPIKeyHandler(apiKeyService services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, r, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	scopes := make([]auth.Permission, 0, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		scopes = append(scopes, auth.Permission(scope))
+	}
+
+	rawKey, key, err := h.apiKeyService.CreateKey(r.Context(), req.Name, scopes, req.ExpiresAt)
+	if err != nil {
+		response.Error(w, r, statusFromError(err), err.Error())
+		return
+	}
+
+	resp := CreateAPIKeyResponse{
+		APIKeyResponse: toAPIKeyResponse(key),
+		Key:            rawKey,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *APIKeyHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.apiKeyService.ListKeys(r.Context())
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	result := make([]APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, toAPIKeyResponse(key))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	keyID := vars["keyId"]
+
+	key, err := h.apiKeyService.RevokeKey(r.Context(), keyID)
+	if err != nil {
+		response.Error(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toAPIKeyResponse(key))
+}
+
+func toAPIKeyResponse(key *domain.APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		CreatedAt:  key.CreatedAt,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/internal/api/response"
+	"github.com/dkumancev/avito-pvz/pkg/application/services"
+	"github.com/gorilla/mux"
+)
+
+type AssignmentHandler struct {
+	assignmentService services.AssignmentService
+}
+
+type AssignEmployeeRequest struct {
+	UserID string `json:"userId"`
+}
+
+type AssignmentResponse struct {
+	UserID     string    `json:"userId"`
+	PVZID      string    `json:"pvzId"`
+	AssignedAt time.Time `json:"assignedAt"`
+}
+
+func NewAssignmentHandler(assignmentService services.AssignmentService) *AssignmentHandler {
+	return &AssignmentHandler{
+		assignmentService: assignmentService,
+	}
+}
+
+func (h *AssignmentHandler) AssignEmployee(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	pvzID := vars["pvzId"]
+
+	var req AssignEmployeeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, r, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	assignment, err := h.assignmentService.AssignEmployee(r.Context(), pvzID, req.UserID)
+	if err != nil {
+		response.Error(w, r, statusFromError(err), err.Error())
+		return
+	}
+
+	resp := AssignmentResponse{
+		UserID:     assignment.UserID,
+		PVZID:      assignment.PVZID,
+		AssignedAt: assignment.AssignedAt,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *AssignmentHandler) UnassignEmployee(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	pvzID := vars["pvzId"]
+	userID := vars["userId"]
+
+	err := h.assignmentService.UnassignEmployee(r.Context(), pvzID, userID)
+	if err != nil {
+		response.Error(w, r, statusFromError(err), err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"message":"Сотрудник откреплен от ПВЗ"}`))
+}
+
+func (h *AssignmentHandler) ListEmployees(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	pvzID := vars["pvzId"]
+
+	assignments, err := h.assignmentService.GetAssignmentsByPVZID(r.Context(), pvzID)
+	if err != nil {
+		response.Error(w, r, statusFromError(err), err.Error())
+		return
+	}
+
+	resp := make([]AssignmentResponse, 0, len(assignments))
+	for _, assignment := range assignments {
+		resp = append(resp, AssignmentResponse{
+			UserID:     assignment.UserID,
+			PVZID:      assignment.PVZID,
+			AssignedAt: assignment.AssignedAt,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
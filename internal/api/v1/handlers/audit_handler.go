@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/internal/api/response"
+	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
+	"github.com/dkumancev/avito-pvz/pkg/application/services"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+type AuditHandler struct {
+	auditService services.AuditService
+}
+
+type AuditEntryResponse struct {
+	ID         string          `json:"id"`
+	ActorID    string          `json:"actorId,omitempty"`
+	ActorRole  string          `json:"actorRole"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entityType"`
+	EntityID   string          `json:"entityId"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
+
+func NewAuditHandler(auditService services.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// ListAuditEntries возвращает журнал аудита. Фильтры: actorId, entityType,
+// entityId, from и to (RFC3339), page и limit
+func (h *AuditHandler) ListAuditEntries(w http.ResponseWriter, r *http.Request) {
+	filter, err := extractAuditFilter(r)
+	if err != nil {
+		response.Error(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	entries, err := h.auditService.ListEntries(r.Context(), filter)
+	if err != nil {
+		response.Error(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result := make([]AuditEntryResponse, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, toAuditEntryResponse(entry))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func extractAuditFilter(r *http.Request) (repositories.AuditFilter, error) {
+	query := r.URL.Query()
+
+	filter := repositories.AuditFilter{
+		ActorID:    query.Get("actorId"),
+		EntityType: query.Get("entityType"),
+		EntityID:   query.Get("entityId"),
+	}
+
+	if fromStr := query.Get("from"); fromStr != "" {
+		from, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return filter, errors.New("неверный формат параметра from, ожидается RFC3339")
+		}
+		filter.From = &from
+	}
+
+	if toStr := query.Get("to"); toStr != "" {
+		to, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return filter, errors.New("неверный формат параметра to, ожидается RFC3339")
+		}
+		filter.To = &to
+	}
+
+	if pageStr := query.Get("page"); pageStr != "" {
+		if parsedPage, err := strconv.Atoi(pageStr); err == nil {
+			filter.Page = parsedPage
+		}
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil {
+			filter.Limit = parsedLimit
+		}
+	}
+
+	return filter, nil
+}
+
+func toAuditEntryResponse(entry *domain.AuditEntry) AuditEntryResponse {
+	return AuditEntryResponse{
+		ID:         entry.ID,
+		ActorID:    entry.ActorID,
+		ActorRole:  entry.ActorRole,
+		Action:     entry.Action,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		Before:     entry.Before,
+		After:      entry.After,
+		CreatedAt:  entry.CreatedAt,
+	}
+}
@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/services/apikey"
+	"github.com/dkumancev/avito-pvz/pkg/application/services/reception"
+)
+
+// statusFromError подбирает HTTP статус для ошибки сервисного слоя
+func statusFromError(err error) int {
+	switch {
+	case errors.Is(err, reception.ErrPVZAccessDenied), errors.Is(err, apikey.ErrScopeNotAllowed):
+		return http.StatusForbidden
+	default:
+		return http.StatusBadRequest
+	}
+}
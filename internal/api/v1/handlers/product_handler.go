@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/dkumancev/avito-pvz/internal/api/response"
 	"github.com/dkumancev/avito-pvz/pkg/application/services"
 )
 
@@ -26,13 +27,13 @@ func (h *ProductHandler) AddProduct(w http.ResponseWriter, r *http.Request) {
 
 	var req AddProductRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"message":"Неверный формат запроса"}`, http.StatusBadRequest)
+		response.Error(w, r, http.StatusBadRequest, "Неверный формат запроса")
 		return
 	}
 
 	product, err := h.receptionService.AddProduct(r.Context(), req.PVZID, req.Type)
 	if err != nil {
-		http.Error(w, `{"message":"`+err.Error()+`"}`, http.StatusBadRequest)
+		response.Error(w, r, statusFromError(err), err.Error())
 		return
 	}
 
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/dkumancev/avito-pvz/internal/api/response"
 	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
 	"github.com/dkumancev/avito-pvz/pkg/application/services"
 	"github.com/gorilla/mux"
@@ -49,13 +50,13 @@ func (h *PVZHandler) CreatePVZ(w http.ResponseWriter, r *http.Request) {
 
 	var req CreatePVZRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"message":"Неверный формат запроса"}`, http.StatusBadRequest)
+		response.Error(w, r, http.StatusBadRequest, "Неверный формат запроса")
 		return
 	}
 
 	pvz, err := h.pvzService.CreatePVZ(r.Context(), req.City)
 	if err != nil {
-		http.Error(w, `{"message":"`+err.Error()+`"}`, http.StatusBadRequest)
+		response.Error(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -82,15 +83,15 @@ func (h *PVZHandler) ListPVZ(w http.ResponseWriter, r *http.Request) {
 
 	pvzList, err := h.pvzService.ListPVZ(r.Context(), filter)
 	if err != nil {
-		http.Error(w, `{"message":"Ошибка при получении списка ПВЗ"}`, http.StatusInternalServerError)
+		response.Error(w, r, http.StatusInternalServerError, "Ошибка при получении списка ПВЗ")
 		return
 	}
 
-	var response []PVZWithReceptionsResponse
+	var result []PVZWithReceptionsResponse
 	for _, pvz := range pvzList {
 		receptions, err := h.receptionService.GetReceptionsByPVZID(r.Context(), pvz.ID)
 		if err != nil {
-			http.Error(w, `{"message":"Ошибка при получении данных о приемках"}`, http.StatusInternalServerError)
+			response.Error(w, r, http.StatusInternalServerError, "Ошибка при получении данных о приемках")
 			return
 		}
 
@@ -98,7 +99,7 @@ func (h *PVZHandler) ListPVZ(w http.ResponseWriter, r *http.Request) {
 		for _, reception := range receptions {
 			products, err := h.receptionService.GetProductsByReceptionID(r.Context(), reception.ID)
 			if err != nil {
-				http.Error(w, `{"message":"Ошибка при получении данных о товарах"}`, http.StatusInternalServerError)
+				response.Error(w, r, http.StatusInternalServerError, "Ошибка при получении данных о товарах")
 				return
 			}
 
@@ -123,7 +124,7 @@ func (h *PVZHandler) ListPVZ(w http.ResponseWriter, r *http.Request) {
 			})
 		}
 
-		response = append(response, PVZWithReceptionsResponse{
+		result = append(result, PVZWithReceptionsResponse{
 			PVZ: PVZResponse{
 				ID:               pvz.ID,
 				RegistrationDate: pvz.RegistrationDate,
@@ -133,8 +134,8 @@ func (h *PVZHandler) ListPVZ(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	// список меняется редко, а терминалы запрашивают его постоянно
+	response.JSONWithETag(w, r, result)
 }
 
 func (h *PVZHandler) CloseLastReception(w http.ResponseWriter, r *http.Request) {
@@ -143,7 +144,7 @@ func (h *PVZHandler) CloseLastReception(w http.ResponseWriter, r *http.Request)
 
 	closedReception, err := h.receptionService.CloseReception(r.Context(), pvzID)
 	if err != nil {
-		http.Error(w, `{"message":"`+err.Error()+`"}`, http.StatusBadRequest)
+		response.Error(w, r, statusFromError(err), err.Error())
 		return
 	}
 
@@ -164,7 +165,7 @@ func (h *PVZHandler) DeleteLastProduct(w http.ResponseWriter, r *http.Request) {
 
 	err := h.receptionService.DeleteLastProduct(r.Context(), pvzID)
 	if err != nil {
-		http.Error(w, `{"message":"`+err.Error()+`"}`, http.StatusBadRequest)
+		response.Error(w, r, statusFromError(err), err.Error())
 		return
 	}
 
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/dkumancev/avito-pvz/internal/api/response"
 	"github.com/dkumancev/avito-pvz/pkg/application/services"
 )
 
@@ -25,13 +26,13 @@ func (h *ReceptionHandler) CreateReception(w http.ResponseWriter, r *http.Reques
 
 	var req CreateReceptionRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"message":"Неверный формат запроса"}`, http.StatusBadRequest)
+		response.Error(w, r, http.StatusBadRequest, "Неверный формат запроса")
 		return
 	}
 
 	reception, err := h.receptionService.CreateReception(r.Context(), req.PVZID)
 	if err != nil {
-		http.Error(w, `{"message":"`+err.Error()+`"}`, http.StatusBadRequest)
+		response.Error(w, r, statusFromError(err), err.Error())
 		return
 	}
 
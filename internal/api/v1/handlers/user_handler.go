@@ -2,9 +2,13 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
+	"github.com/dkumancev/avito-pvz/internal/api/middleware"
+	"github.com/dkumancev/avito-pvz/internal/api/response"
 	"github.com/dkumancev/avito-pvz/pkg/application/services"
+	userServices "github.com/dkumancev/avito-pvz/pkg/application/services/user"
 	"github.com/dkumancev/avito-pvz/pkg/domain"
 )
 
@@ -37,6 +41,31 @@ type TokenResponse struct {
 	Token string `json:"token"`
 }
 
+// ответ первого шага входа для пользователя с включенной 2FA
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"twoFactorRequired"`
+	ChallengeToken    string `json:"challengeToken"`
+}
+
+type LoginTwoFactorRequest struct {
+	ChallengeToken string `json:"challengeToken"`
+	Code           string `json:"code"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+type TwoFactorEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURL string `json:"otpauthUrl"`
+}
+
+type TwoFactorVerifyResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+	Token         string   `json:"token"`
+}
+
 func NewUserHandler(userService services.UserService) *UserHandler {
 	return &UserHandler{
 		userService: userService,
@@ -45,30 +74,25 @@ func NewUserHandler(userService services.UserService) *UserHandler {
 
 func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, `{"message":"Метод не поддерживается"}`, http.StatusMethodNotAllowed)
+		response.Error(w, r, http.StatusMethodNotAllowed, "Метод не поддерживается")
 		return
 	}
 
 	var req RegisterRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"message":"Неверный формат запроса"}`, http.StatusBadRequest)
+		response.Error(w, r, http.StatusBadRequest, "Неверный формат запроса")
 		return
 	}
 
-	var role domain.UserRole
-	switch req.Role {
-	case "employee":
-		role = domain.EmployeeRole
-	case "moderator":
-		role = domain.ModeratorRole
-	default:
-		http.Error(w, `{"message":"Неверная роль пользователя"}`, http.StatusBadRequest)
+	role := domain.UserRole(req.Role)
+	if !domain.ValidRoles[role] {
+		response.Error(w, r, http.StatusBadRequest, "Неверная роль пользователя")
 		return
 	}
 
 	user, err := h.userService.Register(r.Context(), req.Email, req.Password, role)
 	if err != nil {
-		http.Error(w, `{"message":"`+err.Error()+`"}`, http.StatusBadRequest)
+		response.Error(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -85,24 +109,108 @@ func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 
 func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, `{"message":"Метод не поддерживается"}`, http.StatusMethodNotAllowed)
+		response.Error(w, r, http.StatusMethodNotAllowed, "Метод не поддерживается")
 		return
 	}
 
 	var req LoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"message":"Неверный формат запроса"}`, http.StatusBadRequest)
+		response.Error(w, r, http.StatusBadRequest, "Неверный формат запроса")
 		return
 	}
 
-	token, err := h.userService.Login(r.Context(), req.Email, req.Password)
+	result, err := h.userService.Login(r.Context(), req.Email, req.Password)
 	if err != nil {
-		http.Error(w, `{"message":"Неверные учетные данные"}`, http.StatusUnauthorized)
+		response.Error(w, r, http.StatusUnauthorized, "Неверные учетные данные")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if result.TwoFactorRequired() {
+		json.NewEncoder(w).Encode(TwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    result.ChallengeToken,
+		})
 		return
 	}
 
 	resp := TokenResponse{
-		Token: token,
+		Token: result.Token,
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}
+
+// LoginTwoFactor второй шаг входа: обмен токена входа и кода на токен доступа
+func (h *UserHandler) LoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req LoginTwoFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, r, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	token, err := h.userService.VerifyLoginCode(r.Context(), req.ChallengeToken, req.Code)
+	if errors.Is(err, userServices.ErrTooManyTwoFactorAttempts) {
+		response.Error(w, r, http.StatusTooManyRequests, err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(TokenResponse{Token: token})
+}
+
+// EnrollTwoFactor выдает секрет TOTP текущему пользователю
+func (h *UserHandler) EnrollTwoFactor(w http.ResponseWriter, r *http.Request) {
+	user, err := middleware.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, r, http.StatusUnauthorized, "Ошибка авторизации")
+		return
+	}
+
+	enrollment, err := h.userService.EnrollTwoFactor(r.Context(), user.ID)
+	if err != nil {
+		response.Error(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp := TwoFactorEnrollResponse{
+		Secret:     enrollment.Secret,
+		OTPAuthURL: enrollment.URI,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// VerifyTwoFactor подтверждает привязку кодом из приложения и включает 2FA
+func (h *UserHandler) VerifyTwoFactor(w http.ResponseWriter, r *http.Request) {
+	user, err := middleware.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, r, http.StatusUnauthorized, "Ошибка авторизации")
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, r, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	activation, err := h.userService.ConfirmTwoFactor(r.Context(), user.ID, req.Code)
+	if err != nil {
+		response.Error(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp := TwoFactorVerifyResponse{
+		RecoveryCodes: activation.RecoveryCodes,
+		Token:         activation.Token,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -111,30 +219,25 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 
 func (h *UserHandler) DummyLogin(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, `{"message":"Метод не поддерживается"}`, http.StatusMethodNotAllowed)
+		response.Error(w, r, http.StatusMethodNotAllowed, "Метод не поддерживается")
 		return
 	}
 
 	var req DummyLoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"message":"Неверный формат запроса"}`, http.StatusBadRequest)
+		response.Error(w, r, http.StatusBadRequest, "Неверный формат запроса")
 		return
 	}
 
-	var role domain.UserRole
-	switch req.Role {
-	case "employee":
-		role = domain.EmployeeRole
-	case "moderator":
-		role = domain.ModeratorRole
-	default:
-		http.Error(w, `{"message":"Неверная роль пользователя"}`, http.StatusBadRequest)
+	role := domain.UserRole(req.Role)
+	if !domain.ValidRoles[role] {
+		response.Error(w, r, http.StatusBadRequest, "Неверная роль пользователя")
 		return
 	}
 
 	token, err := h.userService.DummyLogin(r.Context(), role)
 	if err != nil {
-		http.Error(w, `{"message":"Ошибка при создании токена"}`, http.StatusInternalServerError)
+		response.Error(w, r, http.StatusInternalServerError, "Ошибка при создании токена")
 		return
 	}
 
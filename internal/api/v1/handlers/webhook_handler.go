@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/internal/api/response"
+	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
+	"github.com/dkumancev/avito-pvz/pkg/application/services"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/gorilla/mux"
+)
+
+type WebhookHandler struct {
+	webhookService services.WebhookService
+}
+
+type CreateWebhookRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"eventTypes"`
+	// Secret - секрет подписи; если не указан, генерируется
+	Secret string `json:"secret,omitempty"`
+}
+
+type WebhookResponse struct {
+	ID         string    `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"eventTypes"`
+	CreatedBy  string    `json:"createdBy,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// CreateWebhookResponse содержит секрет подписи, который больше нигде не возвращается
+type CreateWebhookResponse struct {
+	WebhookResponse
+	Secret string `json:"secret"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             string          `json:"id"`
+	EventID        string          `json:"eventId"`
+	EventType      string          `json:"eventType"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"nextAttemptAt,omitempty"`
+	LastStatusCode int             `json:"lastStatusCode,omitempty"`
+	LastError      string          `json:"lastError,omitempty"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	DeliveredAt    *time.Time      `json:"deliveredAt,omitempty"`
+	Payload        json.RawMessage `json:"payload"`
+}
+
+func NewWebhookHandler(webhookService services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, r, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(r.Context(), req.URL, req.EventTypes, req.Secret)
+	if err != nil {
+		response.Error(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp := CreateWebhookResponse{
+		WebhookResponse: toWebhookResponse(webhook),
+		Secret:          webhook.Secret,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := h.webhookService.ListWebhooks(r.Context())
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	result := make([]WebhookResponse, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		result = append(result, toWebhookResponse(webhook))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	webhookID := mux.Vars(r)["webhookId"]
+
+	err := h.webhookService.DeleteWebhook(r.Context(), webhookID)
+	if err != nil {
+		response.Error(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDeliveries возвращает журнал доставок вебхука. Фильтры: status
+// (pending, delivered, dead), page и limit
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := repositories.WebhookDeliveryFilter{
+		WebhookID: mux.Vars(r)["webhookId"],
+		Status:    query.Get("status"),
+	}
+	if page, err := strconv.Atoi(query.Get("page")); err == nil {
+		filter.Page = page
+	}
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil {
+		filter.Limit = limit
+	}
+
+	deliveries, err := h.webhookService.ListDeliveries(r.Context(), filter)
+	if err != nil {
+		response.Error(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result := make([]WebhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		result = append(result, toWebhookDeliveryResponse(delivery))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	deliveryID := mux.Vars(r)["deliveryId"]
+
+	delivery, err := h.webhookService.Redeliver(r.Context(), deliveryID)
+	if err != nil {
+		response.Error(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(toWebhookDeliveryResponse(delivery))
+}
+
+func toWebhookResponse(webhook *domain.Webhook) WebhookResponse {
+	return WebhookResponse{
+		ID:         webhook.ID,
+		URL:        webhook.URL,
+		EventTypes: webhook.EventTypes,
+		CreatedBy:  webhook.CreatedBy,
+		CreatedAt:  webhook.CreatedAt,
+	}
+}
+
+func toWebhookDeliveryResponse(delivery *domain.WebhookDelivery) WebhookDeliveryResponse {
+	resp := WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt,
+		DeliveredAt:    delivery.DeliveredAt,
+		Payload:        delivery.Payload,
+	}
+	// время следующей попытки имеет смысл только для ожидающей доставки
+	if delivery.Status == domain.DeliveryPending {
+		nextAttemptAt := delivery.NextAttemptAt
+		resp.NextAttemptAt = &nextAttemptAt
+	}
+	return resp
+}
@@ -2,37 +2,87 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/dkumancev/avito-pvz/config"
 	"github.com/dkumancev/avito-pvz/internal/api"
+	"github.com/dkumancev/avito-pvz/pkg/application/auth"
+	"github.com/dkumancev/avito-pvz/pkg/application/events"
+	"github.com/dkumancev/avito-pvz/pkg/application/jobs"
+	"github.com/dkumancev/avito-pvz/pkg/application/webhooks"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/buildinfo"
+	eventpublishers "github.com/dkumancev/avito-pvz/pkg/infrastructure/events"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/health"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/lifecycle"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/logger"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/metrics"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/migrations"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/outbox"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/webhook"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/webhookdelivery"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/tracing"
+	webhooksender "github.com/dkumancev/avito-pvz/pkg/infrastructure/webhooks"
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
+// migrateTimeout ограничивает ожидание блокировки и выполнение миграций при запуске
+const migrateTimeout = 5 * time.Minute
+
+// staleReceptionsLockKey - ключ advisory lock лидера задачи зависших приемок
+const staleReceptionsLockKey int64 = 0x70767a5f737461 // "pvz_sta"
+
+// outboxRelayLockKey - ключ advisory lock лидера публикации событий. Один
+// лидер сохраняет порядок событий в брокере
+const outboxRelayLockKey int64 = 0x70767a5f6f7574 // "pvz_out"
+
+// webhookDispatcherLockKey - ключ advisory lock лидера отправки вебхуков
+const webhookDispatcherLockKey int64 = 0x70767a5f776862 // "pvz_whb"
+
 type Server struct {
 	httpServer    *http.Server
 	cfg           *config.Config
 	logger        *slog.Logger
+	appLogger     *logger.Logger
 	metricsServer *metrics.Server
+	health        *health.Health
+	tracing       tracing.ShutdownFunc
+	db            *sqlx.DB
+	publisher     events.Publisher
+
+	stopJobs context.CancelFunc
+	jobsDone sync.WaitGroup
 }
 
-func NewServer(cfg *config.Config) *Server {
-	appLogger := logger.NewLoggerFromEnvironment(cfg.App.LogLevel)
-	return &Server{
-		cfg:    cfg,
-		logger: appLogger,
+func NewServer(cfg *config.Config) (*Server, error) {
+	appLogger, err := logger.NewFromConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка настройки логов: %w", err)
 	}
+	// Логгер по умолчанию нужен для библиотек, которым логгер не передается явно
+	slog.SetDefault(appLogger.Logger)
+
+	return &Server{
+		cfg:       cfg,
+		logger:    appLogger.Logger,
+		appLogger: appLogger,
+	}, nil
 }
 
-func (s *Server) Run(port string, handler http.Handler) error {
-	s.httpServer = &http.Server{
+// newHTTPServer создает HTTP сервер до запуска приема соединений, чтобы
+// остановка по сигналу не зависела от того, успела ли горутина начать
+// ListenAndServe. Если Shutdown выполнен раньше, ListenAndServe сразу вернет
+// http.ErrServerClosed
+func (s *Server) newHTTPServer(port string, handler http.Handler) *http.Server {
+	return &http.Server{
 		Addr:              ":" + port,
 		Handler:           handler,
 		MaxHeaderBytes:    1 << 20,
@@ -40,8 +90,6 @@ func (s *Server) Run(port string, handler http.Handler) error {
 		WriteTimeout:      s.cfg.HTTP.Timeout,
 		IdleTimeout:       s.cfg.HTTP.Timeout * 3, // обычно IdleTimeout в 2-3 раза больше
 	}
-
-	return s.httpServer.ListenAndServe()
 }
 
 func (s *Server) Shutdown(ctx context.Context) error {
@@ -49,19 +97,40 @@ func (s *Server) Shutdown(ctx context.Context) error {
 }
 
 func (s *Server) Start() error {
+	build := buildinfo.Get()
 	s.logger.Info("Запуск сервиса для управления ПВЗ",
 		"environment", s.cfg.App.Environment,
-		"version", "1.0.0")
+		"version", build.Version,
+		"commit", build.Commit,
+		"go_version", build.GoVersion)
 
 	// Запуск сервера метрик
-	s.metricsServer = metrics.NewServer(s.cfg.Metrics.Port, s.logger)
-	if err := s.metricsServer.Start(); err != nil {
+	slo := metrics.NewSLOTracker(s.cfg.SLO)
+	err := prometheus.Register(slo)
+	if err != nil {
+		s.logger.Error("Ошибка регистрации метрик SLO", "error", err)
+		return err
+	}
+
+	s.metricsServer = metrics.NewServer(s.cfg.Metrics,
+		s.appLogger.Component(logger.ComponentMetrics), s.appLogger.Levels()).WithSLO(slo)
+	err = s.metricsServer.Start()
+	if err != nil {
 		s.logger.Error("Ошибка запуска сервера метрик",
 			"error", logger.SanitizeError(err),
 			"port", s.cfg.Metrics.Port)
 		return err
 	}
 
+	s.tracing, err = tracing.Init(context.Background(), s.cfg.Tracing)
+	if err != nil {
+		s.logger.Error("Ошибка настройки трассировки", "error", err)
+		return err
+	}
+	s.logger.Info("Трассировка настроена",
+		"exporter", s.cfg.Tracing.Exporter,
+		"sample_ratio", s.cfg.Tracing.SampleRatio)
+
 	dbConn, err := db.New(s.cfg.Postgres)
 	if err != nil {
 		s.logger.Error("Ошибка подключения к базе данных",
@@ -71,59 +140,292 @@ func (s *Server) Start() error {
 			"database", s.cfg.Postgres.DBName)
 		return err
 	}
-	defer func() {
-		s.logger.Debug("Закрытие соединения с базой данных")
-		dbConn.Close()
-	}()
+	// при штатной остановке БД закрывается последним шагом gracefulShutdown,
+	// здесь - только если запуск не дошел до него
+	s.db = dbConn
+	defer s.closeDB()
 
 	s.logger.Info("Успешное подключение к базе данных",
 		"host", s.cfg.Postgres.Host,
 		"port", s.cfg.Postgres.Port,
 		"database", s.cfg.Postgres.DBName)
 
-	router := api.NewRouter(dbConn, []byte(s.cfg.Auth.JWTSecret))
+	err = metrics.RegisterDBStats(dbConn.DB, s.cfg.Postgres.DBName)
+	if err != nil {
+		s.logger.Error("Ошибка регистрации метрик пула соединений", "error", err)
+		return err
+	}
+	db.SetQueryInstrumentation(metrics.NewDBMetrics(), s.cfg.Postgres.SlowQueryThreshold,
+		s.appLogger.Component(logger.ComponentDB))
+	db.SetRetryPolicy(db.RetryPolicyFromConfig(s.cfg.Postgres), s.appLogger.Component(logger.ComponentDB))
+
+	runner := migrations.NewRunner(dbConn.DB, s.appLogger.Component(logger.ComponentDB))
+	err = s.migrate(runner)
+	if err != nil {
+		s.logger.Error("Схема базы данных не готова", "error", logger.SanitizeError(err))
+		return err
+	}
+
+	policy, err := auth.NewPolicyFromConfig(s.cfg.Auth.RolePermissions)
+	if err != nil {
+		s.logger.Error("Ошибка разбора прав ролей", "error", err)
+		return err
+	}
+	s.logger.Info("Загружены роли пользователей", "roles", policy.Roles())
+
+	err = policy.RequireTwoFactor(auth.ParseRoles(s.cfg.Auth.TwoFactorRoles)...)
+	if err != nil {
+		s.logger.Error("Ошибка настройки двухфакторной аутентификации", "error", err)
+		return err
+	}
+
+	if s.cfg.Auth.DummyLoginEnabled {
+		s.logger.Warn("Включен тестовый вход /dummyLogin: любой клиент может получить токен с произвольной ролью",
+			"environment", s.cfg.App.Environment)
+	}
+
+	s.health = s.newHealth(dbConn, runner)
+
+	router := api.NewRouter(dbConn, s.cfg, policy, s.health, s.appLogger, slo)
 	handler := router.Setup()
 
+	err = s.startJobs(dbConn, router)
+	if err != nil {
+		s.logger.Error("Ошибка запуска фоновых задач", "error", logger.SanitizeError(err))
+		return err
+	}
+
+	// ошибка ListenAndServe не завершает процесс сразу: фоновые задачи и БД
+	// останавливаются тем же порядком, что и по сигналу
+	s.httpServer = s.newHTTPServer(s.cfg.HTTP.Port, handler)
+	serveErr := make(chan error, 1)
 	go func() {
 		s.logger.Info("HTTP сервер запущен",
 			"port", s.cfg.HTTP.Port,
 			"timeout", s.cfg.HTTP.Timeout)
 
-		if err := s.Run(s.cfg.HTTP.Port, handler); err != nil && err != http.ErrServerClosed {
-			s.logger.Error("Критическая ошибка запуска сервера",
-				"error", logger.SanitizeError(err))
-			os.Exit(1)
+		err := s.httpServer.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
+			serveErr <- err
+		}
+	}()
+
+	return s.gracefulShutdown(serveErr)
+}
+
+// startJobs запускает фоновые задачи. Задачи выполняются на одной реплике:
+// лидер выбирается через advisory lock
+func (s *Server) startJobs(dbConn *sqlx.DB, router *api.Router) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stopJobs = cancel
+
+	jobLogger := s.appLogger.Component(logger.ComponentJobs)
+	jobMetrics := metrics.NewJobMetrics()
+	webhookRepo := webhook.New(dbConn)
+	deliveryRepo := webhookdelivery.New(dbConn)
+
+	if s.cfg.Reception.StaleJobEnabled {
+		staleReceptions := jobs.NewStaleReceptions(router.ReceptionService(), router.PVZService(),
+			jobs.StaleReceptionsConfig{
+				After:       s.cfg.Reception.StaleAfter,
+				AfterByCity: s.cfg.Reception.StaleAfterByCity,
+				Action:      s.cfg.Reception.StaleAction,
+			}, jobLogger)
+		s.runJob(ctx, jobs.NewScheduler(staleReceptions, s.cfg.Reception.StaleCheckInterval,
+			db.NewAdvisoryLock(dbConn, staleReceptionsLockKey), jobMetrics, jobLogger))
+	}
+
+	if s.cfg.Outbox.RelayEnabled {
+		publisher, err := eventpublishers.NewPublisher(s.cfg.Outbox)
+		if err != nil {
+			return err
+		}
+		// доставки вебхуков создаются в той же транзакции, что и отметка о публикации
+		if s.cfg.Webhook.Enabled {
+			publisher = events.NewFanout(publisher, webhooks.NewEnqueuer(webhookRepo, deliveryRepo))
 		}
+		s.publisher = publisher
+		s.logger.Info("Публикация событий включена", "publisher", s.cfg.Outbox.Publisher)
+
+		// транзакция публикации не повторяется: повтор опубликовал бы пачку еще раз
+		relay := jobs.NewOutboxRelay(outbox.New(dbConn), db.NewTransactorWithPolicy(dbConn, db.NoRetry), publisher,
+			jobs.OutboxRelayConfig{
+				BatchSize: s.cfg.Outbox.BatchSize,
+				Retention: s.cfg.Outbox.Retention,
+			}, jobLogger)
+		s.runJob(ctx, jobs.NewScheduler(relay, s.cfg.Outbox.PollInterval,
+			db.NewAdvisoryLock(dbConn, outboxRelayLockKey), jobMetrics, jobLogger))
+	}
+
+	if s.cfg.Webhook.Enabled {
+		dispatcher := jobs.NewWebhookDispatcher(webhookRepo, deliveryRepo,
+			webhooksender.NewHTTPSender(s.cfg.Webhook.Timeout),
+			jobs.WebhookDispatcherConfig{
+				BatchSize:   s.cfg.Webhook.BatchSize,
+				MaxAttempts: s.cfg.Webhook.MaxAttempts,
+				BackoffBase: s.cfg.Webhook.BackoffBase,
+				BackoffMax:  s.cfg.Webhook.BackoffMax,
+			}, jobLogger)
+		s.runJob(ctx, jobs.NewScheduler(dispatcher, s.cfg.Webhook.PollInterval,
+			db.NewAdvisoryLock(dbConn, webhookDispatcherLockKey), jobMetrics, jobLogger))
+	}
+
+	return nil
+}
+
+func (s *Server) runJob(ctx context.Context, scheduler *jobs.Scheduler) {
+	s.jobsDone.Add(1)
+	go func() {
+		defer s.jobsDone.Done()
+		scheduler.Run(ctx)
 	}()
+}
+
+// migrate применяет встроенные миграции, если включен APP_MIGRATE_ON_START, и
+// проверяет, что схема не отстает от версии сервиса. Со старой схемой сервер
+// не запускается
+func (s *Server) migrate(runner *migrations.Runner) error {
+	if s.cfg.App.MigrateOnStart {
+		ctx, cancel := context.WithTimeout(context.Background(), migrateTimeout)
+		defer cancel()
+
+		err := runner.RunMigrationsUp(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return runner.CheckVersion()
+}
+
+// newHealth собирает проверки готовности: БД, версия схемы и, если задан адрес, gRPC сервер
+func (s *Server) newHealth(dbConn *sqlx.DB, runner *migrations.Runner) *health.Health {
+	checks := health.New(s.cfg.Health.CheckTimeout)
+
+	checks.AddReadinessCheck(health.NewPostgresChecker(dbConn))
+	checks.AddReadinessCheck(health.NewMigrationChecker(runner))
+	if s.cfg.Health.GRPCTarget != "" {
+		checks.AddReadinessCheck(health.NewGRPCChecker(s.cfg.Health.GRPCTarget))
+	}
 
-	return s.gracefulShutdown()
+	return checks
 }
 
-func (s *Server) gracefulShutdown() error {
+// gracefulShutdown ждет сигнала завершения или ошибки HTTP сервера и
+// останавливает сервис по шагам: сначала перестает принимать новые запросы,
+// затем дожидается текущих, останавливает фоновые задачи и только после
+// этого закрывает БД, которой они пользуются
+func (s *Server) gracefulShutdown(serveErr <-chan error) error {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(quit)
 
-	sig := <-quit
-	s.logger.Info("Получен сигнал завершения", "signal", sig.String())
-	s.logger.Info("Начинаем корректное завершение работы сервера...")
+	var runErr error
+	select {
+	case sig := <-quit:
+		s.logger.Info("Получен сигнал завершения", "signal", sig.String())
+	case runErr = <-serveErr:
+		s.logger.Error("Критическая ошибка HTTP сервера",
+			"error", logger.SanitizeError(runErr))
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	shutdown := lifecycle.NewShutdown(s.cfg.Shutdown.Timeout, s.logger)
 
-	if s.metricsServer != nil {
-		if err := s.metricsServer.Stop(ctx); err != nil {
-			s.logger.Error("Ошибка при остановке сервера метрик",
-				"error", logger.SanitizeError(err))
+	// readiness начинает отвечать ошибкой, чтобы балансировщик перестал направлять запросы
+	shutdown.Add("readiness", func(ctx context.Context) error {
+		if s.health != nil {
+			s.health.SetShuttingDown()
 		}
+		return nil
+	})
+	if runErr == nil {
+		shutdown.Add("pre_stop_delay", lifecycle.Delay(s.cfg.Shutdown.PreStopDelay))
 	}
+	shutdown.Add("http", s.shutdownHTTP)
+	shutdown.Add("jobs", s.shutdownJobs)
+	// публикатор закрывается после задачи, которая им пользуется
+	shutdown.Add("events", func(ctx context.Context) error {
+		if s.publisher == nil {
+			return nil
+		}
+		return s.publisher.Close()
+	})
+	// метрики останавливаются после HTTP, чтобы их можно было снять во время остановки
+	shutdown.Add("metrics", func(ctx context.Context) error {
+		if s.metricsServer == nil {
+			return nil
+		}
+		return s.metricsServer.Stop(ctx)
+	})
+	shutdown.Add("tracing", func(ctx context.Context) error {
+		if s.tracing == nil {
+			return nil
+		}
+		return s.tracing(ctx)
+	})
+	shutdown.Add("database", func(ctx context.Context) error {
+		return s.closeDB()
+	})
 
-	if err := s.Shutdown(ctx); err != nil {
-		s.logger.Error("Ошибка при остановке сервера",
-			"error", logger.SanitizeError(err),
-			"timeout", "10s")
-		return err
+	err := shutdown.Run()
+	if err != nil && runErr == nil {
+		runErr = err
+	}
+	if runErr == nil {
+		s.logger.Info("Сервер успешно остановлен")
+	}
+
+	if err := s.appLogger.Close(); err != nil && runErr == nil {
+		return fmt.Errorf("ошибка закрытия файла логов: %w", err)
+	}
+	return runErr
+}
+
+// shutdownHTTP дожидается завершения текущих запросов. Если они не уложились
+// в таймаут, оставшиеся соединения закрываются принудительно
+func (s *Server) shutdownHTTP(ctx context.Context) error {
+	if s.httpServer == nil {
+		return nil
 	}
 
-	s.logger.Info("Сервер успешно остановлен")
+	err := s.Shutdown(ctx)
+	if err != nil {
+		s.logger.Warn("Не все запросы завершились до таймаута, соединения закрываются",
+			"error", logger.SanitizeError(err))
+		s.httpServer.Close()
+		return err
+	}
 	return nil
 }
+
+// shutdownJobs останавливает фоновые задачи и ждет завершения текущего запуска
+func (s *Server) shutdownJobs(ctx context.Context) error {
+	if s.stopJobs == nil {
+		return nil
+	}
+	s.stopJobs()
+
+	done := make(chan struct{})
+	go func() {
+		s.jobsDone.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("фоновые задачи не остановились: %w", ctx.Err())
+	}
+}
+
+// closeDB закрывает соединение с БД один раз
+func (s *Server) closeDB() error {
+	if s.db == nil {
+		return nil
+	}
+	s.logger.Debug("Закрытие соединения с базой данных")
+	err := s.db.Close()
+	s.db = nil
+	return err
+}
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+----------------------------------------
+-- Закрепление сотрудников за ПВЗ
+----------------------------------------
+-- Сотрудник может работать с приемками и товарами только на тех ПВЗ,
+-- за которыми он закреплен. Закреплением управляют модераторы.
+CREATE TABLE IF NOT EXISTS employee_pvz (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    pvz_id UUID NOT NULL REFERENCES pvz(id) ON DELETE CASCADE,
+    assigned_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, pvz_id) -- Сотрудник закрепляется за ПВЗ не более одного раза
+);
+
+-- Ускоряет получение списка сотрудников конкретного ПВЗ
+CREATE INDEX IF NOT EXISTS idx_employee_pvz_pvz ON employee_pvz(pvz_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS employee_pvz;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+----------------------------------------
+-- Роли пользователей без жесткого перечисления
+----------------------------------------
+-- Набор ролей и их права задаются конфигурацией сервиса (AUTH_ROLE_PERMISSIONS),
+-- поэтому перечисление user_role заменяется строкой. Новые роли
+-- (например, supervisor или auditor) добавляются без изменения схемы.
+ALTER TABLE users ALTER COLUMN role TYPE VARCHAR(50) USING role::text;
+DROP TYPE IF EXISTS user_role;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+/*
+ВАЖНО! откат возможен, только если в таблице users остались
+пользователи исключительно с ролями employee и moderator.
+*/
+CREATE TYPE user_role AS ENUM ('employee', 'moderator');
+ALTER TABLE users ALTER COLUMN role TYPE user_role USING role::user_role;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+----------------------------------------
+-- API-ключи для межсервисных интеграций
+----------------------------------------
+-- Ключ выдается модератором и хранится только в виде SHA-256 хэша.
+-- prefix - первые символы ключа для опознания в списке без раскрытия секрета.
+-- scopes - права из той же модели, что и у ролей пользователей (pvz:create, pvz:read и т.д.).
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP,   -- NULL - бессрочный ключ
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP    -- NULL - ключ действует
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS api_keys;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+
+----------------------------------------
+-- Двухфакторная аутентификация (TOTP)
+----------------------------------------
+-- secret - секрет TOTP в base32, по нему приложение-аутентификатор генерирует коды.
+-- enabled_at - момент подтверждения первым кодом; до этого запись хранит незавершенную привязку.
+-- last_used_step - номер последнего принятого 30-секундного интервала, защищает от повторного использования кода.
+-- recovery_code_hashes - SHA-256 хэши неиспользованных кодов восстановления.
+CREATE TABLE IF NOT EXISTS user_two_factor (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret VARCHAR(64) NOT NULL,
+    enabled_at TIMESTAMP,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    recovery_code_hashes TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_two_factor;
+-- +goose StatementEnd
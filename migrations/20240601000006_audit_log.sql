@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+
+----------------------------------------
+-- Журнал аудита изменяющих действий
+----------------------------------------
+-- Запись добавляется в той же транзакции, что и изменение.
+-- actor_id - пользователь или API-ключ; NULL для внутренних вызовов.
+-- actor_role - роль пользователя, service (API-ключ) или system.
+-- before/after - JSON снимки сущности до и после изменения.
+-- Ссылок на пользователей и сущности нет: записи переживают удаление.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    actor_id VARCHAR(64),
+    actor_role VARCHAR(50) NOT NULL,
+    action VARCHAR(64) NOT NULL,
+    entity_type VARCHAR(32) NOT NULL,
+    entity_id VARCHAR(128) NOT NULL,
+    before JSONB,
+    after JSONB,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log (created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log (actor_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log (entity_type, entity_id, created_at DESC);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS audit_log;
+-- +goose StatementEnd
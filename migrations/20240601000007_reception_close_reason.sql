@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+
+----------------------------------------
+-- Сведения о закрытии приемки
+----------------------------------------
+-- closed_by - user (закрыта сотрудником) или system (закрыта автоматически).
+-- close_reason - причина автоматического закрытия.
+-- stale_since - когда приемка отмечена зависшей, если ее не закрывают автоматически.
+ALTER TABLE reception
+    ADD COLUMN IF NOT EXISTS closed_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS closed_by VARCHAR(16),
+    ADD COLUMN IF NOT EXISTS close_reason TEXT,
+    ADD COLUMN IF NOT EXISTS stale_since TIMESTAMP;
+
+-- поиск зависших приемок по возрасту
+CREATE INDEX IF NOT EXISTS idx_reception_in_progress_date_time
+    ON reception (date_time) WHERE status = 'in_progress';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_reception_in_progress_date_time;
+
+ALTER TABLE reception
+    DROP COLUMN IF EXISTS stale_since,
+    DROP COLUMN IF EXISTS close_reason,
+    DROP COLUMN IF EXISTS closed_by,
+    DROP COLUMN IF EXISTS closed_at;
+-- +goose StatementEnd
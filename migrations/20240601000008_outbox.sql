@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+
+----------------------------------------
+-- Исходящие доменные события (transactional outbox)
+----------------------------------------
+-- Событие добавляется в той же транзакции, что и изменение, и публикуется
+-- фоновой задачей. seq задает порядок публикации.
+-- published_at - время публикации; NULL, пока событие не опубликовано.
+-- attempts и last_error - неудачные попытки публикации.
+CREATE TABLE IF NOT EXISTS outbox (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    seq BIGSERIAL NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    aggregate_type VARCHAR(32) NOT NULL,
+    aggregate_id VARCHAR(128) NOT NULL,
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    published_at TIMESTAMP,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT
+);
+
+-- выборка неопубликованных событий по порядку
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox (seq) WHERE published_at IS NULL;
+-- удаление опубликованных событий старше срока хранения
+CREATE INDEX IF NOT EXISTS idx_outbox_published_at ON outbox (published_at) WHERE published_at IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS outbox;
+-- +goose StatementEnd
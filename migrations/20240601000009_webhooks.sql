@@ -0,0 +1,49 @@
+-- +goose Up
+-- +goose StatementBegin
+
+----------------------------------------
+-- Исходящие вебхуки
+----------------------------------------
+-- Подписка внешней системы на типы событий. secret хранится открыто: им
+-- подписывается каждая доставка (HMAC-SHA256).
+CREATE TABLE IF NOT EXISTS webhooks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    url TEXT NOT NULL,
+    event_types TEXT[] NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Доставка события одному вебхуку - она же журнал доставок.
+-- status: pending - ждет отправки в next_attempt_at, delivered - получатель
+-- ответил 2xx, dead - попытки исчерпаны.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    webhook_id UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event_id UUID NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_status_code INT,
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMP,
+    -- повторная публикация события из outbox не создает вторую доставку
+    UNIQUE (webhook_id, event_id)
+);
+
+-- выборка доставок, которым пора отправляться
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+-- журнал доставок вебхука
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON webhook_deliveries (webhook_id, created_at DESC);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+----------------------------------------
+-- Попытки ввода кода второго фактора
+----------------------------------------
+-- challenge_id - идентификатор (jti) промежуточного токена входа.
+-- attempts - число проверенных кодов по этому токену; после лимита токен больше не принимается.
+-- expires_at - срок действия токена, после него запись можно удалять.
+CREATE TABLE IF NOT EXISTS two_factor_challenges (
+    challenge_id VARCHAR(64) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_two_factor_challenges_user_id ON two_factor_challenges(user_id, expires_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS two_factor_challenges;
+-- +goose StatementEnd
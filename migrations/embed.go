@@ -0,0 +1,9 @@
+// Package migrations содержит SQL миграции схемы БД, встроенные в бинарник
+package migrations
+
+import "embed"
+
+// FS - файлы миграций goose
+//
+//go:embed *.sql
+var FS embed.FS
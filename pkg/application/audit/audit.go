@@ -0,0 +1,50 @@
+// Package audit записывает изменяющие действия сервисов в журнал аудита.
+// Запись выполняется внутри repositories.Transactor.WithinTransaction,
+// поэтому сохраняется только вместе с самим изменением
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/auth"
+	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+// Record записывает действие инициатора из контекста. before и after -
+// состояние сущности до и после изменения (nil, если сущности не было)
+func Record(ctx context.Context, repo repositories.AuditRepository, action, entityType, entityID string, before, after any) error {
+	actorID, actorRole := Actor(ctx)
+	return RecordActor(ctx, repo, actorID, actorRole, action, entityType, entityID, before, after)
+}
+
+// RecordActor записывает действие явно указанного инициатора, например
+// пользователя, который сам себя зарегистрировал
+func RecordActor(ctx context.Context, repo repositories.AuditRepository, actorID, actorRole, action, entityType, entityID string, before, after any) error {
+	entry, err := domain.NewAuditEntry(actorID, actorRole, action, entityType, entityID, before, after)
+	if err != nil {
+		return fmt.Errorf("ошибка формирования записи аудита: %w", err)
+	}
+
+	err = repo.Create(ctx, entry)
+	if err != nil {
+		return fmt.Errorf("ошибка записи аудита: %w", err)
+	}
+
+	return nil
+}
+
+// Actor определяет инициатора действия: пользователя, API-ключ внешней
+// системы или внутренний вызов без аутентификации
+func Actor(ctx context.Context) (actorID, actorRole string) {
+	if user, ok := auth.UserFromContext(ctx); ok {
+		return user.ID, string(user.Role)
+	}
+
+	if principal, ok := auth.ServicePrincipalFromContext(ctx); ok {
+		return principal.KeyID, domain.AuditActorService
+	}
+
+	return "", domain.AuditActorSystem
+}
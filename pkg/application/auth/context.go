@@ -0,0 +1,43 @@
+// Package auth содержит общие для HTTP, gRPC и сервисов примитивы авторизации
+package auth
+
+import (
+	"context"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/requestctx"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+type contextKey string
+
+const (
+	userContextKey      contextKey = "user"
+	twoFactorContextKey contextKey = "two_factor"
+)
+
+// WithUser сохраняет аутентифицированного пользователя в контексте запроса
+// и отмечает его в сведениях о запросе для логов
+func WithUser(ctx context.Context, user *domain.User) context.Context {
+	if user != nil {
+		requestctx.SetUserID(ctx, user.ID)
+	}
+	return context.WithValue(ctx, userContextKey, user)
+}
+
+// UserFromContext возвращает пользователя, выполняющего запрос.
+// Для внутренних вызовов (без аутентификации) возвращает false
+func UserFromContext(ctx context.Context) (*domain.User, bool) {
+	user, ok := ctx.Value(userContextKey).(*domain.User)
+	return user, ok && user != nil
+}
+
+// WithTwoFactor отмечает, что пользователь подтвердил вход вторым фактором
+func WithTwoFactor(ctx context.Context) context.Context {
+	return context.WithValue(ctx, twoFactorContextKey, true)
+}
+
+// TwoFactorVerified проверяет, подтвержден ли вход пользователя вторым фактором
+func TwoFactorVerified(ctx context.Context) bool {
+	verified, _ := ctx.Value(twoFactorContextKey).(bool)
+	return verified
+}
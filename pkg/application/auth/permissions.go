@@ -0,0 +1,241 @@
+package auth
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+// Permission именованное право на выполнение операции
+type Permission string
+
+const (
+	PermissionPVZCreate       Permission = "pvz:create"
+	PermissionPVZRead         Permission = "pvz:read"
+	PermissionReceptionCreate Permission = "reception:create"
+	PermissionReceptionClose  Permission = "reception:close"
+	PermissionProductAdd      Permission = "product:add"
+	PermissionProductDelete   Permission = "product:delete"
+	PermissionUsersManage     Permission = "users:manage"
+	PermissionReportsRead     Permission = "reports:read"
+	PermissionAuditRead       Permission = "audit:read"
+	PermissionWebhooksManage  Permission = "webhooks:manage"
+	// работа с приемками и товарами любого ПВЗ без закрепления
+	PermissionPVZAny Permission = "pvz:any"
+)
+
+// PVZScopedPermissions - операции в ПВЗ, которые роль без pvz:any выполняет
+// только в закрепленных за пользователем ПВЗ
+var PVZScopedPermissions = []Permission{
+	PermissionReceptionCreate,
+	PermissionReceptionClose,
+	PermissionProductAdd,
+	PermissionProductDelete,
+}
+
+// ServiceScope проверяет, можно ли выдать право API-ключу. Операции в ПВЗ
+// ограничены закреплениями, а внешнюю систему за ПВЗ не закрепить
+func ServiceScope(permission Permission) bool {
+	if permission == PermissionPVZAny {
+		return false
+	}
+	for _, scoped := range PVZScopedPermissions {
+		if permission == scoped {
+			return false
+		}
+	}
+	return ValidPermissions[permission]
+}
+
+// допустимые права
+var ValidPermissions = map[Permission]bool{
+	PermissionPVZCreate:       true,
+	PermissionPVZRead:         true,
+	PermissionReceptionCreate: true,
+	PermissionReceptionClose:  true,
+	PermissionProductAdd:      true,
+	PermissionProductDelete:   true,
+	PermissionUsersManage:     true,
+	PermissionReportsRead:     true,
+	PermissionAuditRead:       true,
+	PermissionWebhooksManage:  true,
+	PermissionPVZAny:          true,
+}
+
+// DefaultRolePermissions права встроенных ролей
+func DefaultRolePermissions() map[domain.UserRole][]Permission {
+	return map[domain.UserRole][]Permission{
+		domain.EmployeeRole: {
+			PermissionPVZRead,
+			PermissionReceptionCreate,
+			PermissionReceptionClose,
+			PermissionProductAdd,
+			PermissionProductDelete,
+		},
+		domain.ModeratorRole: {
+			PermissionPVZCreate,
+			PermissionPVZRead,
+			PermissionUsersManage,
+			PermissionReportsRead,
+			PermissionAuditRead,
+			PermissionWebhooksManage,
+			PermissionPVZAny,
+		},
+	}
+}
+
+// Policy сопоставление ролей и прав
+type Policy struct {
+	roles map[domain.UserRole]map[Permission]bool
+	// роли, которым доступ дается только после входа со вторым фактором
+	twoFactorRoles map[domain.UserRole]bool
+}
+
+func NewPolicy(rolePermissions map[domain.UserRole][]Permission) *Policy {
+	roles := make(map[domain.UserRole]map[Permission]bool, len(rolePermissions))
+	for role, permissions := range rolePermissions {
+		set := make(map[Permission]bool, len(permissions))
+		for _, permission := range permissions {
+			set[permission] = true
+		}
+		roles[role] = set
+	}
+
+	return &Policy{
+		roles:          roles,
+		twoFactorRoles: make(map[domain.UserRole]bool),
+	}
+}
+
+// NewPolicyFromConfig строит политику из встроенных ролей, дополненных
+// ролями из конфигурации в формате "role=perm1,perm2;role2=perm3".
+// Роль из конфигурации полностью заменяет набор прав встроенной роли с тем же именем
+func NewPolicyFromConfig(spec string) (*Policy, error) {
+	rolePermissions := DefaultRolePermissions()
+
+	configured, err := ParseRolePermissions(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	for role, permissions := range configured {
+		rolePermissions[role] = permissions
+	}
+
+	return NewPolicy(rolePermissions), nil
+}
+
+// ParseRolePermissions разбирает описание ролей в формате "role=perm1,perm2;role2=perm3"
+func ParseRolePermissions(spec string) (map[domain.UserRole][]Permission, error) {
+	result := make(map[domain.UserRole][]Permission)
+
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("неверное описание роли %q: ожидается role=perm1,perm2", entry)
+		}
+
+		role := domain.UserRole(strings.TrimSpace(parts[0]))
+		permissions := make([]Permission, 0)
+		for _, name := range strings.Split(parts[1], ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+
+			permission := Permission(name)
+			if !ValidPermissions[permission] {
+				return nil, fmt.Errorf("неизвестное право %q для роли %s", name, role)
+			}
+			permissions = append(permissions, permission)
+		}
+
+		result[role] = permissions
+	}
+
+	return result, nil
+}
+
+// ParseRoles разбирает список ролей через запятую
+func ParseRoles(spec string) []domain.UserRole {
+	roles := make([]domain.UserRole, 0)
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			roles = append(roles, domain.UserRole(name))
+		}
+	}
+	return roles
+}
+
+// HasRole проверяет, что роль известна политике
+func (p *Policy) HasRole(role domain.UserRole) bool {
+	_, ok := p.roles[role]
+	return ok
+}
+
+// HasPermission проверяет, есть ли у роли указанное право
+func (p *Policy) HasPermission(role domain.UserRole, permission Permission) bool {
+	return p.roles[role][permission]
+}
+
+// PVZScoped проверяет, ограничена ли роль закрепленными ПВЗ. Ограничены все
+// роли без pvz:any, в том числе неизвестные политике
+func (p *Policy) PVZScoped(role domain.UserRole) bool {
+	return !p.HasPermission(role, PermissionPVZAny)
+}
+
+// Assignable проверяет, можно ли закрепить пользователя с ролью за ПВЗ: роль
+// ограничена закрепленными ПВЗ и может выполнять в них хотя бы одну операцию
+func (p *Policy) Assignable(role domain.UserRole) bool {
+	if !p.PVZScoped(role) {
+		return false
+	}
+	for _, permission := range PVZScopedPermissions {
+		if p.HasPermission(role, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// Roles возвращает список всех ролей политики
+func (p *Policy) Roles() []domain.UserRole {
+	roles := make([]domain.UserRole, 0, len(p.roles))
+	for role := range p.roles {
+		roles = append(roles, role)
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i] < roles[j] })
+	return roles
+}
+
+// RegisterRoles делает роли политики допустимыми для пользователей домена.
+// Вызывается один раз при старте сервиса
+func (p *Policy) RegisterRoles() {
+	for role := range p.roles {
+		domain.ValidRoles[role] = true
+	}
+}
+
+// RequireTwoFactor требует двухфакторную аутентификацию для указанных ролей
+func (p *Policy) RequireTwoFactor(roles ...domain.UserRole) error {
+	for _, role := range roles {
+		if !p.HasRole(role) {
+			return fmt.Errorf("неизвестная роль %q в списке ролей с обязательной 2FA", role)
+		}
+		p.twoFactorRoles[role] = true
+	}
+	return nil
+}
+
+// TwoFactorRequired проверяет, обязательна ли двухфакторная аутентификация для роли
+func (p *Policy) TwoFactorRequired(role domain.UserRole) bool {
+	return p.twoFactorRoles[role]
+}
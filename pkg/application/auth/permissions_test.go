@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+func TestDefaultPolicy_MatchesBuiltInRoles(t *testing.T) {
+	policy := NewPolicy(DefaultRolePermissions())
+
+	testCases := []struct {
+		role       domain.UserRole
+		permission Permission
+		allowed    bool
+	}{
+		{domain.ModeratorRole, PermissionPVZCreate, true},
+		{domain.ModeratorRole, PermissionReceptionCreate, false},
+		{domain.ModeratorRole, PermissionUsersManage, true},
+		{domain.EmployeeRole, PermissionPVZCreate, false},
+		{domain.EmployeeRole, PermissionPVZRead, true},
+		{domain.EmployeeRole, PermissionReceptionClose, true},
+		{domain.EmployeeRole, PermissionProductDelete, true},
+		{"auditor", PermissionReportsRead, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.role)+"/"+string(tc.permission), func(t *testing.T) {
+			if got := policy.HasPermission(tc.role, tc.permission); got != tc.allowed {
+				t.Errorf("Ожидалось %v для %s/%s, получено %v", tc.allowed, tc.role, tc.permission, got)
+			}
+		})
+	}
+}
+
+func TestNewPolicyFromConfig_AddsRoles(t *testing.T) {
+	policy, err := NewPolicyFromConfig("supervisor=pvz:read, reception:close; auditor=reports:read")
+	if err != nil {
+		t.Fatalf("Ожидалось отсутствие ошибки, получено: %v", err)
+	}
+
+	if !policy.HasRole("supervisor") || !policy.HasRole("auditor") {
+		t.Errorf("Ожидались роли supervisor и auditor, получено: %v", policy.Roles())
+	}
+
+	if !policy.HasPermission("supervisor", PermissionReceptionClose) {
+		t.Error("Ожидалось право reception:close у роли supervisor")
+	}
+
+	if policy.HasPermission("auditor", PermissionPVZRead) {
+		t.Error("Не ожидалось право pvz:read у роли auditor")
+	}
+
+	// встроенные роли сохраняются
+	if !policy.HasPermission(domain.ModeratorRole, PermissionPVZCreate) {
+		t.Error("Ожидалось право pvz:create у модератора")
+	}
+}
+
+func TestParseRolePermissions_Invalid(t *testing.T) {
+	invalidSpecs := []string{
+		"supervisor",
+		"=pvz:read",
+		"supervisor=pvz:delete",
+	}
+
+	for _, spec := range invalidSpecs {
+		t.Run(spec, func(t *testing.T) {
+			if _, err := ParseRolePermissions(spec); err == nil {
+				t.Errorf("Ожидалась ошибка для описания %q", spec)
+			}
+		})
+	}
+}
+
+func TestPolicy_PVZScope(t *testing.T) {
+	policy, err := NewPolicyFromConfig("supervisor=pvz:read,reception:create,product:add;regional=reception:close,pvz:any;auditor=reports:read")
+	if err != nil {
+		t.Fatalf("Ожидалось отсутствие ошибки, получено: %v", err)
+	}
+
+	testCases := []struct {
+		role       domain.UserRole
+		scoped     bool
+		assignable bool
+	}{
+		{domain.EmployeeRole, true, true},
+		{domain.ModeratorRole, false, false},
+		{"supervisor", true, true},
+		{"regional", false, false},
+		{"auditor", true, false},
+		{"unknown", true, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.role), func(t *testing.T) {
+			if got := policy.PVZScoped(tc.role); got != tc.scoped {
+				t.Errorf("PVZScoped(%s) = %v, ожидалось %v", tc.role, got, tc.scoped)
+			}
+			if got := policy.Assignable(tc.role); got != tc.assignable {
+				t.Errorf("Assignable(%s) = %v, ожидалось %v", tc.role, got, tc.assignable)
+			}
+		})
+	}
+}
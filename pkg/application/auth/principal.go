@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"context"
+	"errors"
+)
+
+const (
+	servicePrincipalContextKey contextKey = "service_principal"
+)
+
+var (
+	ErrUnauthenticated   = errors.New("запрос не аутентифицирован")
+	ErrInvalidAPIKey     = errors.New("недействительный API-ключ")
+	ErrPermissionDenied  = errors.New("недостаточно прав для выполнения операции")
+	ErrTwoFactorRequired = errors.New("для роли требуется двухфакторная аутентификация")
+)
+
+// ServicePrincipal внешняя система, аутентифицированная по API-ключу
+type ServicePrincipal struct {
+	KeyID  string
+	Name   string
+	Scopes []Permission
+}
+
+// HasScope проверяет, выдано ли ключу указанное право
+func (p *ServicePrincipal) HasScope(permission Permission) bool {
+	for _, scope := range p.Scopes {
+		if scope == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// WithServicePrincipal сохраняет сервисный принципал в контексте запроса
+func WithServicePrincipal(ctx context.Context, principal *ServicePrincipal) context.Context {
+	return context.WithValue(ctx, servicePrincipalContextKey, principal)
+}
+
+// ServicePrincipalFromContext возвращает сервисный принципал, выполняющий запрос
+func ServicePrincipalFromContext(ctx context.Context) (*ServicePrincipal, bool) {
+	principal, ok := ctx.Value(servicePrincipalContextKey).(*ServicePrincipal)
+	return principal, ok && principal != nil
+}
+
+// Authorize проверяет право у пользователя или сервисного принципала из контекста.
+// Возвращает ErrUnauthenticated, если в контексте нет ни того, ни другого,
+// и ErrTwoFactorRequired, если роль пользователя требует входа со вторым фактором
+func Authorize(ctx context.Context, policy *Policy, permission Permission) error {
+	if user, ok := UserFromContext(ctx); ok {
+		if !policy.HasPermission(user.Role, permission) {
+			return ErrPermissionDenied
+		}
+		if policy.TwoFactorRequired(user.Role) && !TwoFactorVerified(ctx) {
+			return ErrTwoFactorRequired
+		}
+		return nil
+	}
+
+	if principal, ok := ServicePrincipalFromContext(ctx); ok {
+		if !principal.HasScope(permission) {
+			return ErrPermissionDenied
+		}
+		return nil
+	}
+
+	return ErrUnauthenticated
+}
+
+// APIKeyAuthenticator проверяет API-ключи внешних систем
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, rawKey string) (*ServicePrincipal, error)
+}
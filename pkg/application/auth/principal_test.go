@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+func TestAuthorize(t *testing.T) {
+	policy := NewPolicy(DefaultRolePermissions())
+
+	// без пользователя и ключа
+	err := Authorize(context.Background(), policy, PermissionPVZRead)
+	if !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Ожидалась ошибка ErrUnauthenticated, получено: %v", err)
+	}
+
+	// пользователь проверяется по правам роли
+	userCtx := WithUser(context.Background(), &domain.User{ID: "user-1", Role: domain.EmployeeRole})
+	if err := Authorize(userCtx, policy, PermissionPVZRead); err != nil {
+		t.Errorf("Сотрудник должен иметь право pvz:read, получено: %v", err)
+	}
+	if err := Authorize(userCtx, policy, PermissionPVZCreate); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Ожидалась ошибка ErrPermissionDenied, получено: %v", err)
+	}
+
+	// API-ключ проверяется по выданным ему правам, а не по ролям
+	keyCtx := WithServicePrincipal(context.Background(), &ServicePrincipal{
+		KeyID:  "key-1",
+		Name:   "warehouse",
+		Scopes: []Permission{PermissionPVZCreate},
+	})
+	if err := Authorize(keyCtx, policy, PermissionPVZCreate); err != nil {
+		t.Errorf("Ключ должен иметь право pvz:create, получено: %v", err)
+	}
+	if err := Authorize(keyCtx, policy, PermissionPVZRead); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Ожидалась ошибка ErrPermissionDenied, получено: %v", err)
+	}
+}
+
+func TestAuthorize_TwoFactorRequired(t *testing.T) {
+	policy := NewPolicy(DefaultRolePermissions())
+	if err := policy.RequireTwoFactor(domain.ModeratorRole); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	ctx := WithUser(context.Background(), &domain.User{ID: "user-1", Role: domain.ModeratorRole})
+	if err := Authorize(ctx, policy, PermissionPVZCreate); !errors.Is(err, ErrTwoFactorRequired) {
+		t.Errorf("Ожидалась ошибка ErrTwoFactorRequired, получено: %v", err)
+	}
+
+	if err := Authorize(WithTwoFactor(ctx), policy, PermissionPVZCreate); err != nil {
+		t.Errorf("После входа со вторым фактором доступ должен быть разрешен, получено: %v", err)
+	}
+
+	// роли без обязательной 2FA не затрагиваются
+	employeeCtx := WithUser(context.Background(), &domain.User{ID: "user-2", Role: domain.EmployeeRole})
+	if err := Authorize(employeeCtx, policy, PermissionPVZRead); err != nil {
+		t.Errorf("Сотруднику 2FA не требуется, получено: %v", err)
+	}
+
+	if err := policy.RequireTwoFactor("unknown"); err == nil {
+		t.Error("Ожидалась ошибка для неизвестной роли")
+	}
+}
@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// DummyClaim отметка токенов, выданных через /dummyLogin
+	DummyClaim = "dummy"
+	// TwoFactorClaim отметка токенов, выданных после проверки второго фактора
+	TwoFactorClaim = "2fa"
+	// ChallengeClaim отметка промежуточных токенов входа, ожидающих код второго фактора.
+	// Такие токены не дают доступа к API
+	ChallengeClaim = "2fa_challenge"
+)
+
+var (
+	ErrInvalidToken = errors.New("недействительный токен")
+	ErrUnknownRole  = errors.New("неизвестная роль пользователя")
+	ErrDummyToken   = errors.New("тестовые токены отключены")
+)
+
+// TokenParser проверяет JWT токены пользователей
+type TokenParser struct {
+	jwtSecret  []byte
+	allowDummy bool
+}
+
+// NewTokenParser создает парсер токенов. Если allowDummy выключен,
+// токены с отметкой DummyClaim отклоняются
+func NewTokenParser(jwtSecret []byte, allowDummy bool) *TokenParser {
+	return &TokenParser{
+		jwtSecret:  jwtSecret,
+		allowDummy: allowDummy,
+	}
+}
+
+// Authenticate проверяет токен и сохраняет пользователя в контексте,
+// отмечая вход со вторым фактором, если он был подтвержден
+func (p *TokenParser) Authenticate(ctx context.Context, tokenString string) (context.Context, error) {
+	claims, err := p.parseClaims(tokenString)
+	if err != nil {
+		return ctx, err
+	}
+
+	user, err := userFromClaims(claims)
+	if err != nil {
+		return ctx, err
+	}
+
+	ctx = WithUser(ctx, user)
+	if verified, _ := claims[TwoFactorClaim].(bool); verified {
+		ctx = WithTwoFactor(ctx)
+	}
+
+	return ctx, nil
+}
+
+// Parse проверяет подпись JWT токена и извлекает из него пользователя
+func (p *TokenParser) Parse(tokenString string) (*domain.User, error) {
+	claims, err := p.parseClaims(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	return userFromClaims(claims)
+}
+
+func (p *TokenParser) parseClaims(tokenString string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("неподдерживаемый метод подписи")
+		}
+		return p.jwtSecret, nil
+	})
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	if dummy, _ := claims[DummyClaim].(bool); dummy && !p.allowDummy {
+		return nil, ErrDummyToken
+	}
+
+	if challenge, _ := claims[ChallengeClaim].(bool); challenge {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+func userFromClaims(claims jwt.MapClaims) (*domain.User, error) {
+	userID, ok := claims["id"].(string)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	email, ok := claims["email"].(string)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	roleStr, ok := claims["role"].(string)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	role := domain.UserRole(roleStr)
+	if !domain.ValidRoles[role] {
+		return nil, ErrUnknownRole
+	}
+
+	return &domain.User{
+		ID:    userID,
+		Email: email,
+		Role:  role,
+	}, nil
+}
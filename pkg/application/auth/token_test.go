@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func signTestToken(t *testing.T, secret []byte, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	if err != nil {
+		t.Fatalf("Не удалось подписать токен: %v", err)
+	}
+	return token
+}
+
+func TestTokenParser_Parse(t *testing.T) {
+	secret := []byte("test-secret")
+	claims := jwt.MapClaims{
+		"id":    "user-1",
+		"email": "user@example.com",
+		"role":  string(domain.EmployeeRole),
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}
+
+	user, err := NewTokenParser(secret, false).Parse(signTestToken(t, secret, claims))
+	if err != nil {
+		t.Fatalf("Ожидалось отсутствие ошибки, получено: %v", err)
+	}
+	if user.ID != "user-1" || user.Role != domain.EmployeeRole {
+		t.Errorf("Получен неожиданный пользователь: %+v", user)
+	}
+
+	// токен, подписанный другим ключом
+	_, err = NewTokenParser(secret, false).Parse(signTestToken(t, []byte("other-secret"), claims))
+	if !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Ожидалась ошибка ErrInvalidToken, получено: %v", err)
+	}
+
+	// неизвестная роль
+	claims["role"] = "guest"
+	_, err = NewTokenParser(secret, false).Parse(signTestToken(t, secret, claims))
+	if !errors.Is(err, ErrUnknownRole) {
+		t.Errorf("Ожидалась ошибка ErrUnknownRole, получено: %v", err)
+	}
+}
+
+func TestTokenParser_DummyTokens(t *testing.T) {
+	secret := []byte("test-secret")
+	token := signTestToken(t, secret, jwt.MapClaims{
+		"id":       "dummy-id",
+		"email":    "dummy@example.com",
+		"role":     string(domain.ModeratorRole),
+		"exp":      time.Now().Add(time.Hour).Unix(),
+		DummyClaim: true,
+	})
+
+	_, err := NewTokenParser(secret, false).Parse(token)
+	if !errors.Is(err, ErrDummyToken) {
+		t.Errorf("Ожидалась ошибка ErrDummyToken при выключенном тестовом входе, получено: %v", err)
+	}
+
+	_, err = NewTokenParser(secret, true).Parse(token)
+	if err != nil {
+		t.Errorf("Ожидалось отсутствие ошибки при включенном тестовом входе, получено: %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTOTPCode_RFC6238(t *testing.T) {
+	// тестовый секрет из RFC 6238 (SHA1), коды - последние 6 цифр эталонных значений
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+	cases := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tc := range cases {
+		code, err := TOTPCode(secret, TOTPStep(time.Unix(tc.unix, 0)))
+		if err != nil {
+			t.Fatalf("Неожиданная ошибка: %v", err)
+		}
+		if code != tc.code {
+			t.Errorf("Для времени %d ожидался код %s, получен %s", tc.unix, tc.code, code)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("Не удалось создать секрет: %v", err)
+	}
+
+	now := time.Now()
+	step := TOTPStep(now)
+
+	// код соседнего интервала принимается из-за расхождения часов
+	previous, _ := TOTPCode(secret, step-1)
+	matched, ok := ValidateTOTP(secret, previous, now)
+	if !ok || matched != step-1 {
+		t.Errorf("Код предыдущего интервала должен приниматься, получено %d, %v", matched, ok)
+	}
+
+	// код из далекого интервала отклоняется
+	old, _ := TOTPCode(secret, step-10)
+	if _, ok := ValidateTOTP(secret, old, now); ok {
+		t.Error("Устаревший код не должен приниматься")
+	}
+
+	if _, ok := ValidateTOTP(secret, "12345", now); ok {
+		t.Error("Код неверной длины не должен приниматься")
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri := TOTPURI("PVZ Avito", "moderator@example.com", "SECRET")
+
+	if !strings.HasPrefix(uri, "otpauth://totp/PVZ%20Avito:moderator@example.com?") {
+		t.Errorf("Неожиданная ссылка: %s", uri)
+	}
+	if !strings.Contains(uri, "secret=SECRET") {
+		t.Errorf("Ссылка должна содержать секрет: %s", uri)
+	}
+}
@@ -0,0 +1,67 @@
+// Package events записывает доменные события в outbox и описывает их
+// публикацию во внешние системы. Запись выполняется внутри
+// repositories.Transactor.WithinTransaction, поэтому событие сохраняется
+// только вместе с самим изменением
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+// Publisher доставляет события во внешнюю систему. Доставка - не менее
+// одного раза: после сбоя события публикуются повторно
+type Publisher interface {
+	// Publish публикует события в переданном порядке. Ошибка означает, что
+	// ни одно событие не считается опубликованным
+	Publish(ctx context.Context, events []*domain.Event) error
+
+	// Close освобождает ресурсы после остановки публикации
+	Close() error
+}
+
+// Record добавляет событие в outbox. payload - состояние сущности после изменения
+func Record(ctx context.Context, repo repositories.OutboxRepository, eventType, aggregateType, aggregateID string, payload any) error {
+	event, err := domain.NewEvent(eventType, aggregateType, aggregateID, payload)
+	if err != nil {
+		return fmt.Errorf("ошибка формирования события: %w", err)
+	}
+
+	err = repo.Add(ctx, event)
+	if err != nil {
+		return fmt.Errorf("ошибка записи события: %w", err)
+	}
+
+	return nil
+}
+
+// fanout публикует события последовательно через несколько публикаторов
+type fanout []Publisher
+
+// NewFanout объединяет публикаторы. Пачка считается опубликованной, только
+// если ее приняли все; после ошибки любого она публикуется всем повторно
+func NewFanout(publishers ...Publisher) Publisher {
+	return fanout(publishers)
+}
+
+func (f fanout) Publish(ctx context.Context, events []*domain.Event) error {
+	for _, publisher := range f {
+		err := publisher.Publish(ctx, events)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f fanout) Close() error {
+	var errs []error
+	for _, publisher := range f {
+		errs = append(errs, publisher.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,133 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/events"
+	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
+)
+
+// OutboxRelayJob - имя задачи публикации событий в логах и метриках
+const OutboxRelayJob = "outbox_relay"
+
+// outboxCleanupInterval - как часто удаляются опубликованные события
+const outboxCleanupInterval = time.Hour
+
+// OutboxRelayConfig - размер пачки и срок хранения опубликованных событий
+type OutboxRelayConfig struct {
+	BatchSize int
+	Retention time.Duration // 0 - не удалять опубликованные события
+}
+
+// OutboxRelay публикует события из outbox. Пачка выбирается и отмечается
+// опубликованной в одной транзакции: так публикатор может записать в нее
+// свои изменения, например доставки вебхуков. Публикация выполняется внутри
+// транзакции, поэтому transactor не должен повторять fn при временных
+// ошибках (db.NoRetry): повтор опубликовал бы пачку еще раз. Доставка - не
+// менее одного раза: если транзакция не зафиксировалась после публикации,
+// пачка остается в очереди и публикуется повторно при следующем запуске
+type OutboxRelay struct {
+	outbox     repositories.OutboxRepository
+	transactor repositories.Transactor
+	publisher  events.Publisher
+	cfg        OutboxRelayConfig
+	logger     *slog.Logger
+	now        func() time.Time
+
+	lastCleanup time.Time
+}
+
+func NewOutboxRelay(outbox repositories.OutboxRepository, transactor repositories.Transactor, publisher events.Publisher, cfg OutboxRelayConfig, logger *slog.Logger) *OutboxRelay {
+	return &OutboxRelay{
+		outbox:     outbox,
+		transactor: transactor,
+		publisher:  publisher,
+		cfg:        cfg,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+func (j *OutboxRelay) Name() string {
+	return OutboxRelayJob
+}
+
+// Run публикует пачки, пока очередь не опустеет, и периодически удаляет
+// опубликованные события старше срока хранения
+func (j *OutboxRelay) Run(ctx context.Context) error {
+	for {
+		published, err := j.publishBatch(ctx)
+		if err != nil {
+			return err
+		}
+		if published < j.cfg.BatchSize {
+			break
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+
+	return j.cleanup(ctx)
+}
+
+// publishBatch публикует одну пачку и возвращает количество событий в ней.
+// При ошибке публикации пачка остается в очереди, а попытка учитывается
+func (j *OutboxRelay) publishBatch(ctx context.Context) (int, error) {
+	var count int
+	var publishErr error
+	err := j.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		pending, err := j.outbox.ListPending(ctx, j.cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+		count = len(pending)
+		if count == 0 {
+			return nil
+		}
+
+		ids := make([]string, 0, count)
+		for _, event := range pending {
+			ids = append(ids, event.ID)
+		}
+
+		publishErr = j.publisher.Publish(ctx, pending)
+		if publishErr != nil {
+			return j.outbox.MarkFailed(ctx, ids, publishErr.Error())
+		}
+
+		return j.outbox.MarkPublished(ctx, ids)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("ошибка обработки outbox: %w", err)
+	}
+	if publishErr != nil {
+		return 0, fmt.Errorf("ошибка публикации событий: %w", publishErr)
+	}
+
+	if count > 0 {
+		j.logger.DebugContext(ctx, "Опубликованы события", "count", count)
+	}
+	return count, nil
+}
+
+func (j *OutboxRelay) cleanup(ctx context.Context) error {
+	now := j.now()
+	if j.cfg.Retention <= 0 || now.Sub(j.lastCleanup) < outboxCleanupInterval {
+		return nil
+	}
+
+	deleted, err := j.outbox.DeletePublishedBefore(ctx, now.Add(-j.cfg.Retention))
+	if err != nil {
+		return err
+	}
+	j.lastCleanup = now
+
+	if deleted > 0 {
+		j.logger.InfoContext(ctx, "Удалены опубликованные события", "count", deleted)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/tests"
+)
+
+type recordingPublisher struct {
+	batches [][]*domain.Event
+	err     error
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, events []*domain.Event) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.batches = append(p.batches, events)
+	return nil
+}
+
+func (p *recordingPublisher) Close() error { return nil }
+
+func addEvents(t *testing.T, outbox *tests.MockOutboxRepository, count int) {
+	t.Helper()
+	for i := 0; i < count; i++ {
+		event, err := domain.NewEvent(domain.EventReceptionClosed, domain.AuditEntityReception, "reception-1", map[string]int{"n": i})
+		if err != nil {
+			t.Fatalf("Ошибка создания события: %v", err)
+		}
+		outbox.Add(context.Background(), event)
+	}
+}
+
+func TestOutboxRelay_PublishesInBatches(t *testing.T) {
+	outbox := tests.NewMockOutboxRepository()
+	addEvents(t, outbox, 5)
+	publisher := &recordingPublisher{}
+	relay := NewOutboxRelay(outbox, tests.NewMockTransactor(), publisher, OutboxRelayConfig{BatchSize: 2}, tests.NewTestLogger())
+
+	err := relay.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Ожидалась публикация без ошибок, получено: %v", err)
+	}
+
+	if len(publisher.batches) != 3 {
+		t.Fatalf("Ожидалось 3 пачки, получено %d", len(publisher.batches))
+	}
+	var order []string
+	for _, batch := range publisher.batches {
+		for _, event := range batch {
+			order = append(order, event.ID)
+		}
+	}
+	for i, id := range order {
+		if id != outbox.Events[i].ID {
+			t.Fatalf("События опубликованы не по порядку: %v", order)
+		}
+		if !outbox.Published[id] {
+			t.Errorf("Событие %s не отмечено опубликованным", id)
+		}
+	}
+
+	// повторный проход ничего не публикует
+	err = relay.Run(context.Background())
+	if err != nil || len(publisher.batches) != 3 {
+		t.Errorf("Опубликованные события не должны публиковаться повторно: %v, %d", err, len(publisher.batches))
+	}
+}
+
+func TestOutboxRelay_PublishFailureKeepsEvents(t *testing.T) {
+	outbox := tests.NewMockOutboxRepository()
+	addEvents(t, outbox, 2)
+	publisher := &recordingPublisher{err: errors.New("брокер недоступен")}
+	relay := NewOutboxRelay(outbox, tests.NewMockTransactor(), publisher, OutboxRelayConfig{BatchSize: 10}, tests.NewTestLogger())
+
+	err := relay.Run(context.Background())
+	if err == nil {
+		t.Fatal("Ожидалась ошибка публикации")
+	}
+	for _, event := range outbox.Events {
+		if outbox.Published[event.ID] || outbox.Attempts[event.ID] != 1 {
+			t.Errorf("Событие %s должно остаться в очереди с одной попыткой", event.ID)
+		}
+	}
+
+	// брокер восстановился - события публикуются
+	publisher.err = nil
+	err = relay.Run(context.Background())
+	if err != nil || len(publisher.batches) != 1 || len(publisher.batches[0]) != 2 {
+		t.Errorf("Ожидалась публикация после восстановления: %v, %v", err, publisher.batches)
+	}
+}
+
+func TestOutboxRelay_DeletesPublishedAfterRetention(t *testing.T) {
+	outbox := tests.NewMockOutboxRepository()
+	addEvents(t, outbox, 2)
+	relay := NewOutboxRelay(outbox, tests.NewMockTransactor(), &recordingPublisher{},
+		OutboxRelayConfig{BatchSize: 10, Retention: 24 * time.Hour}, tests.NewTestLogger())
+
+	err := relay.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Ожидалась публикация без ошибок, получено: %v", err)
+	}
+	if len(outbox.Events) != 0 {
+		t.Errorf("Опубликованные события должны удаляться, осталось %d", len(outbox.Events))
+	}
+}
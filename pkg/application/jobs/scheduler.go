@@ -0,0 +1,137 @@
+// Package jobs содержит фоновые задачи сервера и планировщик, который
+// запускает их по расписанию на одной реплике
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/metrics"
+)
+
+// unlockTimeout ограничивает снятие блокировки при остановке планировщика
+const unlockTimeout = 5 * time.Second
+
+// Job - периодическая задача
+type Job interface {
+	Name() string
+	Run(ctx context.Context) error
+}
+
+// Locker выбирает лидера среди реплик: задачу выполняет только реплика,
+// которая держит блокировку
+type Locker interface {
+	// TryLock берет блокировку без ожидания или подтверждает, что она еще взята
+	TryLock(ctx context.Context) (bool, error)
+	Unlock(ctx context.Context) error
+}
+
+// Scheduler запускает задачу с заданным периодом. Если задан Locker, задача
+// выполняется только на реплике-лидере; остальные реплики на каждом тике
+// пробуют перехватить лидерство
+type Scheduler struct {
+	job      Job
+	interval time.Duration
+	locker   Locker
+	metrics  metrics.JobMetrics
+	logger   *slog.Logger
+
+	leader bool
+}
+
+func NewScheduler(job Job, interval time.Duration, locker Locker, jobMetrics metrics.JobMetrics, logger *slog.Logger) *Scheduler {
+	return &Scheduler{
+		job:      job,
+		interval: interval,
+		locker:   locker,
+		metrics:  jobMetrics,
+		logger:   logger.With("job", job.Name()),
+	}
+}
+
+// Run выполняет задачу сразу и затем каждые interval, пока не отменен ctx.
+// При выходе снимает блокировку, чтобы лидером стала другая реплика
+func (s *Scheduler) Run(ctx context.Context) {
+	s.logger.InfoContext(ctx, "Фоновая задача запущена", "interval", s.interval.String())
+	defer s.logger.Info("Фоновая задача остановлена")
+	defer s.release()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) tick(ctx context.Context) {
+	if !s.acquire(ctx) {
+		return
+	}
+
+	start := time.Now()
+	err := s.job.Run(ctx)
+	duration := time.Since(start)
+	s.metrics.JobRun(s.job.Name(), duration, err)
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		s.logger.ErrorContext(ctx, "Ошибка выполнения фоновой задачи",
+			"error", err,
+			"duration", duration.String())
+		return
+	}
+	s.logger.DebugContext(ctx, "Фоновая задача выполнена", "duration", duration.String())
+}
+
+// acquire проверяет лидерство реплики перед проходом задачи
+func (s *Scheduler) acquire(ctx context.Context) bool {
+	if s.locker == nil {
+		return true
+	}
+
+	locked, err := s.locker.TryLock(ctx)
+	if err != nil && ctx.Err() == nil {
+		s.logger.WarnContext(ctx, "Ошибка получения блокировки фоновой задачи", "error", err)
+	}
+	s.setLeader(locked)
+	return locked
+}
+
+func (s *Scheduler) setLeader(leader bool) {
+	if leader == s.leader {
+		return
+	}
+	s.leader = leader
+	s.metrics.JobLeader(s.job.Name(), leader)
+
+	if leader {
+		s.logger.Info("Реплика стала лидером фоновой задачи")
+	} else {
+		s.logger.Info("Реплика больше не лидер фоновой задачи")
+	}
+}
+
+func (s *Scheduler) release() {
+	if s.locker == nil || !s.leader {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), unlockTimeout)
+	defer cancel()
+
+	err := s.locker.Unlock(ctx)
+	if err != nil {
+		s.logger.Warn("Ошибка снятия блокировки фоновой задачи", "error", err)
+	}
+	s.setLeader(false)
+}
@@ -0,0 +1,112 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/metrics"
+	"github.com/dkumancev/avito-pvz/pkg/tests"
+)
+
+type countingJob struct {
+	mu   sync.Mutex
+	runs int
+}
+
+func (j *countingJob) Name() string { return "counting" }
+
+func (j *countingJob) Run(ctx context.Context) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.runs++
+	return nil
+}
+
+func (j *countingJob) count() int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.runs
+}
+
+type fakeLocker struct {
+	mu       sync.Mutex
+	free     bool
+	unlocked bool
+}
+
+func (l *fakeLocker) TryLock(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.free, nil
+}
+
+func (l *fakeLocker) Unlock(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.unlocked = true
+	return nil
+}
+
+func (l *fakeLocker) setFree(free bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.free = free
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Condition not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestScheduler_RunsOnlyAsLeader(t *testing.T) {
+	job := &countingJob{}
+	locker := &fakeLocker{}
+	scheduler := NewScheduler(job, 10*time.Millisecond, locker, metrics.NewNoopJobs(), tests.NewTestLogger())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		scheduler.Run(ctx)
+		close(done)
+	}()
+
+	// блокировку держит другая реплика
+	time.Sleep(50 * time.Millisecond)
+	if job.count() != 0 {
+		t.Fatalf("Expected no runs without leadership, got %d", job.count())
+	}
+
+	locker.setFree(true)
+	waitFor(t, func() bool { return job.count() >= 2 })
+
+	cancel()
+	<-done
+
+	if !locker.unlocked {
+		t.Error("Expected lock to be released on stop")
+	}
+}
+
+func TestScheduler_WithoutLockerRunsImmediately(t *testing.T) {
+	job := &countingJob{}
+	scheduler := NewScheduler(job, time.Hour, nil, metrics.NewNoopJobs(), tests.NewTestLogger())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		scheduler.Run(ctx)
+		close(done)
+	}()
+
+	waitFor(t, func() bool { return job.count() == 1 })
+	cancel()
+	<-done
+}
@@ -0,0 +1,126 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/services/pvz"
+	"github.com/dkumancev/avito-pvz/pkg/application/services/reception"
+)
+
+// StaleReceptionsJob - имя задачи обработки зависших приемок в логах и метриках
+const StaleReceptionsJob = "stale_receptions"
+
+// Действия с зависшей приемкой
+const (
+	StaleActionClose = "close" // закрыть от имени системы
+	StaleActionFlag  = "flag"  // только отметить зависшей
+)
+
+// StaleReceptionsConfig - когда приемка считается зависшей и что с ней делать
+type StaleReceptionsConfig struct {
+	After       time.Duration            // возраст по умолчанию
+	AfterByCity map[string]time.Duration // возраст для отдельных городов
+	Action      string                   // StaleActionClose или StaleActionFlag
+}
+
+// StaleReceptions закрывает или отмечает приемки, которые не закрыли вовремя.
+// Незакрытая приемка не дает открыть на ПВЗ новую, поэтому оставленная на ночь
+// приемка блокирует работу пункта
+type StaleReceptions struct {
+	receptions reception.Service
+	pvzs       pvz.Service
+	cfg        StaleReceptionsConfig
+	logger     *slog.Logger
+	now        func() time.Time
+}
+
+func NewStaleReceptions(receptions reception.Service, pvzs pvz.Service, cfg StaleReceptionsConfig, logger *slog.Logger) *StaleReceptions {
+	return &StaleReceptions{
+		receptions: receptions,
+		pvzs:       pvzs,
+		cfg:        cfg,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+func (j *StaleReceptions) Name() string {
+	return StaleReceptionsJob
+}
+
+// Run обрабатывает зависшие приемки. Ошибка одной приемки не останавливает
+// остальные; ошибки возвращаются вместе
+func (j *StaleReceptions) Run(ctx context.Context) error {
+	now := j.now()
+
+	// приемки моложе самого короткого порога не зависли ни в одном городе
+	candidates, err := j.receptions.ListStaleReceptions(ctx, now.Add(-j.minAge()))
+	if err != nil {
+		return err
+	}
+
+	cities := make(map[string]string)
+	var errs []error
+	for _, r := range candidates {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		city, ok := cities[r.PVZID]
+		if !ok {
+			p, err := j.pvzs.GetPVZByID(ctx, r.PVZID)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("приемка %s: %w", r.ID, err))
+				continue
+			}
+			city = p.City
+			cities[r.PVZID] = city
+		}
+
+		maxAge := j.maxAge(city)
+		if now.Sub(r.DateTime) < maxAge {
+			continue
+		}
+
+		err = j.handle(ctx, r.ID, maxAge)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("приемка %s: %w", r.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (j *StaleReceptions) handle(ctx context.Context, receptionID string, maxAge time.Duration) error {
+	var err error
+	if j.cfg.Action == StaleActionFlag {
+		_, err = j.receptions.MarkReceptionStale(ctx, receptionID)
+	} else {
+		reason := fmt.Sprintf("закрыта автоматически: открыта дольше %s", maxAge)
+		_, err = j.receptions.CloseStaleReception(ctx, receptionID, reason)
+	}
+	if errors.Is(err, reception.ErrReceptionNotActive) {
+		// приемку закрыли вручную после выборки
+		return nil
+	}
+	return err
+}
+
+func (j *StaleReceptions) maxAge(city string) time.Duration {
+	if age, ok := j.cfg.AfterByCity[city]; ok {
+		return age
+	}
+	return j.cfg.After
+}
+
+func (j *StaleReceptions) minAge() time.Duration {
+	age := j.cfg.After
+	for _, cityAge := range j.cfg.AfterByCity {
+		age = min(age, cityAge)
+	}
+	return age
+}
@@ -0,0 +1,110 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/services"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/tests"
+)
+
+type staleFixture struct {
+	receptions *tests.MockReceptionRepository
+	business   *tests.MockBusinessMetrics
+	audit      *tests.MockAuditRepository
+	job        *StaleReceptions
+	now        time.Time
+}
+
+func newStaleFixture(cfg StaleReceptionsConfig) *staleFixture {
+	f := &staleFixture{
+		receptions: tests.NewMockReceptionRepository(),
+		business:   tests.NewMockBusinessMetrics(),
+		audit:      tests.NewMockAuditRepository(),
+		now:        time.Date(2024, 6, 2, 9, 0, 0, 0, time.UTC),
+	}
+	pvzRepo := tests.NewMockPVZRepository()
+	pvzRepo.Add(&domain.PVZ{ID: "pvz-msk", City: "Москва"})
+	pvzRepo.Add(&domain.PVZ{ID: "pvz-kzn", City: "Казань"})
+
+	receptionService := services.NewReceptionService(pvzRepo, f.receptions, tests.NewMockProductRepository(),
+		tests.NewMockAssignmentRepository(), f.audit, tests.NewMockOutboxRepository(), tests.NewMockTransactor(), tests.NewTestPolicy(), f.business, tests.NewTestLogger())
+	pvzService := services.NewPVZService(pvzRepo, f.audit, tests.NewMockOutboxRepository(), tests.NewMockTransactor(), f.business, tests.NewTestLogger())
+
+	f.job = NewStaleReceptions(receptionService, pvzService, cfg, tests.NewTestLogger())
+	f.job.now = func() time.Time { return f.now }
+	return f
+}
+
+func (f *staleFixture) addReception(id, pvzID string, age time.Duration) *domain.Reception {
+	r := &domain.Reception{
+		ID:       id,
+		PVZID:    pvzID,
+		DateTime: f.now.Add(-age),
+		Status:   domain.ReceptionStatusInProgress,
+	}
+	f.receptions.Add(r)
+	return r
+}
+
+func TestStaleReceptions_ClosesByCityAge(t *testing.T) {
+	f := newStaleFixture(StaleReceptionsConfig{
+		After:       24 * time.Hour,
+		AfterByCity: map[string]time.Duration{"Москва": 12 * time.Hour},
+		Action:      StaleActionClose,
+	})
+
+	// в Москве порог 12 часов, в Казани - общий, 24 часа
+	moscow := f.addReception("r-msk", "pvz-msk", 14*time.Hour)
+	kazan := f.addReception("r-kzn", "pvz-kzn", 14*time.Hour)
+
+	err := f.job.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if moscow.Status != domain.ReceptionStatusClosed {
+		t.Fatalf("Expected moscow reception to be closed, got %s", moscow.Status)
+	}
+	if moscow.ClosedBy != domain.ReceptionClosedBySystem || moscow.CloseReason == "" || moscow.ClosedAt == nil {
+		t.Errorf("Expected system close with reason, got by=%q reason=%q", moscow.ClosedBy, moscow.CloseReason)
+	}
+	if kazan.Status != domain.ReceptionStatusInProgress {
+		t.Errorf("Expected kazan reception to stay open, got %s", kazan.Status)
+	}
+
+	if f.business.AutoClosed["Москва"] != 1 || f.business.ClosedReceptions["Москва"] != 1 {
+		t.Errorf("Expected auto close metrics for Москва, got %v", f.business.AutoClosed)
+	}
+	if len(f.audit.Entries) != 1 || f.audit.Entries[0].Action != domain.AuditActionReceptionClose {
+		t.Fatalf("Expected one close audit entry, got %d", len(f.audit.Entries))
+	}
+	if f.audit.Entries[0].ActorRole != domain.AuditActorSystem {
+		t.Errorf("Expected system actor, got %s", f.audit.Entries[0].ActorRole)
+	}
+}
+
+func TestStaleReceptions_FlagOnce(t *testing.T) {
+	f := newStaleFixture(StaleReceptionsConfig{After: 24 * time.Hour, Action: StaleActionFlag})
+	r := f.addReception("r-1", "pvz-kzn", 30*time.Hour)
+
+	for i := 0; i < 2; i++ {
+		err := f.job.Run(context.Background())
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+
+	if r.Status != domain.ReceptionStatusInProgress {
+		t.Errorf("Expected reception to stay open, got %s", r.Status)
+	}
+	if r.StaleSince == nil {
+		t.Fatal("Expected reception to be flagged")
+	}
+	// повторный проход не отмечает приемку заново
+	if f.business.MarkedStale["Казань"] != 1 || len(f.audit.Entries) != 1 {
+		t.Errorf("Expected one flag, got metrics %d and audit %d", f.business.MarkedStale["Казань"], len(f.audit.Entries))
+	}
+}
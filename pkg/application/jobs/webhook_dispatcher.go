@@ -0,0 +1,133 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
+	"github.com/dkumancev/avito-pvz/pkg/application/webhooks"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+// WebhookDispatcherJob - имя задачи отправки вебхуков в логах и метриках
+const WebhookDispatcherJob = "webhook_dispatcher"
+
+// WebhookDispatcherConfig - размер пачки и политика повторов
+type WebhookDispatcherConfig struct {
+	BatchSize   int
+	MaxAttempts int           // после стольких неудач доставка попадает в dead
+	BackoffBase time.Duration // задержка перед первым повтором, дальше удваивается
+	BackoffMax  time.Duration
+}
+
+// WebhookDispatcher отправляет доставки, которым пора отправляться, и
+// записывает результат каждой попытки в журнал доставок
+type WebhookDispatcher struct {
+	webhookRepo  repositories.WebhookRepository
+	deliveryRepo repositories.WebhookDeliveryRepository
+	sender       webhooks.Sender
+	cfg          WebhookDispatcherConfig
+	logger       *slog.Logger
+	now          func() time.Time
+}
+
+func NewWebhookDispatcher(webhookRepo repositories.WebhookRepository, deliveryRepo repositories.WebhookDeliveryRepository, sender webhooks.Sender, cfg WebhookDispatcherConfig, logger *slog.Logger) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+		sender:       sender,
+		cfg:          cfg,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+func (j *WebhookDispatcher) Name() string {
+	return WebhookDispatcherJob
+}
+
+// Run отправляет пачки, пока есть доставки, которым пора отправляться.
+// Неудачная доставка откладывается, поэтому в следующую пачку не попадает.
+// Доставки удаленного вебхука пропускаются: их удаляет каскад вместе с ним
+func (j *WebhookDispatcher) Run(ctx context.Context) error {
+	// nil - вебхук удален после выборки пачки
+	subscriptions := make(map[string]*domain.Webhook)
+	for {
+		due, err := j.deliveryRepo.ListDue(ctx, j.now(), j.cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		sent := 0
+		for _, delivery := range due {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			webhook, ok := subscriptions[delivery.WebhookID]
+			if !ok {
+				webhook, err = j.webhookRepo.GetByID(ctx, delivery.WebhookID)
+				if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+					return err
+				}
+				subscriptions[delivery.WebhookID] = webhook
+			}
+			if webhook == nil {
+				j.logger.InfoContext(ctx, "Вебхук удален, доставка пропущена",
+					"webhook_id", delivery.WebhookID, "delivery_id", delivery.ID)
+				continue
+			}
+
+			err = j.deliver(ctx, webhook, delivery)
+			if err != nil {
+				return err
+			}
+			sent++
+		}
+
+		// пропущенные доставки снова попадут в выборку, пока их не удалит каскад
+		if len(due) < j.cfg.BatchSize || sent == 0 {
+			return nil
+		}
+	}
+}
+
+// deliver выполняет одну попытку и сохраняет ее результат. Ошибка получателя
+// не прерывает задачу - прерывает только ошибка сохранения
+func (j *WebhookDispatcher) deliver(ctx context.Context, webhook *domain.Webhook, delivery *domain.WebhookDelivery) error {
+	statusCode, sendErr := j.sender.Send(ctx, webhooks.Request{
+		URL:        webhook.URL,
+		Secret:     webhook.Secret,
+		DeliveryID: delivery.ID,
+		EventID:    delivery.EventID,
+		EventType:  delivery.EventType,
+		Body:       delivery.Payload,
+	})
+
+	now := j.now()
+	logger := j.logger.With("webhook_id", webhook.ID, "delivery_id", delivery.ID, "event_type", delivery.EventType)
+	if sendErr == nil {
+		delivery.MarkDelivered(statusCode, now)
+		logger.DebugContext(ctx, "Вебхук доставлен", "status_code", statusCode)
+	} else {
+		delay := webhooks.Backoff(delivery.Attempts+1, j.cfg.BackoffBase, j.cfg.BackoffMax)
+		delivery.MarkFailed(statusCode, sendErr.Error(), now.Add(delay), j.cfg.MaxAttempts)
+		if delivery.Status == domain.DeliveryDead {
+			logger.WarnContext(ctx, "Доставка вебхука исчерпала попытки",
+				"attempts", delivery.Attempts, "error", sendErr)
+		} else {
+			logger.InfoContext(ctx, "Доставка вебхука не удалась, повтор запланирован",
+				"attempts", delivery.Attempts, "retry_in", delay, "error", sendErr)
+		}
+	}
+
+	err := j.deliveryRepo.Update(ctx, delivery)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения результата доставки: %w", err)
+	}
+
+	return nil
+}
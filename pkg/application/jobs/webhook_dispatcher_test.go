@@ -0,0 +1,148 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/webhooks"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/tests"
+)
+
+// scriptedSender возвращает ответы по порядку, затем - успех
+type scriptedSender struct {
+	failures []int
+	requests []webhooks.Request
+}
+
+func (s *scriptedSender) Send(ctx context.Context, req webhooks.Request) (int, error) {
+	s.requests = append(s.requests, req)
+	if len(s.failures) > 0 {
+		status := s.failures[0]
+		s.failures = s.failures[1:]
+		return status, errors.New("получатель недоступен")
+	}
+	return 200, nil
+}
+
+func newDispatcherFixture(t *testing.T, sender webhooks.Sender, maxAttempts int) (*WebhookDispatcher, *tests.MockWebhookDeliveryRepository, *time.Time) {
+	t.Helper()
+	webhookRepo := tests.NewMockWebhookRepository()
+	deliveryRepo := tests.NewMockWebhookDeliveryRepository()
+
+	webhook, _ := domain.NewWebhook("https://partner.example/hooks", []string{domain.EventReceptionClosed},
+		"0123456789abcdef", "")
+	webhookRepo.Create(context.Background(), webhook)
+
+	event, _ := domain.NewEvent(domain.EventReceptionClosed, domain.AuditEntityReception, "reception-1", map[string]string{})
+	event.ID = "event-1"
+	err := webhooks.NewEnqueuer(webhookRepo, deliveryRepo).Publish(context.Background(), []*domain.Event{event})
+	if err != nil {
+		t.Fatalf("Ошибка постановки доставки: %v", err)
+	}
+
+	now := time.Now()
+	dispatcher := NewWebhookDispatcher(webhookRepo, deliveryRepo, sender, WebhookDispatcherConfig{
+		BatchSize:   10,
+		MaxAttempts: maxAttempts,
+		BackoffBase: time.Minute,
+		BackoffMax:  time.Hour,
+	}, tests.NewTestLogger())
+	dispatcher.now = func() time.Time { return now }
+	return dispatcher, deliveryRepo, &now
+}
+
+func TestWebhookDispatcher_RetriesWithBackoff(t *testing.T) {
+	sender := &scriptedSender{failures: []int{503, 0}}
+	dispatcher, deliveries, now := newDispatcherFixture(t, sender, 5)
+	delivery := deliveries.Deliveries[0]
+
+	// первая попытка неудачна - повтор через минуту
+	err := dispatcher.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Ошибка получателя не должна прерывать задачу: %v", err)
+	}
+	if delivery.Attempts != 1 || delivery.LastStatusCode != 503 || !delivery.NextAttemptAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("Ожидался повтор через минуту, получено: %+v", delivery)
+	}
+
+	// до срока повтора доставка не отправляется
+	dispatcher.Run(context.Background())
+	if len(sender.requests) != 1 {
+		t.Fatalf("Доставка отправлена раньше срока: %d запросов", len(sender.requests))
+	}
+
+	// вторая неудача - задержка удваивается
+	*now = now.Add(time.Minute)
+	dispatcher.Run(context.Background())
+	if delivery.Attempts != 2 || !delivery.NextAttemptAt.Equal(now.Add(2*time.Minute)) {
+		t.Fatalf("Ожидался повтор через две минуты, получено: %+v", delivery)
+	}
+
+	*now = now.Add(2 * time.Minute)
+	dispatcher.Run(context.Background())
+	if delivery.Status != domain.DeliveryDelivered || delivery.Attempts != 3 || delivery.LastError != "" {
+		t.Errorf("Ожидалась успешная доставка, получено: %+v", delivery)
+	}
+
+	req := sender.requests[0]
+	if req.URL != "https://partner.example/hooks" || req.EventID != "event-1" || req.DeliveryID != delivery.ID {
+		t.Errorf("Неверный запрос доставки: %+v", req)
+	}
+}
+
+func TestWebhookDispatcher_DeadLetterAfterMaxAttempts(t *testing.T) {
+	sender := &scriptedSender{failures: []int{500, 500}}
+	dispatcher, deliveries, now := newDispatcherFixture(t, sender, 2)
+	delivery := deliveries.Deliveries[0]
+
+	dispatcher.Run(context.Background())
+	*now = now.Add(time.Hour)
+	dispatcher.Run(context.Background())
+
+	if delivery.Status != domain.DeliveryDead || delivery.Attempts != 2 {
+		t.Fatalf("Ожидалась доставка в dead после двух попыток, получено: %+v", delivery)
+	}
+
+	*now = now.Add(time.Hour)
+	dispatcher.Run(context.Background())
+	if len(sender.requests) != 2 {
+		t.Errorf("Доставка в dead не должна отправляться, запросов: %d", len(sender.requests))
+	}
+}
+
+// Вебхук удален после выборки пачки, но до отправки: его доставки
+// пропускаются, остальные отправляются
+func TestWebhookDispatcher_SkipsDeletedWebhook(t *testing.T) {
+	ctx := context.Background()
+	webhookRepo := tests.NewMockWebhookRepository()
+	deliveryRepo := tests.NewMockWebhookDeliveryRepository()
+
+	deleted, _ := domain.NewWebhook("https://a.example/hooks", []string{domain.EventReceptionClosed}, "0123456789abcdef", "")
+	kept, _ := domain.NewWebhook("https://b.example/hooks", []string{domain.EventReceptionClosed}, "0123456789abcdef", "")
+	webhookRepo.Create(ctx, deleted)
+	webhookRepo.Create(ctx, kept)
+
+	event, _ := domain.NewEvent(domain.EventReceptionClosed, domain.AuditEntityReception, "reception-1", map[string]string{})
+	event.ID = "event-1"
+	err := webhooks.NewEnqueuer(webhookRepo, deliveryRepo).Publish(ctx, []*domain.Event{event})
+	if err != nil {
+		t.Fatalf("Ошибка постановки доставки: %v", err)
+	}
+	// доставки остаются: каскад в БД удалит их позже
+	webhookRepo.Delete(ctx, deleted.ID)
+
+	sender := &scriptedSender{}
+	dispatcher := NewWebhookDispatcher(webhookRepo, deliveryRepo, sender,
+		WebhookDispatcherConfig{BatchSize: 10, MaxAttempts: 3}, tests.NewTestLogger())
+
+	err = dispatcher.Run(ctx)
+	if err != nil {
+		t.Fatalf("Удаленный вебхук не должен прерывать задачу: %v", err)
+	}
+	if len(sender.requests) != 1 || sender.requests[0].URL != "https://b.example/hooks" {
+		t.Errorf("Ожидалась отправка только оставшемуся вебхуку: %+v", sender.requests)
+	}
+}
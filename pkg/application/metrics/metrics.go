@@ -0,0 +1,79 @@
+// Package metrics описывает бизнес-метрики, которые сервисы сообщают о своих операциях.
+// Реализация (Prometheus) находится в слое инфраструктуры
+package metrics
+
+import "time"
+
+// BusinessMetrics фиксирует бизнес-события. Вызывается сервисами после
+// успешного сохранения изменений, поэтому учитываются и HTTP, и gRPC вызовы
+type BusinessMetrics interface {
+	// создан ПВЗ
+	PVZCreated(city string)
+
+	// открыта приемка
+	ReceptionOpened(city string)
+
+	// закрыта приемка; duration - время от открытия до закрытия
+	ReceptionClosed(city string, duration time.Duration)
+
+	// добавлен товар в приемку
+	ProductAdded(city, productType string)
+
+	// удален последний товар приемки
+	ProductRemoved(city, productType string)
+
+	// зависшая приемка закрыта системой
+	ReceptionAutoClosed(city string)
+
+	// зависшая приемка отмечена без закрытия
+	ReceptionMarkedStale(city string)
+}
+
+// JobMetrics фиксирует работу фоновых задач
+type JobMetrics interface {
+	// завершен проход задачи; err - ошибка прохода или nil
+	JobRun(job string, duration time.Duration, err error)
+
+	// реплика стала лидером задачи (leader = true) или перестала им быть
+	JobLeader(job string, leader bool)
+}
+
+// RateLimitMetrics фиксирует отклоненные ограничением частоты запросы
+type RateLimitMetrics interface {
+	// запрос отклонен; transport - http или grpc, subject - user, api_key или ip
+	Throttled(transport, route, subject string)
+}
+
+type noop struct{}
+
+// NewNoop возвращает реализацию, которая ничего не записывает (для тестов и утилит)
+func NewNoop() BusinessMetrics {
+	return noop{}
+}
+
+func (noop) PVZCreated(city string)                              {}
+func (noop) ReceptionOpened(city string)                         {}
+func (noop) ReceptionClosed(city string, duration time.Duration) {}
+func (noop) ProductAdded(city, productType string)               {}
+func (noop) ProductRemoved(city, productType string)             {}
+func (noop) ReceptionAutoClosed(city string)                     {}
+func (noop) ReceptionMarkedStale(city string)                    {}
+
+type noopJobs struct{}
+
+// NewNoopJobs возвращает метрики фоновых задач, которые ничего не записывают
+func NewNoopJobs() JobMetrics {
+	return noopJobs{}
+}
+
+func (noopJobs) JobRun(job string, duration time.Duration, err error) {}
+func (noopJobs) JobLeader(job string, leader bool)                    {}
+
+type noopRateLimit struct{}
+
+// NewNoopRateLimit возвращает метрики ограничения частоты, которые ничего не записывают
+func NewNoopRateLimit() RateLimitMetrics {
+	return noopRateLimit{}
+}
+
+func (noopRateLimit) Throttled(transport, route, subject string) {}
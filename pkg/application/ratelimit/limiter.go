@@ -0,0 +1,148 @@
+// Package ratelimit ограничивает частоту запросов алгоритмом token bucket.
+// Ограничение считается отдельно для каждой пары маршрут + вызывающая сторона
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit - не больше Requests запросов за Period. Все Requests можно выполнить
+// сразу, после чего запас восстанавливается равномерно в течение Period.
+// Нулевой Limit не ограничивает запросы
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// Unlimited сообщает, что ограничение отключено
+func (l Limit) Unlimited() bool {
+	return l.Requests <= 0 || l.Period <= 0
+}
+
+// rate - скорость восстановления запаса в запросах за секунду
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// Config - ограничения по умолчанию и для отдельных маршрутов
+type Config struct {
+	Default Limit            // для пользователей и API-ключей
+	Public  Limit            // для запросов без аутентификации, по IP
+	Routes  map[string]Limit // маршрут ("POST /products" или полное имя gRPC метода) - ограничение
+}
+
+// Decision - результат проверки запроса
+type Decision struct {
+	Allowed    bool
+	Limit      Limit
+	Remaining  int           // сколько запросов еще можно выполнить сразу
+	Reset      time.Duration // через сколько запас восстановится полностью
+	RetryAfter time.Duration // через сколько можно повторить отклоненный запрос
+}
+
+// sweepInterval - как часто удаляются корзины, запас которых восстановился
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	limit  Limit
+}
+
+// Limiter хранит корзины в памяти процесса: на каждой реплике ограничение
+// считается отдельно
+type Limiter struct {
+	cfg Config
+	now func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func New(cfg Config) *Limiter {
+	return &Limiter{
+		cfg:     cfg,
+		now:     time.Now,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// LimitFor возвращает ограничение маршрута для вызывающей стороны
+func (l *Limiter) LimitFor(route string, subject Subject) Limit {
+	if limit, ok := l.cfg.Routes[route]; ok {
+		return limit
+	}
+	if subject.Kind == SubjectIP {
+		return l.cfg.Public
+	}
+	return l.cfg.Default
+}
+
+// Allow расходует один запрос из корзины маршрута и вызывающей стороны
+func (l *Limiter) Allow(route string, subject Subject) Decision {
+	return l.take(route, subject, 1)
+}
+
+// Check проверяет, остался ли запас в корзине, не расходуя его. Вместе с
+// Allow позволяет списывать запрос только после того, как известен его исход
+func (l *Limiter) Check(route string, subject Subject) Decision {
+	return l.take(route, subject, 0)
+}
+
+func (l *Limiter) take(route string, subject Subject, cost float64) Decision {
+	limit := l.LimitFor(route, subject)
+	if limit.Unlimited() {
+		return Decision{Allowed: true, Limit: limit}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	key := route + " " + subject.String()
+	b, ok := l.buckets[key]
+	if !ok || b.limit != limit {
+		b = &bucket{tokens: float64(limit.Requests), last: now, limit: limit}
+		l.buckets[key] = b
+	}
+
+	rate := limit.rate()
+	b.tokens = math.Min(float64(limit.Requests), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	decision := Decision{Limit: limit}
+	if b.tokens >= 1 {
+		b.tokens -= cost
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = secondsToDuration((1 - b.tokens) / rate)
+	}
+	decision.Remaining = int(b.tokens)
+	decision.Reset = secondsToDuration((float64(limit.Requests) - b.tokens) / rate)
+
+	return decision
+}
+
+// sweep удаляет корзины с полным запасом: новая корзина для той же
+// стороны ничем от них не отличается
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= b.limit.Period {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(math.Ceil(seconds * float64(time.Second)))
+}
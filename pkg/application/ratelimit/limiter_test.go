@@ -0,0 +1,94 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLimiter(cfg Config) (*Limiter, *time.Time) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	limiter := New(cfg)
+	limiter.now = func() time.Time { return now }
+	return limiter, &now
+}
+
+func TestLimiter_TokenBucket(t *testing.T) {
+	limiter, now := newTestLimiter(Config{Default: Limit{Requests: 2, Period: 2 * time.Second}})
+	user := Subject{Kind: SubjectUser, ID: "u1"}
+
+	first := limiter.Allow("POST /products", user)
+	require.True(t, first.Allowed)
+	assert.Equal(t, 1, first.Remaining)
+	assert.Equal(t, time.Second, first.Reset)
+
+	require.True(t, limiter.Allow("POST /products", user).Allowed)
+
+	denied := limiter.Allow("POST /products", user)
+	require.False(t, denied.Allowed)
+	assert.Equal(t, 0, denied.Remaining)
+	assert.Equal(t, time.Second, denied.RetryAfter)
+	assert.Equal(t, 2*time.Second, denied.Reset)
+
+	// у другого пользователя и другого маршрута своя корзина
+	assert.True(t, limiter.Allow("POST /products", Subject{Kind: SubjectUser, ID: "u2"}).Allowed)
+	assert.True(t, limiter.Allow("POST /receptions", user).Allowed)
+
+	// запас восстанавливается со скоростью 1 запрос в секунду
+	*now = now.Add(500 * time.Millisecond)
+	halfway := limiter.Allow("POST /products", user)
+	require.False(t, halfway.Allowed)
+	assert.Equal(t, 500*time.Millisecond, halfway.RetryAfter)
+
+	*now = now.Add(500 * time.Millisecond)
+	assert.True(t, limiter.Allow("POST /products", user).Allowed)
+}
+
+func TestLimiter_LimitFor(t *testing.T) {
+	limiter, _ := newTestLimiter(Config{
+		Default: Limit{Requests: 20, Period: time.Second},
+		Public:  Limit{Requests: 5, Period: time.Minute},
+		Routes: map[string]Limit{
+			"POST /products": {Requests: 10, Period: time.Second},
+			"GET /pvz":       {},
+		},
+	})
+	user := Subject{Kind: SubjectUser, ID: "u1"}
+	ip := Subject{Kind: SubjectIP, ID: "10.0.0.1"}
+
+	assert.Equal(t, Limit{Requests: 20, Period: time.Second}, limiter.LimitFor("POST /receptions", user))
+	assert.Equal(t, Limit{Requests: 5, Period: time.Minute}, limiter.LimitFor("POST /login", ip))
+	assert.Equal(t, Limit{Requests: 10, Period: time.Second}, limiter.LimitFor("POST /products", user))
+
+	for i := 0; i < 100; i++ {
+		require.True(t, limiter.Allow("GET /pvz", user).Allowed, "нулевое ограничение не ограничивает")
+	}
+}
+
+func TestLimiter_SweepsFullBuckets(t *testing.T) {
+	limiter, now := newTestLimiter(Config{Default: Limit{Requests: 1, Period: time.Second}})
+
+	limiter.Allow("POST /products", Subject{Kind: SubjectUser, ID: "u1"})
+	limiter.Allow("POST /products", Subject{Kind: SubjectUser, ID: "u2"})
+	require.Len(t, limiter.buckets, 2)
+
+	*now = now.Add(2 * sweepInterval)
+	limiter.Allow("POST /products", Subject{Kind: SubjectUser, ID: "u3"})
+	assert.Len(t, limiter.buckets, 1)
+}
+
+func TestLimiter_CheckDoesNotConsume(t *testing.T) {
+	limiter, _ := newTestLimiter(Config{Public: Limit{Requests: 1, Period: time.Minute}})
+	ip := Subject{Kind: SubjectIP, ID: "10.0.0.1"}
+
+	for i := 0; i < 3; i++ {
+		require.True(t, limiter.Check("auth", ip).Allowed, "проверка не расходует запас")
+	}
+
+	require.True(t, limiter.Allow("auth", ip).Allowed)
+	denied := limiter.Check("auth", ip)
+	assert.False(t, denied.Allowed)
+	assert.Equal(t, time.Minute, denied.RetryAfter)
+}
@@ -0,0 +1,38 @@
+package ratelimit
+
+import (
+	"context"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/auth"
+)
+
+// SubjectKind - по какому признаку считается ограничение
+type SubjectKind string
+
+const (
+	SubjectUser   SubjectKind = "user"    // пользователь из JWT
+	SubjectAPIKey SubjectKind = "api_key" // внешняя система
+	SubjectIP     SubjectKind = "ip"      // запрос без аутентификации
+)
+
+// Subject - вызывающая сторона, для которой считается ограничение
+type Subject struct {
+	Kind SubjectKind
+	ID   string
+}
+
+func (s Subject) String() string {
+	return string(s.Kind) + ":" + s.ID
+}
+
+// SubjectFromContext возвращает пользователя или API-ключ из контекста.
+// Если вызов не аутентифицирован, ограничение считается по IP клиента
+func SubjectFromContext(ctx context.Context, clientIP string) Subject {
+	if principal, ok := auth.ServicePrincipalFromContext(ctx); ok {
+		return Subject{Kind: SubjectAPIKey, ID: principal.KeyID}
+	}
+	if user, ok := auth.UserFromContext(ctx); ok {
+		return Subject{Kind: SubjectUser, ID: user.ID}
+	}
+	return Subject{Kind: SubjectIP, ID: clientIP}
+}
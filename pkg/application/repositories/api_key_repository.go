@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error)
+
+	GetByID(ctx context.Context, id string) (*domain.APIKey, error)
+
+	GetByHash(ctx context.Context, keyHash string) (*domain.APIKey, error)
+
+	List(ctx context.Context) ([]*domain.APIKey, error)
+
+	Update(ctx context.Context, key *domain.APIKey) error
+
+	UpdateLastUsed(ctx context.Context, id string, usedAt time.Time) error
+}
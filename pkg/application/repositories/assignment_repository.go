@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+type AssignmentRepository interface {
+	Create(ctx context.Context, assignment *domain.Assignment) (*domain.Assignment, error)
+
+	Delete(ctx context.Context, userID, pvzID string) error
+
+	GetByPVZID(ctx context.Context, pvzID string) ([]*domain.Assignment, error)
+
+	Exists(ctx context.Context, userID, pvzID string) (bool, error)
+}
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+type AuditRepository interface {
+	Create(ctx context.Context, entry *domain.AuditEntry) error
+
+	List(ctx context.Context, filter AuditFilter) ([]*domain.AuditEntry, error)
+}
+
+// параметры фильтрации журнала аудита; пустые поля не ограничивают выборку
+type AuditFilter struct {
+	ActorID    string
+	EntityType string
+	EntityID   string
+	From       *time.Time
+	To         *time.Time
+	Page       int
+	Limit      int
+}
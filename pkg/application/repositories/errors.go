@@ -0,0 +1,7 @@
+package repositories
+
+import "errors"
+
+// ErrNotFound - запись не найдена. Репозитории оборачивают ее в сообщение
+// с сущностью, поэтому проверяется через errors.Is
+var ErrNotFound = errors.New("запись не найдена")
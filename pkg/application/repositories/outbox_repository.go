@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+// OutboxRepository хранит доменные события до публикации
+type OutboxRepository interface {
+	// Add добавляет событие. Вызывается в транзакции изменения
+	Add(ctx context.Context, event *domain.Event) error
+
+	// ListPending возвращает до limit неопубликованных событий в порядке
+	// записи. В транзакции события блокируются до ее завершения
+	ListPending(ctx context.Context, limit int) ([]*domain.Event, error)
+
+	// MarkPublished отмечает события опубликованными
+	MarkPublished(ctx context.Context, ids []string) error
+
+	// MarkFailed учитывает неудачную попытку публикации событий
+	MarkFailed(ctx context.Context, ids []string, reason string) error
+
+	// DeletePublishedBefore удаляет события, опубликованные раньше before.
+	// Возвращает количество удаленных событий
+	DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
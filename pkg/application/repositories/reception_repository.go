@@ -2,18 +2,28 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/dkumancev/avito-pvz/pkg/domain"
 )
 
+// ErrReceptionNotActive - приемку закрыли параллельно, изменение не применено
+var ErrReceptionNotActive = errors.New("приемка уже закрыта")
+
 type ReceptionRepository interface {
 	Create(ctx context.Context, reception *domain.Reception) (*domain.Reception, error)
 
 	GetByID(ctx context.Context, id string) (*domain.Reception, error)
 
+	// Update сохраняет изменения приемки, только если она еще не закрыта.
+	// Иначе возвращает ErrReceptionNotActive
 	Update(ctx context.Context, reception *domain.Reception) error
 
 	GetLastActiveByPVZID(ctx context.Context, pvzID string) (*domain.Reception, error)
 
 	GetByPVZID(ctx context.Context, pvzID string) ([]*domain.Reception, error)
+
+	// ListActiveBefore возвращает незакрытые приемки, открытые раньше before, без товаров
+	ListActiveBefore(ctx context.Context, before time.Time) ([]*domain.Reception, error)
 }
@@ -0,0 +1,10 @@
+package repositories
+
+import "context"
+
+// Transactor выполняет функцию в одной транзакции БД. Репозитории, вызванные
+// с контекстом, переданным в fn, работают в этой транзакции. Ошибка fn
+// откатывает транзакцию; вложенные вызовы используют внешнюю транзакцию
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+type TwoFactorRepository interface {
+	// Получение настроек 2FA пользователя. Возвращает nil без ошибки, если привязки нет
+	GetByUserID(ctx context.Context, userID string) (*domain.TwoFactor, error)
+
+	// Создание или обновление настроек 2FA
+	Save(ctx context.Context, twoFactor *domain.TwoFactor) error
+
+	// Атомарная фиксация принятого TOTP интервала. Возвращает false, если
+	// интервал не новее уже использованного (код принят параллельным запросом)
+	UseStep(ctx context.Context, userID string, step int64) (bool, error)
+
+	// Атомарное погашение кода восстановления. Возвращает false, если кода нет
+	UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error)
+
+	// Учет попытки ввода кода по промежуточному токену входа.
+	// Возвращает число попыток с учетом текущей
+	RegisterChallengeAttempt(ctx context.Context, challengeID, userID string, expiresAt time.Time) (int, error)
+}
@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error)
+
+	GetByID(ctx context.Context, id string) (*domain.Webhook, error)
+
+	List(ctx context.Context) ([]*domain.Webhook, error)
+
+	// ListByEventType возвращает вебхуки, подписанные на тип события
+	ListByEventType(ctx context.Context, eventType string) ([]*domain.Webhook, error)
+
+	// Delete удаляет вебхук вместе с журналом его доставок
+	Delete(ctx context.Context, id string) error
+}
+
+type WebhookDeliveryRepository interface {
+	// Create добавляет доставку. Повтор доставки того же события тому же
+	// вебхуку игнорируется
+	Create(ctx context.Context, delivery *domain.WebhookDelivery) error
+
+	GetByID(ctx context.Context, id string) (*domain.WebhookDelivery, error)
+
+	// ListDue возвращает ожидающие доставки с next_attempt_at не позже now,
+	// начиная с самых давних
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*domain.WebhookDelivery, error)
+
+	// List возвращает журнал доставок вебхука, начиная с последних
+	List(ctx context.Context, filter WebhookDeliveryFilter) ([]*domain.WebhookDelivery, error)
+
+	// Update сохраняет результат попытки или повторную постановку в очередь
+	Update(ctx context.Context, delivery *domain.WebhookDelivery) error
+}
+
+// параметры выборки журнала доставок; пустой Status не ограничивает выборку
+type WebhookDeliveryFilter struct {
+	WebhookID string
+	Status    string
+	Page      int
+	Limit     int
+}
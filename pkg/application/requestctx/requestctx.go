@@ -0,0 +1,101 @@
+// Package requestctx хранит сведения о текущем запросе (идентификатор запроса,
+// пользователь, ПВЗ), которые добавляются к каждой записи лога.
+// Сведения заполняются по мере обработки: транспорт задает ID запроса,
+// аутентификация - пользователя, сервисы - ПВЗ
+package requestctx
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// maxIDLength - максимальная длина принимаемого от клиента ID запроса
+const maxIDLength = 128
+
+type contextKey struct{}
+
+// Values - снимок сведений о запросе
+type Values struct {
+	RequestID string
+	UserID    string
+	PVZID     string
+}
+
+// fields изменяемы, чтобы сведения, найденные во вложенных обработчиках
+// (например, пользователь после аутентификации), попадали и в итоговую запись
+// лога внешнего middleware
+type fields struct {
+	mu     sync.RWMutex
+	values Values
+}
+
+// New возвращает контекст с идентификатором запроса
+func New(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, &fields{values: Values{RequestID: requestID}})
+}
+
+// NewID генерирует новый идентификатор запроса
+func NewID() string {
+	return uuid.New().String()
+}
+
+// IsValidID проверяет идентификатор запроса, пришедший от клиента.
+// Допускаются непустые строки до 128 символов из букв, цифр и символов -_.:
+func IsValidID(id string) bool {
+	if id == "" || len(id) > maxIDLength {
+		return false
+	}
+
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// RequestID возвращает идентификатор текущего запроса или пустую строку
+func RequestID(ctx context.Context) string {
+	values, _ := FromContext(ctx)
+	return values.RequestID
+}
+
+// FromContext возвращает сведения о запросе. Для контекста без запроса
+// (фоновые задачи, тесты) возвращает false
+func FromContext(ctx context.Context) (Values, bool) {
+	f, ok := ctx.Value(contextKey{}).(*fields)
+	if !ok {
+		return Values{}, false
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.values, true
+}
+
+// SetUserID запоминает пользователя, выполняющего запрос
+func SetUserID(ctx context.Context, userID string) {
+	set(ctx, func(v *Values) { v.UserID = userID })
+}
+
+// SetPVZID запоминает ПВЗ, с которым работает запрос
+func SetPVZID(ctx context.Context, pvzID string) {
+	set(ctx, func(v *Values) { v.PVZID = pvzID })
+}
+
+func set(ctx context.Context, update func(v *Values)) {
+	f, ok := ctx.Value(contextKey{}).(*fields)
+	if !ok {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	update(&f.values)
+}
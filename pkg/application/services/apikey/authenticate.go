@@ -0,0 +1,55 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/auth"
+	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
+)
+
+// lastUsedInterval - как часто обновляется время последнего использования
+// ключа: точнее для журнала не нужно, а запись на каждый запрос нагружает БД
+const lastUsedInterval = time.Minute
+
+func (s *service) Authenticate(ctx context.Context, rawKey string) (*auth.ServicePrincipal, error) {
+	if !strings.HasPrefix(rawKey, keyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := s.apiKeyRepo.GetByHash(ctx, hashKey(rawKey))
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, fmt.Errorf("ошибка получения API-ключа: %w", err)
+	}
+
+	now := time.Now()
+	if !key.IsActive(now) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	// время использования справочное, поэтому ошибка записи не отклоняет запрос
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedInterval {
+		err = s.apiKeyRepo.UpdateLastUsed(ctx, key.ID, now)
+		if err != nil {
+			s.logger.WarnContext(ctx, "Не удалось обновить время использования API-ключа",
+				"key_id", key.ID, "error", err)
+		}
+	}
+
+	scopes := make([]auth.Permission, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		scopes = append(scopes, auth.Permission(scope))
+	}
+
+	return &auth.ServicePrincipal{
+		KeyID:  key.ID,
+		Name:   key.Name,
+		Scopes: scopes,
+	}, nil
+}
@@ -0,0 +1,104 @@
+package apikey
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/audit"
+	"github.com/dkumancev/avito-pvz/pkg/application/auth"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+func (s *service) CreateKey(ctx context.Context, name string, scopes []auth.Permission, expiresAt *time.Time) (string, *domain.APIKey, error) {
+	scopeNames := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !auth.ValidPermissions[scope] {
+			return "", nil, fmt.Errorf("%w: %s", ErrInvalidScope, scope)
+		}
+		if !auth.ServiceScope(scope) || !s.canGrant(ctx, scope) {
+			return "", nil, fmt.Errorf("%w: %s", ErrScopeNotAllowed, scope)
+		}
+		scopeNames = append(scopeNames, string(scope))
+	}
+
+	rawKey, keyHash, err := generateKey()
+	if err != nil {
+		return "", nil, fmt.Errorf("ошибка генерации API-ключа: %w", err)
+	}
+
+	var createdBy string
+	if user, ok := auth.UserFromContext(ctx); ok {
+		createdBy = user.ID
+	}
+
+	key, err := domain.NewAPIKey(name, rawKey[:len(keyPrefix)+8], keyHash, scopeNames, createdBy, expiresAt)
+	if err != nil {
+		return "", nil, fmt.Errorf("ошибка создания API-ключа: %w", err)
+	}
+
+	var savedKey *domain.APIKey
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		savedKey, err = s.apiKeyRepo.Create(ctx, key)
+		if err != nil {
+			return fmt.Errorf("ошибка сохранения API-ключа: %w", err)
+		}
+
+		return audit.Record(ctx, s.auditRepo, domain.AuditActionAPIKeyCreate, domain.AuditEntityAPIKey, savedKey.ID, nil, savedKey)
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return rawKey, savedKey, nil
+}
+
+// canGrant проверяет, что создатель ключа сам обладает правом: пользователь -
+// по своей роли, внешняя система - по правам своего ключа. Внутренние вызовы
+// без аутентификации (pvzctl) не ограничиваются
+func (s *service) canGrant(ctx context.Context, scope auth.Permission) bool {
+	if user, ok := auth.UserFromContext(ctx); ok {
+		return s.policy.HasPermission(user.Role, scope)
+	}
+	if principal, ok := auth.ServicePrincipalFromContext(ctx); ok {
+		return principal.HasScope(scope)
+	}
+	return true
+}
+
+func (s *service) ListKeys(ctx context.Context) ([]*domain.APIKey, error) {
+	keys, err := s.apiKeyRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения API-ключей: %w", err)
+	}
+
+	return keys, nil
+}
+
+func (s *service) RevokeKey(ctx context.Context, id string) (*domain.APIKey, error) {
+	key, err := s.apiKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения API-ключа: %w", err)
+	}
+
+	before := *key
+
+	err = key.Revoke()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка отзыва API-ключа: %w", err)
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.apiKeyRepo.Update(ctx, key)
+		if err != nil {
+			return fmt.Errorf("ошибка сохранения API-ключа: %w", err)
+		}
+
+		return audit.Record(ctx, s.auditRepo, domain.AuditActionAPIKeyRevoke, domain.AuditEntityAPIKey, key.ID, before, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
@@ -0,0 +1,81 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/auth"
+	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+// префикс, по которому API-ключи сервиса легко опознать в конфигурации клиентов
+const keyPrefix = "pvz_"
+
+var (
+	ErrInvalidAPIKey = auth.ErrInvalidAPIKey
+	ErrInvalidScope  = errors.New("неизвестное право API-ключа")
+
+	// ErrScopeNotAllowed - право нельзя выдать ключу: его нет у создателя
+	// или оно относится к операциям в ПВЗ, которые требуют закрепления
+	ErrScopeNotAllowed = errors.New("право недоступно для API-ключа")
+)
+
+type Service interface {
+	// Выпуск нового ключа. Открытое значение ключа возвращается только один раз
+	CreateKey(ctx context.Context, name string, scopes []auth.Permission, expiresAt *time.Time) (string, *domain.APIKey, error)
+
+	// Список выпущенных ключей
+	ListKeys(ctx context.Context) ([]*domain.APIKey, error)
+
+	// Отзыв ключа
+	RevokeKey(ctx context.Context, id string) (*domain.APIKey, error)
+
+	// Проверка ключа и получение сервисного принципала
+	Authenticate(ctx context.Context, rawKey string) (*auth.ServicePrincipal, error)
+}
+
+type service struct {
+	apiKeyRepo repositories.APIKeyRepository
+	auditRepo  repositories.AuditRepository
+	transactor repositories.Transactor
+	policy     *auth.Policy
+	logger     *slog.Logger
+}
+
+func New(
+	apiKeyRepo repositories.APIKeyRepository,
+	auditRepo repositories.AuditRepository,
+	transactor repositories.Transactor,
+	policy *auth.Policy,
+	logger *slog.Logger,
+) Service {
+	return &service{
+		apiKeyRepo: apiKeyRepo,
+		auditRepo:  auditRepo,
+		transactor: transactor,
+		policy:     policy,
+		logger:     logger,
+	}
+}
+
+// generateKey создает случайный ключ и его хэш для хранения
+func generateKey() (string, string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+
+	rawKey := keyPrefix + hex.EncodeToString(secret)
+	return rawKey, hashKey(rawKey), nil
+}
+
+func hashKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
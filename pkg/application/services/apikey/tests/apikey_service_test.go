@@ -0,0 +1,271 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/auth"
+	"github.com/dkumancev/avito-pvz/pkg/application/services"
+	"github.com/dkumancev/avito-pvz/pkg/application/services/apikey"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/tests"
+)
+
+func TestAPIKeyService_CreateAndAuthenticate(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := tests.NewMockAPIKeyRepository()
+	service := services.NewAPIKeyService(mockRepo, tests.NewMockAuditRepository(), tests.NewMockTransactor(), tests.NewTestPolicy(), tests.NewTestLogger())
+
+	// Act
+	rawKey, key, err := service.CreateKey(ctx, "warehouse", []auth.Permission{auth.PermissionPVZRead}, nil)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !strings.HasPrefix(rawKey, key.Prefix) {
+		t.Errorf("Expected key %q to start with prefix %q", rawKey, key.Prefix)
+	}
+	if key.KeyHash == "" || strings.Contains(key.KeyHash, rawKey) {
+		t.Error("Expected only the key hash to be stored")
+	}
+
+	principal, err := service.Authenticate(ctx, rawKey)
+	if err != nil {
+		t.Fatalf("Expected no error when authenticating, got: %v", err)
+	}
+	if principal.KeyID != key.ID || principal.Name != "warehouse" {
+		t.Errorf("Unexpected principal: %+v", principal)
+	}
+	if !principal.HasScope(auth.PermissionPVZRead) {
+		t.Error("Expected principal to have pvz:read scope")
+	}
+	if principal.HasScope(auth.PermissionPVZCreate) {
+		t.Error("Expected principal not to have pvz:create scope")
+	}
+	if key.LastUsedAt == nil {
+		t.Error("Expected last used time to be recorded")
+	}
+
+	// неизвестный ключ
+	_, err = service.Authenticate(ctx, "pvz_unknown")
+	if !errors.Is(err, apikey.ErrInvalidAPIKey) {
+		t.Errorf("Expected ErrInvalidAPIKey for unknown key, got: %v", err)
+	}
+}
+
+func TestAPIKeyService_Revoke(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := tests.NewMockAPIKeyRepository()
+	service := services.NewAPIKeyService(mockRepo, tests.NewMockAuditRepository(), tests.NewMockTransactor(), tests.NewTestPolicy(), tests.NewTestLogger())
+
+	rawKey, key, _ := service.CreateKey(ctx, "reports", []auth.Permission{auth.PermissionReportsRead}, nil)
+
+	// Act
+	revoked, err := service.RevokeKey(ctx, key.ID)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if revoked.RevokedAt == nil {
+		t.Error("Expected revoked time to be set")
+	}
+
+	_, err = service.Authenticate(ctx, rawKey)
+	if !errors.Is(err, apikey.ErrInvalidAPIKey) {
+		t.Errorf("Expected ErrInvalidAPIKey for revoked key, got: %v", err)
+	}
+
+	// повторный отзыв
+	_, err = service.RevokeKey(ctx, key.ID)
+	if err == nil {
+		t.Error("Expected error when revoking twice, got nil")
+	}
+}
+
+func TestAPIKeyService_Expired(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := tests.NewMockAPIKeyRepository()
+	service := services.NewAPIKeyService(mockRepo, tests.NewMockAuditRepository(), tests.NewMockTransactor(), tests.NewTestPolicy(), tests.NewTestLogger())
+
+	expiresAt := time.Now().Add(time.Hour)
+	rawKey, key, err := service.CreateKey(ctx, "sync", []auth.Permission{auth.PermissionPVZRead}, &expiresAt)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// срок действия истек
+	expired := time.Now().Add(-time.Minute)
+	key.ExpiresAt = &expired
+
+	_, err = service.Authenticate(ctx, rawKey)
+	if !errors.Is(err, apikey.ErrInvalidAPIKey) {
+		t.Errorf("Expected ErrInvalidAPIKey for expired key, got: %v", err)
+	}
+
+	// срок действия в прошлом при создании
+	_, _, err = service.CreateKey(ctx, "sync", []auth.Permission{auth.PermissionPVZRead}, &expired)
+	if err == nil {
+		t.Error("Expected error for expiry in the past, got nil")
+	}
+}
+
+func TestAPIKeyService_InvalidScope(t *testing.T) {
+	ctx := context.Background()
+	service := services.NewAPIKeyService(tests.NewMockAPIKeyRepository(), tests.NewMockAuditRepository(), tests.NewMockTransactor(), tests.NewTestPolicy(), tests.NewTestLogger())
+
+	_, _, err := service.CreateKey(ctx, "bad", []auth.Permission{"pvz:destroy"}, nil)
+	if !errors.Is(err, apikey.ErrInvalidScope) {
+		t.Errorf("Expected ErrInvalidScope, got: %v", err)
+	}
+
+	_, _, err = service.CreateKey(ctx, "empty", nil, nil)
+	if err == nil {
+		t.Error("Expected error for key without scopes, got nil")
+	}
+}
+
+func TestAPIKeyService_ScopesLimitedByCreator(t *testing.T) {
+	policy, err := auth.NewPolicyFromConfig("integrator=users:manage,pvz:read")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	service := services.NewAPIKeyService(tests.NewMockAPIKeyRepository(), tests.NewMockAuditRepository(), tests.NewMockTransactor(), policy, tests.NewTestLogger())
+
+	integrator := auth.WithUser(context.Background(), &domain.User{ID: "integrator-1", Role: "integrator"})
+	keyCtx := auth.WithServicePrincipal(context.Background(), &auth.ServicePrincipal{
+		KeyID:  "key-1",
+		Scopes: []auth.Permission{auth.PermissionUsersManage, auth.PermissionPVZRead},
+	})
+
+	testCases := []struct {
+		name    string
+		ctx     context.Context
+		scopes  []auth.Permission
+		wantErr error
+	}{
+		{
+			name:   "право есть у роли создателя",
+			ctx:    integrator,
+			scopes: []auth.Permission{auth.PermissionPVZRead},
+		},
+		{
+			name:    "права нет у роли создателя",
+			ctx:     integrator,
+			scopes:  []auth.Permission{auth.PermissionPVZRead, auth.PermissionAuditRead},
+			wantErr: apikey.ErrScopeNotAllowed,
+		},
+		{
+			name:    "ключ не расширяет права ключа-создателя",
+			ctx:     keyCtx,
+			scopes:  []auth.Permission{auth.PermissionReportsRead},
+			wantErr: apikey.ErrScopeNotAllowed,
+		},
+		{
+			name:   "ключ выдает свои права",
+			ctx:    keyCtx,
+			scopes: []auth.Permission{auth.PermissionPVZRead},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := service.CreateKey(tc.ctx, "integration", tc.scopes, nil)
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("Expected error %v, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAPIKeyService_PVZScopesRejected(t *testing.T) {
+	service := services.NewAPIKeyService(tests.NewMockAPIKeyRepository(), tests.NewMockAuditRepository(), tests.NewMockTransactor(), tests.NewTestPolicy(), tests.NewTestLogger())
+
+	// операции в ПВЗ требуют закрепления, поэтому ключу их не выдать даже без ограничений создателя
+	for _, scope := range append(auth.PVZScopedPermissions, auth.PermissionPVZAny) {
+		_, _, err := service.CreateKey(context.Background(), "pvz-writer", []auth.Permission{scope}, nil)
+		if !errors.Is(err, apikey.ErrScopeNotAllowed) {
+			t.Errorf("Expected ErrScopeNotAllowed for %s, got: %v", scope, err)
+		}
+	}
+}
+
+// failingAPIKeyRepository имитирует сбой БД при чтении или записи ключа
+type failingAPIKeyRepository struct {
+	*tests.MockAPIKeyRepository
+	getErr     error
+	updateErr  error
+	lastUpdate int
+}
+
+func (r *failingAPIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	if r.getErr != nil {
+		return nil, r.getErr
+	}
+	return r.MockAPIKeyRepository.GetByHash(ctx, keyHash)
+}
+
+func (r *failingAPIKeyRepository) UpdateLastUsed(ctx context.Context, id string, usedAt time.Time) error {
+	r.lastUpdate++
+	if r.updateErr != nil {
+		return r.updateErr
+	}
+	return r.MockAPIKeyRepository.UpdateLastUsed(ctx, id, usedAt)
+}
+
+func TestAPIKeyService_AuthenticateStorageErrors(t *testing.T) {
+	ctx := context.Background()
+	repo := &failingAPIKeyRepository{MockAPIKeyRepository: tests.NewMockAPIKeyRepository()}
+	service := services.NewAPIKeyService(repo, tests.NewMockAuditRepository(), tests.NewMockTransactor(), tests.NewTestPolicy(), tests.NewTestLogger())
+
+	rawKey, _, err := service.CreateKey(ctx, "warehouse", []auth.Permission{auth.PermissionPVZRead}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// сбой записи времени использования не отклоняет запрос
+	repo.updateErr = errors.New("connection reset")
+	if _, err = service.Authenticate(ctx, rawKey); err != nil {
+		t.Errorf("Expected last used update failure to be ignored, got: %v", err)
+	}
+
+	// сбой чтения - внутренняя ошибка, а не недействительный ключ
+	repo.getErr = errors.New("connection reset")
+	_, err = service.Authenticate(ctx, rawKey)
+	if err == nil || errors.Is(err, apikey.ErrInvalidAPIKey) {
+		t.Errorf("Expected storage error not to be ErrInvalidAPIKey, got: %v", err)
+	}
+}
+
+func TestAPIKeyService_LastUsedThrottled(t *testing.T) {
+	ctx := context.Background()
+	repo := &failingAPIKeyRepository{MockAPIKeyRepository: tests.NewMockAPIKeyRepository()}
+	service := services.NewAPIKeyService(repo, tests.NewMockAuditRepository(), tests.NewMockTransactor(), tests.NewTestPolicy(), tests.NewTestLogger())
+
+	rawKey, key, err := service.CreateKey(ctx, "warehouse", []auth.Permission{auth.PermissionPVZRead}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err = service.Authenticate(ctx, rawKey); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+	if repo.lastUpdate != 1 {
+		t.Errorf("Expected one last used update within a minute, got %d", repo.lastUpdate)
+	}
+
+	stale := time.Now().Add(-2 * time.Minute)
+	key.LastUsedAt = &stale
+	if _, err = service.Authenticate(ctx, rawKey); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if repo.lastUpdate != 2 {
+		t.Errorf("Expected stale last used time to be updated, got %d updates", repo.lastUpdate)
+	}
+}
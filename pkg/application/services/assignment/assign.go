@@ -0,0 +1,59 @@
+package assignment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/audit"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+func (s *service) AssignEmployee(ctx context.Context, pvzID, userID string) (*domain.Assignment, error) {
+	_, err := s.pvzRepo.GetByID(ctx, pvzID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения ПВЗ: %w", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения пользователя: %w", err)
+	}
+
+	if !s.policy.Assignable(user.Role) {
+		return nil, fmt.Errorf("%w: роль %s", ErrNotAssignable, user.Role)
+	}
+
+	assignment, err := domain.NewAssignment(user, pvzID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка закрепления сотрудника: %w", err)
+	}
+
+	var savedAssignment *domain.Assignment
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		savedAssignment, err = s.assignmentRepo.Create(ctx, assignment)
+		if err != nil {
+			return fmt.Errorf("ошибка сохранения закрепления: %w", err)
+		}
+
+		return audit.Record(ctx, s.auditRepo, domain.AuditActionAssignmentCreate, domain.AuditEntityAssignment,
+			auditEntityID(pvzID, userID), nil, savedAssignment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return savedAssignment, nil
+}
+
+func (s *service) UnassignEmployee(ctx context.Context, pvzID, userID string) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.assignmentRepo.Delete(ctx, userID, pvzID)
+		if err != nil {
+			return fmt.Errorf("ошибка открепления сотрудника: %w", err)
+		}
+
+		before := &domain.Assignment{UserID: userID, PVZID: pvzID}
+		return audit.Record(ctx, s.auditRepo, domain.AuditActionAssignmentDelete, domain.AuditEntityAssignment,
+			auditEntityID(pvzID, userID), before, nil)
+	})
+}
@@ -0,0 +1,22 @@
+package assignment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+func (s *service) GetAssignmentsByPVZID(ctx context.Context, pvzID string) ([]*domain.Assignment, error) {
+	_, err := s.pvzRepo.GetByID(ctx, pvzID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения ПВЗ: %w", err)
+	}
+
+	assignments, err := s.assignmentRepo.GetByPVZID(ctx, pvzID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения сотрудников ПВЗ: %w", err)
+	}
+
+	return assignments, nil
+}
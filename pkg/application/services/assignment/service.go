@@ -0,0 +1,56 @@
+package assignment
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/auth"
+	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+// ErrNotAssignable - роль пользователя не работает с закрепленными ПВЗ
+var ErrNotAssignable = errors.New("за ПВЗ можно закрепить только пользователя, работающего с приемками в закрепленных ПВЗ")
+
+type Service interface {
+	// Закрепление сотрудника за ПВЗ
+	AssignEmployee(ctx context.Context, pvzID, userID string) (*domain.Assignment, error)
+
+	// Открепление сотрудника от ПВЗ
+	UnassignEmployee(ctx context.Context, pvzID, userID string) error
+
+	// Получение списка сотрудников, закрепленных за ПВЗ
+	GetAssignmentsByPVZID(ctx context.Context, pvzID string) ([]*domain.Assignment, error)
+}
+
+type service struct {
+	pvzRepo        repositories.PVZRepository
+	userRepo       repositories.UserRepository
+	assignmentRepo repositories.AssignmentRepository
+	auditRepo      repositories.AuditRepository
+	transactor     repositories.Transactor
+	policy         *auth.Policy
+}
+
+func New(
+	pvzRepo repositories.PVZRepository,
+	userRepo repositories.UserRepository,
+	assignmentRepo repositories.AssignmentRepository,
+	auditRepo repositories.AuditRepository,
+	transactor repositories.Transactor,
+	policy *auth.Policy,
+) Service {
+	return &service{
+		pvzRepo:        pvzRepo,
+		userRepo:       userRepo,
+		assignmentRepo: assignmentRepo,
+		auditRepo:      auditRepo,
+		transactor:     transactor,
+		policy:         policy,
+	}
+}
+
+// auditEntityID - идентификатор закрепления в журнале аудита
+func auditEntityID(pvzID, userID string) string {
+	return pvzID + ":" + userID
+}
@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/auth"
+	"github.com/dkumancev/avito-pvz/pkg/application/services"
+	"github.com/dkumancev/avito-pvz/pkg/application/services/assignment"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/tests"
+)
+
+func TestAssignmentService_AssignAndUnassign(t *testing.T) {
+	ctx := context.Background()
+	mockPVZRepo := tests.NewMockPVZRepository()
+	mockUserRepo := tests.NewMockUserRepository()
+	mockAssignmentRepo := tests.NewMockAssignmentRepository()
+
+	service := services.NewAssignmentService(mockPVZRepo, mockUserRepo, mockAssignmentRepo, tests.NewMockAuditRepository(), tests.NewMockTransactor(), tests.NewTestPolicy())
+
+	pvz, _ := domain.NewPVZ("Казань")
+	pvz.ID = "pvz-123"
+	mockPVZRepo.Create(ctx, pvz)
+
+	employee, _ := domain.NewUser("employee@example.com", "hash", domain.EmployeeRole)
+	employee, _ = mockUserRepo.Create(ctx, employee)
+
+	// Act
+	assignment, err := service.AssignEmployee(ctx, pvz.ID, employee.ID)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if assignment.UserID != employee.ID || assignment.PVZID != pvz.ID {
+		t.Errorf("Expected assignment %s/%s, got %s/%s", employee.ID, pvz.ID, assignment.UserID, assignment.PVZID)
+	}
+
+	assignments, err := service.GetAssignmentsByPVZID(ctx, pvz.ID)
+	if err != nil {
+		t.Errorf("Expected no error when listing assignments, got: %v", err)
+	}
+	if len(assignments) != 1 {
+		t.Errorf("Expected 1 assignment, got %d", len(assignments))
+	}
+
+	err = service.UnassignEmployee(ctx, pvz.ID, employee.ID)
+	if err != nil {
+		t.Errorf("Expected no error when unassigning, got: %v", err)
+	}
+
+	// повторное открепление должно вернуть ошибку
+	err = service.UnassignEmployee(ctx, pvz.ID, employee.ID)
+	if err == nil {
+		t.Error("Expected error when unassigning twice, got nil")
+	}
+
+	// закрепление за несуществующим ПВЗ
+	_, err = service.AssignEmployee(ctx, "non-existent-pvz", employee.ID)
+	if err == nil {
+		t.Error("Expected error for non-existent PVZ, got nil")
+	}
+}
+
+func TestAssignmentService_AssignModerator(t *testing.T) {
+	ctx := context.Background()
+	mockPVZRepo := tests.NewMockPVZRepository()
+	mockUserRepo := tests.NewMockUserRepository()
+	mockAssignmentRepo := tests.NewMockAssignmentRepository()
+
+	service := services.NewAssignmentService(mockPVZRepo, mockUserRepo, mockAssignmentRepo, tests.NewMockAuditRepository(), tests.NewMockTransactor(), tests.NewTestPolicy())
+
+	pvz, _ := domain.NewPVZ("Москва")
+	pvz.ID = "pvz-123"
+	mockPVZRepo.Create(ctx, pvz)
+
+	moderator, _ := domain.NewUser("moderator@example.com", "hash", domain.ModeratorRole)
+	moderator, _ = mockUserRepo.Create(ctx, moderator)
+
+	// модератор работает с любым ПВЗ, закреплять его не нужно
+	_, err := service.AssignEmployee(ctx, pvz.ID, moderator.ID)
+	if !errors.Is(err, assignment.ErrNotAssignable) {
+		t.Errorf("Expected ErrNotAssignable when assigning moderator, got: %v", err)
+	}
+}
+
+func TestAssignmentService_AssignCustomRole(t *testing.T) {
+	policy, err := auth.NewPolicyFromConfig("supervisor=pvz:read,reception:create,reception:close;auditor=reports:read")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	testCases := []struct {
+		name    string
+		role    domain.UserRole
+		wantErr error
+	}{
+		// своя роль с правами на приемки закрепляется так же, как сотрудник
+		{name: "роль с операциями в ПВЗ", role: "supervisor"},
+		// роль без операций в ПВЗ закреплять бессмысленно
+		{name: "роль без операций в ПВЗ", role: "auditor", wantErr: assignment.ErrNotAssignable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockPVZRepo := tests.NewMockPVZRepository()
+			mockUserRepo := tests.NewMockUserRepository()
+
+			service := services.NewAssignmentService(mockPVZRepo, mockUserRepo, tests.NewMockAssignmentRepository(), tests.NewMockAuditRepository(), tests.NewMockTransactor(), policy)
+
+			pvz, _ := domain.NewPVZ("Москва")
+			pvz.ID = "pvz-123"
+			mockPVZRepo.Create(ctx, pvz)
+
+			user, _ := mockUserRepo.Create(ctx, domain.User{Email: "user@example.com", Role: tc.role})
+
+			_, err := service.AssignEmployee(ctx, pvz.ID, user.ID)
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("Expected error %v, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
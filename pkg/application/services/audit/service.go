@@ -0,0 +1,52 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 100
+)
+
+var ErrInvalidPeriod = errors.New("начало периода должно быть раньше конца")
+
+type Service interface {
+	// Просмотр журнала аудита с фильтрацией по инициатору, сущности и периоду
+	ListEntries(ctx context.Context, filter repositories.AuditFilter) ([]*domain.AuditEntry, error)
+}
+
+type service struct {
+	auditRepo repositories.AuditRepository
+}
+
+func New(auditRepo repositories.AuditRepository) Service {
+	return &service{
+		auditRepo: auditRepo,
+	}
+}
+
+func (s *service) ListEntries(ctx context.Context, filter repositories.AuditFilter) ([]*domain.AuditEntry, error) {
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return nil, ErrInvalidPeriod
+	}
+
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 || filter.Limit > maxLimit {
+		filter.Limit = defaultLimit
+	}
+
+	entries, err := s.auditRepo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения журнала аудита: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,56 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
+	"github.com/dkumancev/avito-pvz/pkg/application/services"
+	"github.com/dkumancev/avito-pvz/pkg/application/services/audit"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/tests"
+)
+
+func TestAuditService_ListEntries(t *testing.T) {
+	ctx := context.Background()
+	mockAuditRepo := tests.NewMockAuditRepository()
+	service := services.NewAuditService(mockAuditRepo)
+
+	entries := []*domain.AuditEntry{
+		{ActorID: "moderator-1", ActorRole: string(domain.ModeratorRole), Action: domain.AuditActionPVZCreate, EntityType: domain.AuditEntityPVZ, EntityID: "pvz-1"},
+		{ActorID: "employee-1", ActorRole: string(domain.EmployeeRole), Action: domain.AuditActionReceptionCreate, EntityType: domain.AuditEntityReception, EntityID: "reception-1"},
+		{ActorID: "employee-1", ActorRole: string(domain.EmployeeRole), Action: domain.AuditActionReceptionClose, EntityType: domain.AuditEntityReception, EntityID: "reception-1"},
+	}
+	for _, entry := range entries {
+		entry.CreatedAt = time.Now()
+		mockAuditRepo.Create(ctx, entry)
+	}
+
+	// Act
+	result, err := service.ListEntries(ctx, repositories.AuditFilter{ActorID: "employee-1", EntityID: "reception-1"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(result))
+	}
+	if result[0].Action != domain.AuditActionReceptionClose {
+		t.Errorf("Expected newest entry first, got %s", result[0].Action)
+	}
+}
+
+func TestAuditService_InvalidPeriod(t *testing.T) {
+	service := services.NewAuditService(tests.NewMockAuditRepository())
+
+	from := time.Now()
+	to := from.Add(-time.Hour)
+
+	_, err := service.ListEntries(context.Background(), repositories.AuditFilter{From: &from, To: &to})
+	if !errors.Is(err, audit.ErrInvalidPeriod) {
+		t.Errorf("Expected ErrInvalidPeriod, got: %v", err)
+	}
+}
@@ -2,12 +2,19 @@
 package services
 
 import (
+	"log/slog"
 	"time"
 
+	"github.com/dkumancev/avito-pvz/pkg/application/auth"
+	"github.com/dkumancev/avito-pvz/pkg/application/metrics"
 	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
+	"github.com/dkumancev/avito-pvz/pkg/application/services/apikey"
+	"github.com/dkumancev/avito-pvz/pkg/application/services/assignment"
+	"github.com/dkumancev/avito-pvz/pkg/application/services/audit"
 	"github.com/dkumancev/avito-pvz/pkg/application/services/pvz"
 	"github.com/dkumancev/avito-pvz/pkg/application/services/reception"
 	"github.com/dkumancev/avito-pvz/pkg/application/services/user"
+	"github.com/dkumancev/avito-pvz/pkg/application/services/webhook"
 )
 
 
@@ -20,26 +27,89 @@ type (
 
 	// UserService интерфейс сервиса пользователей
 	UserService = user.Service
+
+	// AssignmentService интерфейс сервиса закрепления сотрудников за ПВЗ
+	AssignmentService = assignment.Service
+
+	// APIKeyService интерфейс сервиса API-ключей
+	APIKeyService = apikey.Service
+
+	// AuditService интерфейс сервиса журнала аудита
+	AuditService = audit.Service
+
+	// WebhookService интерфейс сервиса вебхуков
+	WebhookService = webhook.Service
 )
 
 // Функции-конструкторы для совместимости
 
-func NewPVZService(pvzRepo repositories.PVZRepository) PVZService {
-	return pvz.New(pvzRepo)
+func NewPVZService(
+	pvzRepo repositories.PVZRepository,
+	auditRepo repositories.AuditRepository,
+	outboxRepo repositories.OutboxRepository,
+	transactor repositories.Transactor,
+	businessMetrics metrics.BusinessMetrics,
+	logger *slog.Logger,
+) PVZService {
+	return pvz.New(pvzRepo, auditRepo, outboxRepo, transactor, businessMetrics, logger)
 }
 
 func NewReceptionService(
 	pvzRepo repositories.PVZRepository,
 	receptionRepo repositories.ReceptionRepository,
 	productRepo repositories.ProductRepository,
+	assignmentRepo repositories.AssignmentRepository,
+	auditRepo repositories.AuditRepository,
+	outboxRepo repositories.OutboxRepository,
+	transactor repositories.Transactor,
+	policy *auth.Policy,
+	businessMetrics metrics.BusinessMetrics,
+	logger *slog.Logger,
 ) ReceptionService {
-	return reception.New(pvzRepo, receptionRepo, productRepo)
+	return reception.New(pvzRepo, receptionRepo, productRepo, assignmentRepo, auditRepo, outboxRepo, transactor, policy, businessMetrics, logger)
 }
 
 func NewUserService(
 	userRepo repositories.UserRepository,
+	twoFactorRepo repositories.TwoFactorRepository,
+	auditRepo repositories.AuditRepository,
+	transactor repositories.Transactor,
 	jwtSecret []byte,
 	tokenExpiry time.Duration,
 ) UserService {
-	return user.New(userRepo, jwtSecret, tokenExpiry)
+	return user.New(userRepo, twoFactorRepo, auditRepo, transactor, jwtSecret, tokenExpiry)
+}
+
+func NewAssignmentService(
+	pvzRepo repositories.PVZRepository,
+	userRepo repositories.UserRepository,
+	assignmentRepo repositories.AssignmentRepository,
+	auditRepo repositories.AuditRepository,
+	transactor repositories.Transactor,
+	policy *auth.Policy,
+) AssignmentService {
+	return assignment.New(pvzRepo, userRepo, assignmentRepo, auditRepo, transactor, policy)
+}
+
+func NewAPIKeyService(
+	apiKeyRepo repositories.APIKeyRepository,
+	auditRepo repositories.AuditRepository,
+	transactor repositories.Transactor,
+	policy *auth.Policy,
+	logger *slog.Logger,
+) APIKeyService {
+	return apikey.New(apiKeyRepo, auditRepo, transactor, policy, logger)
+}
+
+func NewAuditService(auditRepo repositories.AuditRepository) AuditService {
+	return audit.New(auditRepo)
+}
+
+func NewWebhookService(
+	webhookRepo repositories.WebhookRepository,
+	deliveryRepo repositories.WebhookDeliveryRepository,
+	auditRepo repositories.AuditRepository,
+	transactor repositories.Transactor,
+) WebhookService {
+	return webhook.New(webhookRepo, deliveryRepo, auditRepo, transactor)
 }
@@ -4,19 +4,42 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/dkumancev/avito-pvz/pkg/application/audit"
+	"github.com/dkumancev/avito-pvz/pkg/application/events"
+	"github.com/dkumancev/avito-pvz/pkg/application/requestctx"
 	"github.com/dkumancev/avito-pvz/pkg/domain"
 )
 
 func (s *service) CreatePVZ(ctx context.Context, city string) (*domain.PVZ, error) {
+	ctx, span := tracer.Start(ctx, "pvz.CreatePVZ")
+	defer span.End()
+
 	pvz, err := domain.NewPVZ(city)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка создания ПВЗ: %w", err)
 	}
 
-	savedPVZ, err := s.pvzRepo.Create(ctx, pvz)
+	var savedPVZ *domain.PVZ
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		savedPVZ, err = s.pvzRepo.Create(ctx, pvz)
+		if err != nil {
+			return fmt.Errorf("ошибка сохранения ПВЗ: %w", err)
+		}
+
+		err = audit.Record(ctx, s.auditRepo, domain.AuditActionPVZCreate, domain.AuditEntityPVZ, savedPVZ.ID, nil, savedPVZ)
+		if err != nil {
+			return err
+		}
+
+		return events.Record(ctx, s.outboxRepo, domain.EventPVZCreated, domain.AuditEntityPVZ, savedPVZ.ID, savedPVZ)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("ошибка сохранения ПВЗ: %w", err)
+		return nil, err
 	}
 
+	requestctx.SetPVZID(ctx, savedPVZ.ID)
+	s.metrics.PVZCreated(savedPVZ.City)
+	s.logger.InfoContext(ctx, "Создан ПВЗ", "city", savedPVZ.City)
+
 	return savedPVZ, nil
 }
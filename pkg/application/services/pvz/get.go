@@ -9,6 +9,9 @@ import (
 )
 
 func (s *service) GetPVZByID(ctx context.Context, id string) (*domain.PVZ, error) {
+	ctx, span := tracer.Start(ctx, "pvz.GetPVZByID")
+	defer span.End()
+
 	pvz, err := s.pvzRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка получения ПВЗ: %w", err)
@@ -17,6 +20,9 @@ func (s *service) GetPVZByID(ctx context.Context, id string) (*domain.PVZ, error
 }
 
 func (s *service) ListPVZs(ctx context.Context, filter repositories.PVZFilter) ([]*domain.PVZ, error) {
+	ctx, span := tracer.Start(ctx, "pvz.ListPVZs")
+	defer span.End()
+
 	// дефолтные значения для пагинации, если не указаны
 	if filter.Page < 1 {
 		filter.Page = 1
@@ -2,11 +2,16 @@ package pvz
 
 import (
 	"context"
+	"log/slog"
 
+	"github.com/dkumancev/avito-pvz/pkg/application/metrics"
 	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
 	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"go.opentelemetry.io/otel"
 )
 
+var tracer = otel.Tracer("github.com/dkumancev/avito-pvz/pkg/application/services/pvz")
+
 type Service interface {
 	// Создание нового ПВЗ
 	CreatePVZ(ctx context.Context, city string) (*domain.PVZ, error)
@@ -22,11 +27,28 @@ type Service interface {
 }
 
 type service struct {
-	pvzRepo repositories.PVZRepository
+	pvzRepo    repositories.PVZRepository
+	auditRepo  repositories.AuditRepository
+	outboxRepo repositories.OutboxRepository
+	transactor repositories.Transactor
+	metrics    metrics.BusinessMetrics
+	logger     *slog.Logger
 }
 
-func New(pvzRepo repositories.PVZRepository) Service {
+func New(
+	pvzRepo repositories.PVZRepository,
+	auditRepo repositories.AuditRepository,
+	outboxRepo repositories.OutboxRepository,
+	transactor repositories.Transactor,
+	businessMetrics metrics.BusinessMetrics,
+	logger *slog.Logger,
+) Service {
 	return &service{
-		pvzRepo: pvzRepo,
+		pvzRepo:    pvzRepo,
+		auditRepo:  auditRepo,
+		outboxRepo: outboxRepo,
+		transactor: transactor,
+		metrics:    businessMetrics,
+		logger:     logger,
 	}
 }
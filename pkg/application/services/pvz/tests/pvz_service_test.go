@@ -12,7 +12,7 @@ import (
 func TestPVZService_CreatePVZ(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := tests.NewMockPVZRepository()
-	service := services.NewPVZService(mockRepo)
+	service := services.NewPVZService(mockRepo, tests.NewMockAuditRepository(), tests.NewMockOutboxRepository(), tests.NewMockTransactor(), tests.NewMockBusinessMetrics(), tests.NewTestLogger())
 
 	// Valid city
 	pvz, err := service.CreatePVZ(ctx, "Москва")
@@ -36,7 +36,7 @@ func TestPVZService_CreatePVZ(t *testing.T) {
 func TestPVZService_GetPVZ(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := tests.NewMockPVZRepository()
-	service := services.NewPVZService(mockRepo)
+	service := services.NewPVZService(mockRepo, tests.NewMockAuditRepository(), tests.NewMockOutboxRepository(), tests.NewMockTransactor(), tests.NewMockBusinessMetrics(), tests.NewTestLogger())
 
 	// Create a PVZ first
 	createdPVZ, _ := service.CreatePVZ(ctx, "Москва")
@@ -63,7 +63,7 @@ func TestPVZService_GetPVZ(t *testing.T) {
 func TestPVZService_ListPVZs(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := tests.NewMockPVZRepository()
-	service := services.NewPVZService(mockRepo)
+	service := services.NewPVZService(mockRepo, tests.NewMockAuditRepository(), tests.NewMockOutboxRepository(), tests.NewMockTransactor(), tests.NewMockBusinessMetrics(), tests.NewTestLogger())
 
 	// Create a PVZ - в текущей реализации мока, Create всегда использует
 	// фиксированный ID "mock-pvz-id", так что второй вызов перезапишет первый
@@ -84,7 +84,7 @@ func TestPVZService_ListPVZs(t *testing.T) {
 func TestPVZService_ListPVZ(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := tests.NewMockPVZRepository()
-	service := services.NewPVZService(mockRepo)
+	service := services.NewPVZService(mockRepo, tests.NewMockAuditRepository(), tests.NewMockOutboxRepository(), tests.NewMockTransactor(), tests.NewMockBusinessMetrics(), tests.NewTestLogger())
 
 	// Create a PVZ - в текущей реализации мока, Create всегда использует
 	// фиксированный ID "mock-pvz-id", так что второй вызов перезапишет первый
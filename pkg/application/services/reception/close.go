@@ -3,30 +3,65 @@ package reception
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/dkumancev/avito-pvz/pkg/application/audit"
+	"github.com/dkumancev/avito-pvz/pkg/application/events"
+	"github.com/dkumancev/avito-pvz/pkg/application/requestctx"
 	"github.com/dkumancev/avito-pvz/pkg/domain"
 )
 
 func (s *service) CloseReception(ctx context.Context, pvzID string) (*domain.Reception, error) {
-	_, err := s.pvzRepo.GetByID(ctx, pvzID)
-	if err != nil {
-		return nil, fmt.Errorf("ошибка получения ПВЗ: %w", err)
-	}
+	ctx, span := tracer.Start(ctx, "reception.CloseReception")
+	defer span.End()
 
-	reception, err := s.getActiveReception(ctx, pvzID)
+	requestctx.SetPVZID(ctx, pvzID)
+
+	err := s.checkPVZAccess(ctx, pvzID)
 	if err != nil {
-		return nil, fmt.Errorf("не удалось получить активную приемку: %w", err)
+		return nil, err
 	}
 
-	err = reception.Close()
+	pvz, err := s.pvzRepo.GetByID(ctx, pvzID)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка закрытия приемки: %w", err)
+		return nil, fmt.Errorf("ошибка получения ПВЗ: %w", err)
 	}
 
-	err = s.receptionRepo.Update(ctx, reception)
+	var reception *domain.Reception
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		reception, err = s.getActiveReception(ctx, pvzID)
+		if err != nil {
+			return fmt.Errorf("не удалось получить активную приемку: %w", err)
+		}
+		before := *reception
+
+		err = reception.Close()
+		if err != nil {
+			return fmt.Errorf("ошибка закрытия приемки: %w", err)
+		}
+
+		err = s.receptionRepo.Update(ctx, reception)
+		if err != nil {
+			return fmt.Errorf("ошибка обновления приемки: %w", err)
+		}
+
+		err = audit.Record(ctx, s.auditRepo, domain.AuditActionReceptionClose, domain.AuditEntityReception, reception.ID, before, reception)
+		if err != nil {
+			return err
+		}
+
+		return events.Record(ctx, s.outboxRepo, domain.EventReceptionClosed, domain.AuditEntityReception, reception.ID, reception)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("ошибка обновления приемки: %w", err)
+		return nil, err
 	}
 
+	duration := time.Since(reception.DateTime)
+	s.metrics.ReceptionClosed(pvz.City, duration)
+	s.logger.InfoContext(ctx, "Закрыта приемка",
+		"reception_id", reception.ID,
+		"products", len(reception.Products),
+		"duration", duration.String())
+
 	return reception, nil
 }
@@ -5,27 +5,56 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/dkumancev/avito-pvz/pkg/application/audit"
+	"github.com/dkumancev/avito-pvz/pkg/application/events"
+	"github.com/dkumancev/avito-pvz/pkg/application/requestctx"
 	"github.com/dkumancev/avito-pvz/pkg/domain"
 )
 
 func (s *service) CreateReception(ctx context.Context, pvzID string) (*domain.Reception, error) {
+	ctx, span := tracer.Start(ctx, "reception.CreateReception")
+	defer span.End()
+
+	requestctx.SetPVZID(ctx, pvzID)
+
+	err := s.checkPVZAccess(ctx, pvzID)
+	if err != nil {
+		return nil, err
+	}
+
 	pvz, err := s.pvzRepo.GetByID(ctx, pvzID)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка получения ПВЗ: %w", err)
 	}
 
-	// Проверяем, что нет активной приемки
-	existingReception, err := s.receptionRepo.GetLastActiveByPVZID(ctx, pvzID)
-	if err == nil && existingReception != nil {
-		return nil, errors.New("для данного ПВЗ уже существует активная приемка")
-	}
+	var savedReception *domain.Reception
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Проверяем, что нет активной приемки
+		existingReception, err := s.receptionRepo.GetLastActiveByPVZID(ctx, pvzID)
+		if err == nil && existingReception != nil {
+			return errors.New("для данного ПВЗ уже существует активная приемка")
+		}
+
+		reception := domain.NewReception(pvz.ID)
 
-	reception := domain.NewReception(pvz.ID)
+		savedReception, err = s.receptionRepo.Create(ctx, reception)
+		if err != nil {
+			return fmt.Errorf("ошибка создания приемки: %w", err)
+		}
 
-	savedReception, err := s.receptionRepo.Create(ctx, reception)
+		err = audit.Record(ctx, s.auditRepo, domain.AuditActionReceptionCreate, domain.AuditEntityReception, savedReception.ID, nil, savedReception)
+		if err != nil {
+			return err
+		}
+
+		return events.Record(ctx, s.outboxRepo, domain.EventReceptionCreated, domain.AuditEntityReception, savedReception.ID, savedReception)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("ошибка создания приемки: %w", err)
+		return nil, err
 	}
 
+	s.metrics.ReceptionOpened(pvz.City)
+	s.logger.InfoContext(ctx, "Открыта приемка", "reception_id", savedReception.ID)
+
 	return savedReception, nil
 }
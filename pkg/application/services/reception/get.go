@@ -3,11 +3,15 @@ package reception
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/dkumancev/avito-pvz/pkg/domain"
 )
 
 func (s *service) GetReceptionsByPVZID(ctx context.Context, pvzID string) ([]*domain.Reception, error) {
+	ctx, span := tracer.Start(ctx, "reception.GetReceptionsByPVZID")
+	defer span.End()
+
 	_, err := s.pvzRepo.GetByID(ctx, pvzID)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка получения ПВЗ: %w", err)
@@ -22,6 +26,9 @@ func (s *service) GetReceptionsByPVZID(ctx context.Context, pvzID string) ([]*do
 }
 
 func (s *service) GetProductsByReceptionID(ctx context.Context, receptionID string) ([]*domain.Product, error) {
+	ctx, span := tracer.Start(ctx, "reception.GetProductsByReceptionID")
+	defer span.End()
+
 	_, err := s.receptionRepo.GetByID(ctx, receptionID)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка получения приемки: %w", err)
@@ -34,3 +41,15 @@ func (s *service) GetProductsByReceptionID(ctx context.Context, receptionID stri
 
 	return products, nil
 }
+
+func (s *service) ListStaleReceptions(ctx context.Context, before time.Time) ([]*domain.Reception, error) {
+	ctx, span := tracer.Start(ctx, "reception.ListStaleReceptions")
+	defer span.End()
+
+	receptions, err := s.receptionRepo.ListActiveBefore(ctx, before)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения незакрытых приемок: %w", err)
+	}
+
+	return receptions, nil
+}
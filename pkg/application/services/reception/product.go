@@ -4,11 +4,24 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/dkumancev/avito-pvz/pkg/application/audit"
+	"github.com/dkumancev/avito-pvz/pkg/application/events"
+	"github.com/dkumancev/avito-pvz/pkg/application/requestctx"
 	"github.com/dkumancev/avito-pvz/pkg/domain"
 )
 
 func (s *service) AddProduct(ctx context.Context, pvzID string, productType string) (*domain.Product, error) {
-	_, err := s.pvzRepo.GetByID(ctx, pvzID)
+	ctx, span := tracer.Start(ctx, "reception.AddProduct")
+	defer span.End()
+
+	requestctx.SetPVZID(ctx, pvzID)
+
+	err := s.checkPVZAccess(ctx, pvzID)
+	if err != nil {
+		return nil, err
+	}
+
+	pvz, err := s.pvzRepo.GetByID(ctx, pvzID)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка получения ПВЗ: %w", err)
 	}
@@ -28,16 +41,44 @@ func (s *service) AddProduct(ctx context.Context, pvzID string, productType stri
 		return nil, fmt.Errorf("не удалось добавить товар в приемку: %w", err)
 	}
 
-	savedProduct, err := s.productRepo.Create(ctx, product, reception.ID)
+	var savedProduct *domain.Product
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		savedProduct, err = s.productRepo.Create(ctx, product, reception.ID)
+		if err != nil {
+			return fmt.Errorf("ошибка сохранения товара: %w", err)
+		}
+
+		err = audit.Record(ctx, s.auditRepo, domain.AuditActionProductAdd, domain.AuditEntityProduct, savedProduct.ID, nil, savedProduct)
+		if err != nil {
+			return err
+		}
+
+		return events.Record(ctx, s.outboxRepo, domain.EventProductAdded, domain.AuditEntityProduct, savedProduct.ID, savedProduct)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("ошибка сохранения товара: %w", err)
+		return nil, err
 	}
+	// в приемке остается сохраненный товар с присвоенным ID
+	reception.Products[len(reception.Products)-1] = *savedProduct
+
+	s.metrics.ProductAdded(pvz.City, savedProduct.Type)
+	s.logger.InfoContext(ctx, "Добавлен товар", "reception_id", reception.ID, "product_id", savedProduct.ID, "type", savedProduct.Type)
 
 	return savedProduct, nil
 }
 
 func (s *service) RemoveLastProduct(ctx context.Context, pvzID string) error {
-	_, err := s.pvzRepo.GetByID(ctx, pvzID)
+	ctx, span := tracer.Start(ctx, "reception.RemoveLastProduct")
+	defer span.End()
+
+	requestctx.SetPVZID(ctx, pvzID)
+
+	err := s.checkPVZAccess(ctx, pvzID)
+	if err != nil {
+		return err
+	}
+
+	pvz, err := s.pvzRepo.GetByID(ctx, pvzID)
 	if err != nil {
 		return fmt.Errorf("ошибка получения ПВЗ: %w", err)
 	}
@@ -47,16 +88,38 @@ func (s *service) RemoveLastProduct(ctx context.Context, pvzID string) error {
 		return fmt.Errorf("не удалось получить активную приемку: %w", err)
 	}
 
+	// удаляемый товар нужен для метрик и журнала аудита
+	var removed domain.Product
+	if len(reception.Products) > 0 {
+		removed = reception.Products[len(reception.Products)-1]
+	}
+	productType := removed.Type
+
 	err = reception.RemoveLastProduct()
 	if err != nil {
 		return fmt.Errorf("ошибка удаления товара: %w", err)
 	}
 
-	err = s.productRepo.DeleteLastByReceptionID(ctx, reception.ID)
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.productRepo.DeleteLastByReceptionID(ctx, reception.ID)
+		if err != nil {
+			return fmt.Errorf("ошибка удаления товара из БД: %w", err)
+		}
+
+		err = audit.Record(ctx, s.auditRepo, domain.AuditActionProductRemove, domain.AuditEntityProduct, removed.ID, removed, nil)
+		if err != nil {
+			return err
+		}
+
+		return events.Record(ctx, s.outboxRepo, domain.EventProductRemoved, domain.AuditEntityProduct, removed.ID, removed)
+	})
 	if err != nil {
-		return fmt.Errorf("ошибка удаления товара из БД: %w", err)
+		return err
 	}
 
+	s.metrics.ProductRemoved(pvz.City, productType)
+	s.logger.InfoContext(ctx, "Удален последний товар", "reception_id", reception.ID, "type", productType)
+
 	return nil
 }
 
@@ -2,9 +2,25 @@ package reception
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
 
+	"github.com/dkumancev/avito-pvz/pkg/application/auth"
+	"github.com/dkumancev/avito-pvz/pkg/application/metrics"
 	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
 	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("github.com/dkumancev/avito-pvz/pkg/application/services/reception")
+
+var (
+	ErrPVZAccessDenied = errors.New("сотрудник не закреплен за данным ПВЗ")
+
+	// ErrReceptionNotActive - приемку уже закрыли, пока ее обрабатывала система
+	ErrReceptionNotActive = repositories.ErrReceptionNotActive
 )
 
 type Service interface {
@@ -28,23 +44,53 @@ type Service interface {
 
 	// Получение товаров по ID приемки
 	GetProductsByReceptionID(ctx context.Context, receptionID string) ([]*domain.Product, error)
+
+	// Получение незакрытых приемок, открытых раньше before
+	ListStaleReceptions(ctx context.Context, before time.Time) ([]*domain.Reception, error)
+
+	// Закрытие зависшей приемки системой с указанием причины
+	CloseStaleReception(ctx context.Context, receptionID, reason string) (*domain.Reception, error)
+
+	// Отметка зависшей приемки без закрытия. Возвращает false, если приемка уже отмечена
+	MarkReceptionStale(ctx context.Context, receptionID string) (bool, error)
 }
 
 type service struct {
-	pvzRepo       repositories.PVZRepository
-	receptionRepo repositories.ReceptionRepository
-	productRepo   repositories.ProductRepository
+	pvzRepo        repositories.PVZRepository
+	receptionRepo  repositories.ReceptionRepository
+	productRepo    repositories.ProductRepository
+	assignmentRepo repositories.AssignmentRepository
+	auditRepo      repositories.AuditRepository
+	outboxRepo     repositories.OutboxRepository
+	transactor     repositories.Transactor
+	policy         *auth.Policy
+	metrics        metrics.BusinessMetrics
+	logger         *slog.Logger
 }
 
 func New(
 	pvzRepo repositories.PVZRepository,
 	receptionRepo repositories.ReceptionRepository,
 	productRepo repositories.ProductRepository,
+	assignmentRepo repositories.AssignmentRepository,
+	auditRepo repositories.AuditRepository,
+	outboxRepo repositories.OutboxRepository,
+	transactor repositories.Transactor,
+	policy *auth.Policy,
+	businessMetrics metrics.BusinessMetrics,
+	logger *slog.Logger,
 ) Service {
 	return &service{
-		pvzRepo:       pvzRepo,
-		receptionRepo: receptionRepo,
-		productRepo:   productRepo,
+		pvzRepo:        pvzRepo,
+		receptionRepo:  receptionRepo,
+		productRepo:    productRepo,
+		assignmentRepo: assignmentRepo,
+		auditRepo:      auditRepo,
+		outboxRepo:     outboxRepo,
+		transactor:     transactor,
+		policy:         policy,
+		metrics:        businessMetrics,
+		logger:         logger,
 	}
 }
 
@@ -55,3 +101,30 @@ func (s *service) getActiveReception(ctx context.Context, pvzID string) (*domain
 	}
 	return reception, nil
 }
+
+// checkPVZAccess проверяет, что пользователь из контекста закреплен за ПВЗ.
+// Роли с правом pvz:any и внутренние вызовы без аутентификации не ограничиваются.
+// Внешнюю систему за ПВЗ не закрепить, поэтому API-ключам операции в ПВЗ недоступны.
+// Вызывается до чтения ПВЗ, чтобы по ответу нельзя было узнать, существует ли чужой ПВЗ
+func (s *service) checkPVZAccess(ctx context.Context, pvzID string) error {
+	user, ok := auth.UserFromContext(ctx)
+	if !ok {
+		if _, isService := auth.ServicePrincipalFromContext(ctx); isService {
+			return ErrPVZAccessDenied
+		}
+		return nil
+	}
+	if !s.policy.PVZScoped(user.Role) {
+		return nil
+	}
+
+	assigned, err := s.assignmentRepo.Exists(ctx, user.ID, pvzID)
+	if err != nil {
+		return fmt.Errorf("ошибка проверки закрепления за ПВЗ: %w", err)
+	}
+	if !assigned {
+		return ErrPVZAccessDenied
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package reception
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/audit"
+	"github.com/dkumancev/avito-pvz/pkg/application/events"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+// CloseStaleReception закрывает приемку от имени системы и сохраняет причину.
+// Проверка закрепления за ПВЗ не выполняется: вызов идет из фоновой задачи
+func (s *service) CloseStaleReception(ctx context.Context, receptionID, reason string) (*domain.Reception, error) {
+	ctx, span := tracer.Start(ctx, "reception.CloseStaleReception")
+	defer span.End()
+
+	var (
+		reception *domain.Reception
+		pvz       *domain.PVZ
+	)
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		reception, err = s.receptionRepo.GetByID(ctx, receptionID)
+		if err != nil {
+			return fmt.Errorf("ошибка получения приемки: %w", err)
+		}
+		if !reception.IsActive() {
+			return ErrReceptionNotActive
+		}
+
+		pvz, err = s.pvzRepo.GetByID(ctx, reception.PVZID)
+		if err != nil {
+			return fmt.Errorf("ошибка получения ПВЗ: %w", err)
+		}
+		before := *reception
+
+		err = reception.CloseBySystem(reason)
+		if err != nil {
+			return fmt.Errorf("ошибка закрытия приемки: %w", err)
+		}
+
+		err = s.receptionRepo.Update(ctx, reception)
+		if err != nil {
+			return fmt.Errorf("ошибка обновления приемки: %w", err)
+		}
+
+		err = audit.Record(ctx, s.auditRepo, domain.AuditActionReceptionClose, domain.AuditEntityReception, reception.ID, before, reception)
+		if err != nil {
+			return err
+		}
+
+		return events.Record(ctx, s.outboxRepo, domain.EventReceptionClosed, domain.AuditEntityReception, reception.ID, reception)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	duration := time.Since(reception.DateTime)
+	s.metrics.ReceptionClosed(pvz.City, duration)
+	s.metrics.ReceptionAutoClosed(pvz.City)
+	s.logger.InfoContext(ctx, "Зависшая приемка закрыта системой",
+		"reception_id", reception.ID,
+		"pvz_id", reception.PVZID,
+		"reason", reason,
+		"duration", duration.String())
+
+	return reception, nil
+}
+
+// MarkReceptionStale отмечает приемку зависшей, не закрывая ее
+func (s *service) MarkReceptionStale(ctx context.Context, receptionID string) (bool, error) {
+	ctx, span := tracer.Start(ctx, "reception.MarkReceptionStale")
+	defer span.End()
+
+	var (
+		reception *domain.Reception
+		pvz       *domain.PVZ
+		marked    bool
+	)
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		reception, err = s.receptionRepo.GetByID(ctx, receptionID)
+		if err != nil {
+			return fmt.Errorf("ошибка получения приемки: %w", err)
+		}
+
+		pvz, err = s.pvzRepo.GetByID(ctx, reception.PVZID)
+		if err != nil {
+			return fmt.Errorf("ошибка получения ПВЗ: %w", err)
+		}
+		before := *reception
+
+		marked = reception.MarkStale(time.Now())
+		if !marked {
+			return nil
+		}
+
+		err = s.receptionRepo.Update(ctx, reception)
+		if err != nil {
+			return fmt.Errorf("ошибка обновления приемки: %w", err)
+		}
+
+		return audit.Record(ctx, s.auditRepo, domain.AuditActionReceptionStale, domain.AuditEntityReception, reception.ID, before, reception)
+	})
+	if err != nil || !marked {
+		return false, err
+	}
+
+	s.metrics.ReceptionMarkedStale(pvz.City)
+	s.logger.InfoContext(ctx, "Приемка отмечена зависшей",
+		"reception_id", reception.ID,
+		"pvz_id", reception.PVZID,
+		"opened_at", reception.DateTime)
+
+	return true, nil
+}
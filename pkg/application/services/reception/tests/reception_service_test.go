@@ -2,9 +2,14 @@ package tests
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/dkumancev/avito-pvz/pkg/application/auth"
 	"github.com/dkumancev/avito-pvz/pkg/application/services"
+	"github.com/dkumancev/avito-pvz/pkg/application/services/reception"
 	"github.com/dkumancev/avito-pvz/pkg/domain"
 	"github.com/dkumancev/avito-pvz/pkg/tests"
 )
@@ -15,7 +20,7 @@ func TestReceptionService_CreateReception(t *testing.T) {
 	mockReceptionRepo := tests.NewMockReceptionRepository()
 	mockProductRepo := tests.NewMockProductRepository()
 
-	service := services.NewReceptionService(mockPVZRepo, mockReceptionRepo, mockProductRepo)
+	service := services.NewReceptionService(mockPVZRepo, mockReceptionRepo, mockProductRepo, tests.NewMockAssignmentRepository(), tests.NewMockAuditRepository(), tests.NewMockOutboxRepository(), tests.NewMockTransactor(), tests.NewTestPolicy(), tests.NewMockBusinessMetrics(), tests.NewTestLogger())
 
 	pvz, _ := domain.NewPVZ("Москва")
 	pvz.ID = "pvz-123"
@@ -51,7 +56,7 @@ func TestReceptionService_CloseReception(t *testing.T) {
 	mockReceptionRepo := tests.NewMockReceptionRepository()
 	mockProductRepo := tests.NewMockProductRepository()
 
-	service := services.NewReceptionService(mockPVZRepo, mockReceptionRepo, mockProductRepo)
+	service := services.NewReceptionService(mockPVZRepo, mockReceptionRepo, mockProductRepo, tests.NewMockAssignmentRepository(), tests.NewMockAuditRepository(), tests.NewMockOutboxRepository(), tests.NewMockTransactor(), tests.NewTestPolicy(), tests.NewMockBusinessMetrics(), tests.NewTestLogger())
 
 	pvz, _ := domain.NewPVZ("Москва")
 	pvz.ID = "pvz-123"
@@ -85,8 +90,9 @@ func TestReceptionService_AddAndRemoveProduct(t *testing.T) {
 	mockPVZRepo := tests.NewMockPVZRepository()
 	mockReceptionRepo := tests.NewMockReceptionRepository()
 	mockProductRepo := tests.NewMockProductRepository()
+	businessMetrics := tests.NewMockBusinessMetrics()
 
-	service := services.NewReceptionService(mockPVZRepo, mockReceptionRepo, mockProductRepo)
+	service := services.NewReceptionService(mockPVZRepo, mockReceptionRepo, mockProductRepo, tests.NewMockAssignmentRepository(), tests.NewMockAuditRepository(), tests.NewMockOutboxRepository(), tests.NewMockTransactor(), tests.NewTestPolicy(), businessMetrics, tests.NewTestLogger())
 
 	pvz, _ := domain.NewPVZ("Москва")
 	pvz.ID = "pvz-123"
@@ -139,6 +145,19 @@ func TestReceptionService_AddAndRemoveProduct(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error when removing product from closed reception, got nil")
 	}
+	// метрики учитывают тип и город, неудачные операции не считаются
+	if businessMetrics.AddedProducts["Москва/"+domain.ProductTypeClothes] != 1 {
+		t.Errorf("Expected 1 clothes product added metric, got %d", businessMetrics.AddedProducts["Москва/"+domain.ProductTypeClothes])
+	}
+	if businessMetrics.RemovedProducts["Москва/"+domain.ProductTypeClothes] != 1 {
+		t.Errorf("Expected 1 clothes product removed metric, got %d", businessMetrics.RemovedProducts["Москва/"+domain.ProductTypeClothes])
+	}
+	if businessMetrics.AddedProducts["Москва/"+domain.ProductTypeShoes] != 0 {
+		t.Error("Expected failed product addition not to be counted")
+	}
+	if businessMetrics.ClosedReceptions["Москва"] != 1 || len(businessMetrics.ReceptionDurations) != 1 {
+		t.Error("Expected reception close to be counted with its duration")
+	}
 }
 
 // Дополнительный тест для новых методов
@@ -148,7 +167,7 @@ func TestReceptionService_GetReceptionsAndProducts(t *testing.T) {
 	mockReceptionRepo := tests.NewMockReceptionRepository()
 	mockProductRepo := tests.NewMockProductRepository()
 
-	service := services.NewReceptionService(mockPVZRepo, mockReceptionRepo, mockProductRepo)
+	service := services.NewReceptionService(mockPVZRepo, mockReceptionRepo, mockProductRepo, tests.NewMockAssignmentRepository(), tests.NewMockAuditRepository(), tests.NewMockOutboxRepository(), tests.NewMockTransactor(), tests.NewTestPolicy(), tests.NewMockBusinessMetrics(), tests.NewTestLogger())
 
 	pvz, _ := domain.NewPVZ("Москва")
 	pvz.ID = "pvz-123"
@@ -184,3 +203,350 @@ func TestReceptionService_GetReceptionsAndProducts(t *testing.T) {
 		t.Errorf("Expected 1 product after deletion, got %d", len(products))
 	}
 }
+
+func TestReceptionService_ListStaleReceptions(t *testing.T) {
+	ctx := context.Background()
+	mockPVZRepo := tests.NewMockPVZRepository()
+
+	service := services.NewReceptionService(mockPVZRepo, tests.NewMockReceptionRepository(), tests.NewMockProductRepository(), tests.NewMockAssignmentRepository(), tests.NewMockAuditRepository(), tests.NewMockOutboxRepository(), tests.NewMockTransactor(), tests.NewTestPolicy(), tests.NewMockBusinessMetrics(), tests.NewTestLogger())
+
+	pvz, _ := domain.NewPVZ("Москва")
+	pvz.ID = "pvz-123"
+	mockPVZRepo.Create(ctx, pvz)
+
+	reception, _ := service.CreateReception(ctx, pvz.ID)
+	reception.DateTime = time.Now().Add(-48 * time.Hour)
+
+	stale, err := service.ListStaleReceptions(ctx, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(stale) != 1 || stale[0].ID != reception.ID {
+		t.Fatalf("Expected reception %s to be stale, got %v", reception.ID, stale)
+	}
+
+	// приемка моложе порога не считается зависшей
+	stale, _ = service.ListStaleReceptions(ctx, time.Now().Add(-72*time.Hour))
+	if len(stale) != 0 {
+		t.Errorf("Expected no stale receptions, got %d", len(stale))
+	}
+
+	// закрытая приемка не считается зависшей
+	_, _ = service.CloseReception(ctx, pvz.ID)
+	stale, _ = service.ListStaleReceptions(ctx, time.Now().Add(-24*time.Hour))
+	if len(stale) != 0 {
+		t.Errorf("Expected no stale receptions after close, got %d", len(stale))
+	}
+}
+
+func TestReceptionService_EmployeePVZAccess(t *testing.T) {
+	ctx := context.Background()
+	mockPVZRepo := tests.NewMockPVZRepository()
+	mockReceptionRepo := tests.NewMockReceptionRepository()
+	mockProductRepo := tests.NewMockProductRepository()
+	mockAssignmentRepo := tests.NewMockAssignmentRepository()
+
+	service := services.NewReceptionService(mockPVZRepo, mockReceptionRepo, mockProductRepo, mockAssignmentRepo, tests.NewMockAuditRepository(), tests.NewMockOutboxRepository(), tests.NewMockTransactor(), tests.NewTestPolicy(), tests.NewMockBusinessMetrics(), tests.NewTestLogger())
+
+	pvz, _ := domain.NewPVZ("Москва")
+	pvz.ID = "pvz-123"
+	mockPVZRepo.Create(ctx, pvz)
+
+	employee := &domain.User{ID: "employee-1", Role: domain.EmployeeRole}
+	employeeCtx := auth.WithUser(ctx, employee)
+
+	// сотрудник не закреплен за ПВЗ - все операции должны быть отклонены
+	_, err := service.CreateReception(employeeCtx, pvz.ID)
+	if !errors.Is(err, reception.ErrPVZAccessDenied) {
+		t.Errorf("Expected ErrPVZAccessDenied when creating reception, got: %v", err)
+	}
+
+	// несуществующий ПВЗ неотличим от чужого
+	_, err = service.CreateReception(employeeCtx, "pvz-unknown")
+	if !errors.Is(err, reception.ErrPVZAccessDenied) {
+		t.Errorf("Expected ErrPVZAccessDenied for unknown PVZ, got: %v", err)
+	}
+	err = service.DeleteLastProduct(employeeCtx, "pvz-unknown")
+	if !errors.Is(err, reception.ErrPVZAccessDenied) {
+		t.Errorf("Expected ErrPVZAccessDenied when deleting product in unknown PVZ, got: %v", err)
+	}
+
+	// приемку открывает внутренний вызов без пользователя в контексте
+	_, _ = service.CreateReception(ctx, pvz.ID)
+
+	_, err = service.AddProduct(employeeCtx, pvz.ID, domain.ProductTypeShoes)
+	if !errors.Is(err, reception.ErrPVZAccessDenied) {
+		t.Errorf("Expected ErrPVZAccessDenied when adding product, got: %v", err)
+	}
+
+	err = service.DeleteLastProduct(employeeCtx, pvz.ID)
+	if !errors.Is(err, reception.ErrPVZAccessDenied) {
+		t.Errorf("Expected ErrPVZAccessDenied when deleting product, got: %v", err)
+	}
+
+	_, err = service.CloseReception(employeeCtx, pvz.ID)
+	if !errors.Is(err, reception.ErrPVZAccessDenied) {
+		t.Errorf("Expected ErrPVZAccessDenied when closing reception, got: %v", err)
+	}
+
+	// после закрепления сотрудник может работать с ПВЗ
+	assignment, _ := domain.NewAssignment(*employee, pvz.ID)
+	mockAssignmentRepo.Create(ctx, assignment)
+
+	_, err = service.AddProduct(employeeCtx, pvz.ID, domain.ProductTypeShoes)
+	if err != nil {
+		t.Errorf("Expected no error for assigned employee, got: %v", err)
+	}
+
+	_, err = service.CloseReception(employeeCtx, pvz.ID)
+	if err != nil {
+		t.Errorf("Expected no error when assigned employee closes reception, got: %v", err)
+	}
+}
+
+func TestReceptionService_CustomRolePVZAccess(t *testing.T) {
+	ctx := context.Background()
+	mockPVZRepo := tests.NewMockPVZRepository()
+	mockAssignmentRepo := tests.NewMockAssignmentRepository()
+
+	policy, err := auth.NewPolicyFromConfig("supervisor=pvz:read,reception:create;regional=reception:create,pvz:any")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	service := services.NewReceptionService(mockPVZRepo, tests.NewMockReceptionRepository(), tests.NewMockProductRepository(), mockAssignmentRepo, tests.NewMockAuditRepository(), tests.NewMockOutboxRepository(), tests.NewMockTransactor(), policy, tests.NewMockBusinessMetrics(), tests.NewTestLogger())
+
+	pvz, _ := domain.NewPVZ("Москва")
+	pvz.ID = "pvz-123"
+	mockPVZRepo.Create(ctx, pvz)
+
+	// своя роль без pvz:any ограничена закрепленными ПВЗ, как сотрудник
+	supervisor := &domain.User{ID: "supervisor-1", Role: "supervisor"}
+	_, err = service.CreateReception(auth.WithUser(ctx, supervisor), pvz.ID)
+	if !errors.Is(err, reception.ErrPVZAccessDenied) {
+		t.Errorf("Expected ErrPVZAccessDenied for unassigned custom role, got: %v", err)
+	}
+
+	// роль с pvz:any работает с любым ПВЗ
+	regional := &domain.User{ID: "regional-1", Role: "regional"}
+	_, err = service.CreateReception(auth.WithUser(ctx, regional), pvz.ID)
+	if err != nil {
+		t.Errorf("Expected no error for role with pvz:any, got: %v", err)
+	}
+}
+
+func TestReceptionService_ServicePrincipalPVZAccess(t *testing.T) {
+	ctx := context.Background()
+	mockPVZRepo := tests.NewMockPVZRepository()
+
+	service := services.NewReceptionService(mockPVZRepo, tests.NewMockReceptionRepository(), tests.NewMockProductRepository(), tests.NewMockAssignmentRepository(), tests.NewMockAuditRepository(), tests.NewMockOutboxRepository(), tests.NewMockTransactor(), tests.NewTestPolicy(), tests.NewMockBusinessMetrics(), tests.NewTestLogger())
+
+	pvz, _ := domain.NewPVZ("Москва")
+	pvz.ID = "pvz-123"
+	mockPVZRepo.Create(ctx, pvz)
+
+	// ключ, выпущенный до ограничения прав, не должен открывать приемки ни в одном ПВЗ
+	keyCtx := auth.WithServicePrincipal(ctx, &auth.ServicePrincipal{
+		KeyID:  "key-1",
+		Scopes: []auth.Permission{auth.PermissionReceptionCreate},
+	})
+	_, err := service.CreateReception(keyCtx, pvz.ID)
+	if !errors.Is(err, reception.ErrPVZAccessDenied) {
+		t.Errorf("Expected ErrPVZAccessDenied for service principal, got: %v", err)
+	}
+}
+
+func TestReceptionService_AuditLog(t *testing.T) {
+	ctx := context.Background()
+	mockPVZRepo := tests.NewMockPVZRepository()
+	mockAuditRepo := tests.NewMockAuditRepository()
+	mockTransactor := tests.NewMockTransactor()
+
+	service := services.NewReceptionService(mockPVZRepo, tests.NewMockReceptionRepository(), tests.NewMockProductRepository(),
+		tests.NewMockAssignmentRepository(), mockAuditRepo, tests.NewMockOutboxRepository(), mockTransactor, tests.NewTestPolicy(), tests.NewMockBusinessMetrics(), tests.NewTestLogger())
+
+	pvz, _ := domain.NewPVZ("Москва")
+	pvz.ID = "pvz-123"
+	mockPVZRepo.Create(ctx, pvz)
+
+	moderator := &domain.User{ID: "moderator-1", Role: domain.ModeratorRole}
+	moderatorCtx := auth.WithUser(ctx, moderator)
+
+	created, _ := service.CreateReception(moderatorCtx, pvz.ID)
+	product, _ := service.AddProduct(moderatorCtx, pvz.ID, domain.ProductTypeShoes)
+	_ = service.RemoveLastProduct(moderatorCtx, pvz.ID)
+	_, err := service.CloseReception(moderatorCtx, pvz.ID)
+	if err != nil {
+		t.Fatalf("Expected no error when closing reception, got: %v", err)
+	}
+
+	expected := []struct {
+		action   string
+		entityID string
+	}{
+		{domain.AuditActionReceptionCreate, created.ID},
+		{domain.AuditActionProductAdd, product.ID},
+		{domain.AuditActionProductRemove, product.ID},
+		{domain.AuditActionReceptionClose, created.ID},
+	}
+	if len(mockAuditRepo.Entries) != len(expected) {
+		t.Fatalf("Expected %d audit entries, got %d", len(expected), len(mockAuditRepo.Entries))
+	}
+	for i, want := range expected {
+		entry := mockAuditRepo.Entries[i]
+		if entry.Action != want.action || entry.EntityID != want.entityID {
+			t.Errorf("Entry %d: expected %s of %s, got %s of %s", i, want.action, want.entityID, entry.Action, entry.EntityID)
+		}
+		if entry.ActorID != moderator.ID || entry.ActorRole != string(domain.ModeratorRole) {
+			t.Errorf("Entry %d: expected actor %s, got %s (%s)", i, moderator.ID, entry.ActorID, entry.ActorRole)
+		}
+	}
+	if mockTransactor.Calls != len(expected) {
+		t.Errorf("Expected each change in its own transaction, got %d transactions", mockTransactor.Calls)
+	}
+
+	closeEntry := mockAuditRepo.Entries[3]
+	if !strings.Contains(string(closeEntry.Before), domain.ReceptionStatusInProgress) ||
+		!strings.Contains(string(closeEntry.After), domain.ReceptionStatusClosed) {
+		t.Errorf("Expected before/after snapshots of status change, got %s -> %s", closeEntry.Before, closeEntry.After)
+	}
+}
+
+func TestReceptionService_AuditFailureFailsChange(t *testing.T) {
+	ctx := context.Background()
+	mockPVZRepo := tests.NewMockPVZRepository()
+	mockAuditRepo := tests.NewMockAuditRepository()
+	businessMetrics := tests.NewMockBusinessMetrics()
+
+	service := services.NewReceptionService(mockPVZRepo, tests.NewMockReceptionRepository(), tests.NewMockProductRepository(),
+		tests.NewMockAssignmentRepository(), mockAuditRepo, tests.NewMockOutboxRepository(), tests.NewMockTransactor(), tests.NewTestPolicy(), businessMetrics, tests.NewTestLogger())
+
+	pvz, _ := domain.NewPVZ("Москва")
+	pvz.ID = "pvz-123"
+	mockPVZRepo.Create(ctx, pvz)
+
+	mockAuditRepo.Err = errors.New("audit unavailable")
+
+	_, err := service.CreateReception(ctx, pvz.ID)
+	if err == nil {
+		t.Fatal("Expected error when audit entry cannot be written, got nil")
+	}
+	if businessMetrics.OpenedReceptions["Москва"] != 0 {
+		t.Error("Expected no metrics for failed change")
+	}
+}
+
+func TestReceptionService_OutboxEvents(t *testing.T) {
+	ctx := context.Background()
+	mockPVZRepo := tests.NewMockPVZRepository()
+	mockOutboxRepo := tests.NewMockOutboxRepository()
+
+	service := services.NewReceptionService(mockPVZRepo, tests.NewMockReceptionRepository(), tests.NewMockProductRepository(),
+		tests.NewMockAssignmentRepository(), tests.NewMockAuditRepository(), mockOutboxRepo, tests.NewMockTransactor(), tests.NewTestPolicy(), tests.NewMockBusinessMetrics(), tests.NewTestLogger())
+
+	pvz, _ := domain.NewPVZ("Москва")
+	pvz.ID = "pvz-123"
+	mockPVZRepo.Create(ctx, pvz)
+
+	created, _ := service.CreateReception(ctx, pvz.ID)
+	product, _ := service.AddProduct(ctx, pvz.ID, domain.ProductTypeShoes)
+	_ = service.RemoveLastProduct(ctx, pvz.ID)
+	_, err := service.CloseReception(ctx, pvz.ID)
+	if err != nil {
+		t.Fatalf("Expected no error when closing reception, got: %v", err)
+	}
+
+	expected := []struct {
+		eventType   string
+		aggregateID string
+	}{
+		{domain.EventReceptionCreated, created.ID},
+		{domain.EventProductAdded, product.ID},
+		{domain.EventProductRemoved, product.ID},
+		{domain.EventReceptionClosed, created.ID},
+	}
+	if len(mockOutboxRepo.Events) != len(expected) {
+		t.Fatalf("Expected %d events, got %d", len(expected), len(mockOutboxRepo.Events))
+	}
+	for i, want := range expected {
+		event := mockOutboxRepo.Events[i]
+		if event.Type != want.eventType || event.AggregateID != want.aggregateID {
+			t.Errorf("Event %d: expected %s of %s, got %s of %s", i, want.eventType, want.aggregateID, event.Type, event.AggregateID)
+		}
+	}
+
+	closed := mockOutboxRepo.Events[3]
+	if !strings.Contains(string(closed.Payload), domain.ReceptionStatusClosed) ||
+		!strings.Contains(string(closed.Payload), pvz.ID) {
+		t.Errorf("Expected closed reception in payload, got %s", closed.Payload)
+	}
+
+	// событие не записано - изменение не выполняется
+	mockOutboxRepo.Err = errors.New("outbox unavailable")
+	_, err = service.CreateReception(ctx, pvz.ID)
+	if err == nil {
+		t.Fatal("Expected error when event cannot be written, got nil")
+	}
+}
+
+// snapshotReceptionRepository отдает копию приемки, прочитанную до
+// параллельного изменения, как это видит транзакция без блокировки строки
+type snapshotReceptionRepository struct {
+	*tests.MockReceptionRepository
+	snapshot domain.Reception
+}
+
+func (r *snapshotReceptionRepository) GetByID(ctx context.Context, id string) (*domain.Reception, error) {
+	snapshot := r.snapshot
+	return &snapshot, nil
+}
+
+func TestReceptionService_StaleJobDoesNotOverwriteClosedReception(t *testing.T) {
+	ctx := context.Background()
+	mockPVZRepo := tests.NewMockPVZRepository()
+	mockReceptionRepo := tests.NewMockReceptionRepository()
+	mockOutboxRepo := tests.NewMockOutboxRepository()
+
+	pvz, _ := domain.NewPVZ("Москва")
+	pvz.ID = "pvz-123"
+	mockPVZRepo.Create(ctx, pvz)
+
+	service := services.NewReceptionService(mockPVZRepo, mockReceptionRepo, tests.NewMockProductRepository(),
+		tests.NewMockAssignmentRepository(), tests.NewMockAuditRepository(), mockOutboxRepo, tests.NewMockTransactor(), tests.NewTestPolicy(), tests.NewMockBusinessMetrics(), tests.NewTestLogger())
+
+	created, err := service.CreateReception(ctx, pvz.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	// фоновая задача прочитала приемку до закрытия
+	staleRead := &snapshotReceptionRepository{MockReceptionRepository: mockReceptionRepo, snapshot: *created}
+
+	_, err = service.CloseReception(ctx, pvz.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	eventsAfterClose := len(mockOutboxRepo.Events)
+
+	staleService := services.NewReceptionService(mockPVZRepo, staleRead, tests.NewMockProductRepository(),
+		tests.NewMockAssignmentRepository(), tests.NewMockAuditRepository(), mockOutboxRepo, tests.NewMockTransactor(), tests.NewTestPolicy(), tests.NewMockBusinessMetrics(), tests.NewTestLogger())
+
+	// повторное закрытие не проходит и не публикует второе событие
+	_, err = staleService.CloseStaleReception(ctx, created.ID, "зависла")
+	if !errors.Is(err, reception.ErrReceptionNotActive) {
+		t.Errorf("Expected ErrReceptionNotActive on stale close, got: %v", err)
+	}
+	if len(mockOutboxRepo.Events) != eventsAfterClose {
+		t.Errorf("Expected no duplicate close event, got %d events after %d", len(mockOutboxRepo.Events), eventsAfterClose)
+	}
+
+	// отметка по устаревшему чтению не переоткрывает приемку
+	_, err = staleService.MarkReceptionStale(ctx, created.ID)
+	if !errors.Is(err, reception.ErrReceptionNotActive) {
+		t.Errorf("Expected ErrReceptionNotActive on stale mark, got: %v", err)
+	}
+
+	stored, _ := mockReceptionRepo.GetByID(ctx, created.ID)
+	if stored.IsActive() || stored.CloseReason != "" {
+		t.Errorf("Expected reception to stay closed manually, got status %s, reason %q", stored.Status, stored.CloseReason)
+	}
+}
@@ -2,27 +2,58 @@ package user
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/dkumancev/avito-pvz/pkg/application/auth"
 	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
-func (s *service) Login(ctx context.Context, email, password string) (string, error) {
+func (s *service) Login(ctx context.Context, email, password string) (*LoginResult, error) {
 	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
-		return "", ErrInvalidCredentials
+		return nil, ErrInvalidCredentials
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
 	if err != nil {
-		return "", ErrInvalidCredentials
+		return nil, ErrInvalidCredentials
+	}
+
+	twoFactor, err := s.twoFactorRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения настроек 2FA: %w", err)
+	}
+
+	// При включенной 2FA токен доступа выдается только после проверки кода
+	if twoFactor != nil && twoFactor.IsEnabled() {
+		// jti связывает попытки ввода кода с конкретным токеном
+		challenge, err := s.signToken(jwt.MapClaims{
+			"id":                user.ID,
+			"jti":               uuid.New().String(),
+			auth.ChallengeClaim: true,
+			"exp":               time.Now().Add(challengeExpiry).Unix(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{ChallengeToken: challenge}, nil
 	}
 
 	// Генерируем JWT токен
-	return s.generateToken(user)
+	token, err := s.generateToken(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginResult{Token: token}, nil
 }
 
-// DummyLogin создает тестовый токен с указанной ролью
+// DummyLogin создает тестовый токен с указанной ролью.
+// Токен помечается отметкой auth.DummyClaim, чтобы его можно было отклонить там, где тестовый вход выключен
 func (s *service) DummyLogin(ctx context.Context, role domain.UserRole) (string, error) {
 	//  фиктивный пользователь для тестирования
 	dummyUser := domain.User{
@@ -31,5 +62,8 @@ func (s *service) DummyLogin(ctx context.Context, role domain.UserRole) (string,
 		Role:  role,
 	}
 
-	return s.generateToken(dummyUser)
+	claims := s.userClaims(dummyUser)
+	claims[auth.DummyClaim] = true
+
+	return s.signToken(claims)
 }
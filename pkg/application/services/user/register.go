@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/dkumancev/avito-pvz/pkg/application/audit"
 	"github.com/dkumancev/avito-pvz/pkg/domain"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -27,5 +28,20 @@ func (s *service) Register(ctx context.Context, email, password string, role dom
 		return domain.User{}, fmt.Errorf("ошибка создания пользователя: %w", err)
 	}
 
-	return s.userRepo.Create(ctx, user)
+	var savedUser domain.User
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		savedUser, err = s.userRepo.Create(ctx, user)
+		if err != nil {
+			return err
+		}
+
+		// регистрация публичная: инициатор - сам новый пользователь
+		return audit.RecordActor(ctx, s.auditRepo, savedUser.ID, string(savedUser.Role),
+			domain.AuditActionUserRegister, domain.AuditEntityUser, savedUser.ID, nil, savedUser)
+	})
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	return savedUser, nil
 }
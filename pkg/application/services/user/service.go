@@ -10,48 +10,112 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// время жизни промежуточного токена входа, ожидающего код второго фактора
+const challengeExpiry = 5 * time.Minute
+
+// число кодов, которые можно проверить по одному промежуточному токену входа
+const maxChallengeAttempts = 5
+
+// название сервиса в приложении-аутентификаторе
+const totpIssuer = "PVZ Avito"
+
 var (
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrUserAlreadyExists  = errors.New("user with this email already exists")
+
+	ErrInvalidChallenge         = errors.New("недействительный или истекший токен входа")
+	ErrInvalidTwoFactorCode     = errors.New("неверный код подтверждения")
+	ErrTwoFactorNotEnrolled     = errors.New("двухфакторная аутентификация не настроена")
+	ErrTwoFactorAlreadyEnabled  = errors.New("двухфакторная аутентификация уже включена")
+	ErrTooManyTwoFactorAttempts = errors.New("превышено число попыток ввода кода, выполните вход заново")
 )
 
+// LoginResult результат проверки пароля. Если у пользователя включена 2FA,
+// вместо токена доступа возвращается ChallengeToken для второго шага входа
+type LoginResult struct {
+	Token          string
+	ChallengeToken string
+}
+
+// TwoFactorRequired проверяет, нужен ли второй шаг входа
+func (r *LoginResult) TwoFactorRequired() bool {
+	return r.ChallengeToken != ""
+}
+
+// TwoFactorEnrollment данные для добавления секрета в приложение-аутентификатор
+type TwoFactorEnrollment struct {
+	Secret string
+	URI    string
+}
+
+// TwoFactorActivation результат включения 2FA: одноразовые коды восстановления
+// (показываются только один раз) и новый токен доступа с подтвержденным вторым фактором
+type TwoFactorActivation struct {
+	RecoveryCodes []string
+	Token         string
+}
+
 type Service interface {
 	// Регистрация нового пользователя
 	Register(ctx context.Context, email, password string, role domain.UserRole) (domain.User, error)
 
-	// Вход пользователя
-	Login(ctx context.Context, email, password string) (string, error)
+	// Вход пользователя. При включенной 2FA возвращает токен для второго шага
+	Login(ctx context.Context, email, password string) (*LoginResult, error)
+
+	// Второй шаг входа: проверка TOTP кода или кода восстановления
+	VerifyLoginCode(ctx context.Context, challengeToken, code string) (string, error)
+
+	// Начало привязки приложения-аутентификатора
+	EnrollTwoFactor(ctx context.Context, userID string) (*TwoFactorEnrollment, error)
+
+	// Подтверждение привязки первым кодом из приложения
+	ConfirmTwoFactor(ctx context.Context, userID, code string) (*TwoFactorActivation, error)
 
 	// Тестовый вход для получения токена с заданной ролью
 	DummyLogin(ctx context.Context, role domain.UserRole) (string, error)
 }
 
 type service struct {
-	userRepo    repositories.UserRepository
-	jwtSecret   []byte
-	tokenExpiry time.Duration
+	userRepo      repositories.UserRepository
+	twoFactorRepo repositories.TwoFactorRepository
+	auditRepo     repositories.AuditRepository
+	transactor    repositories.Transactor
+	jwtSecret     []byte
+	tokenExpiry   time.Duration
 }
 
 func New(
 	userRepo repositories.UserRepository,
+	twoFactorRepo repositories.TwoFactorRepository,
+	auditRepo repositories.AuditRepository,
+	transactor repositories.Transactor,
 	jwtSecret []byte,
 	tokenExpiry time.Duration,
 ) Service {
 	return &service{
-		userRepo:    userRepo,
-		jwtSecret:   jwtSecret,
-		tokenExpiry: tokenExpiry,
+		userRepo:      userRepo,
+		twoFactorRepo: twoFactorRepo,
+		auditRepo:     auditRepo,
+		transactor:    transactor,
+		jwtSecret:     jwtSecret,
+		tokenExpiry:   tokenExpiry,
 	}
 }
 
 func (s *service) generateToken(user domain.User) (string, error) {
-	claims := jwt.MapClaims{
+	return s.signToken(s.userClaims(user))
+}
+
+func (s *service) userClaims(user domain.User) jwt.MapClaims {
+	return jwt.MapClaims{
 		"id":    user.ID,
 		"email": user.Email,
 		"role":  user.Role,
 		"exp":   time.Now().Add(s.tokenExpiry).Unix(),
 	}
+}
 
+func (s *service) signToken(claims jwt.MapClaims) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString(s.jwtSecret)
 }
@@ -0,0 +1,202 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/auth"
+	"github.com/dkumancev/avito-pvz/pkg/application/services"
+	userServices "github.com/dkumancev/avito-pvz/pkg/application/services/user"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/tests"
+)
+
+func TestUserService_TwoFactorLogin(t *testing.T) {
+	ctx := context.Background()
+	jwtSecret := []byte("test-secret")
+	service := services.NewUserService(NewMockUserRepository(), tests.NewMockTwoFactorRepository(), tests.NewMockAuditRepository(), tests.NewMockTransactor(), jwtSecret, time.Hour)
+	parser := auth.NewTokenParser(jwtSecret, false)
+
+	email := "moderator@example.com"
+	password := "password123"
+	user, _ := service.Register(ctx, email, password, domain.ModeratorRole)
+
+	// привязка приложения
+	enrollment, err := service.EnrollTwoFactor(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("Ожидалось отсутствие ошибки, получено: %v", err)
+	}
+	if enrollment.Secret == "" || enrollment.URI == "" {
+		t.Fatal("Ожидались секрет и ссылка otpauth")
+	}
+
+	step := auth.TOTPStep(time.Now())
+	code, _ := auth.TOTPCode(enrollment.Secret, step)
+
+	_, err = service.ConfirmTwoFactor(ctx, user.ID, "000000")
+	if !errors.Is(err, userServices.ErrInvalidTwoFactorCode) {
+		t.Errorf("Ожидалась ошибка неверного кода, получено: %v", err)
+	}
+
+	activation, err := service.ConfirmTwoFactor(ctx, user.ID, code)
+	if err != nil {
+		t.Fatalf("Ожидалось отсутствие ошибки, получено: %v", err)
+	}
+	if len(activation.RecoveryCodes) != 10 {
+		t.Errorf("Ожидалось 10 кодов восстановления, получено %d", len(activation.RecoveryCodes))
+	}
+
+	authCtx, err := parser.Authenticate(ctx, activation.Token)
+	if err != nil || !auth.TwoFactorVerified(authCtx) {
+		t.Errorf("Токен после включения 2FA должен быть отмечен вторым фактором, ошибка: %v", err)
+	}
+
+	// повторная привязка невозможна
+	_, err = service.EnrollTwoFactor(ctx, user.ID)
+	if !errors.Is(err, userServices.ErrTwoFactorAlreadyEnabled) {
+		t.Errorf("Ожидалась ошибка ErrTwoFactorAlreadyEnabled, получено: %v", err)
+	}
+
+	// первый шаг входа возвращает только промежуточный токен
+	result, err := service.Login(ctx, email, password)
+	if err != nil {
+		t.Fatalf("Ожидалось отсутствие ошибки, получено: %v", err)
+	}
+	if !result.TwoFactorRequired() || result.Token != "" {
+		t.Fatal("Ожидался второй шаг входа без токена доступа")
+	}
+	if _, err := parser.Parse(result.ChallengeToken); err == nil {
+		t.Error("Промежуточный токен не должен давать доступ к API")
+	}
+
+	// код уже использованного интервала отклоняется
+	_, err = service.VerifyLoginCode(ctx, result.ChallengeToken, code)
+	if !errors.Is(err, userServices.ErrInvalidTwoFactorCode) {
+		t.Errorf("Ожидалась ошибка повторного кода, получено: %v", err)
+	}
+
+	nextCode, _ := auth.TOTPCode(enrollment.Secret, step+1)
+	token, err := service.VerifyLoginCode(ctx, result.ChallengeToken, nextCode)
+	if err != nil {
+		t.Fatalf("Ожидалось отсутствие ошибки, получено: %v", err)
+	}
+	if _, err := parser.Parse(token); err != nil {
+		t.Errorf("Ожидался действительный токен доступа, получено: %v", err)
+	}
+
+	// код восстановления одноразовый
+	recoveryCode := activation.RecoveryCodes[0]
+	if _, err := service.VerifyLoginCode(ctx, result.ChallengeToken, recoveryCode); err != nil {
+		t.Errorf("Код восстановления должен приниматься, получено: %v", err)
+	}
+	if _, err := service.VerifyLoginCode(ctx, result.ChallengeToken, recoveryCode); !errors.Is(err, userServices.ErrInvalidTwoFactorCode) {
+		t.Errorf("Повторный код восстановления должен отклоняться, получено: %v", err)
+	}
+
+	// токен доступа нельзя использовать как промежуточный
+	if _, err := service.VerifyLoginCode(ctx, token, nextCode); !errors.Is(err, userServices.ErrInvalidChallenge) {
+		t.Errorf("Ожидалась ошибка ErrInvalidChallenge, получено: %v", err)
+	}
+}
+
+func TestUserService_ConfirmTwoFactor_NotEnrolled(t *testing.T) {
+	ctx := context.Background()
+	service := services.NewUserService(NewMockUserRepository(), tests.NewMockTwoFactorRepository(), tests.NewMockAuditRepository(), tests.NewMockTransactor(), []byte("test-secret"), time.Hour)
+
+	user, _ := service.Register(ctx, "employee@example.com", "password123", domain.EmployeeRole)
+
+	_, err := service.ConfirmTwoFactor(ctx, user.ID, "123456")
+	if !errors.Is(err, userServices.ErrTwoFactorNotEnrolled) {
+		t.Errorf("Ожидалась ошибка ErrTwoFactorNotEnrolled, получено: %v", err)
+	}
+}
+
+// enableTwoFactor регистрирует пользователя с включенной 2FA и возвращает секрет и коды восстановления
+func enableTwoFactor(t *testing.T, service services.UserService, email, password string) (string, []string) {
+	t.Helper()
+	ctx := context.Background()
+
+	user, _ := service.Register(ctx, email, password, domain.ModeratorRole)
+	enrollment, err := service.EnrollTwoFactor(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("Ожидалось отсутствие ошибки, получено: %v", err)
+	}
+
+	code, _ := auth.TOTPCode(enrollment.Secret, auth.TOTPStep(time.Now()))
+	activation, err := service.ConfirmTwoFactor(ctx, user.ID, code)
+	if err != nil {
+		t.Fatalf("Ожидалось отсутствие ошибки, получено: %v", err)
+	}
+
+	return enrollment.Secret, activation.RecoveryCodes
+}
+
+func TestUserService_VerifyLoginCode_AttemptLimit(t *testing.T) {
+	ctx := context.Background()
+	service := services.NewUserService(NewMockUserRepository(), tests.NewMockTwoFactorRepository(), tests.NewMockAuditRepository(), tests.NewMockTransactor(), []byte("test-secret"), time.Hour)
+
+	_, recoveryCodes := enableTwoFactor(t, service, "moderator@example.com", "password123")
+
+	result, err := service.Login(ctx, "moderator@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Ожидалось отсутствие ошибки, получено: %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		_, err = service.VerifyLoginCode(ctx, result.ChallengeToken, "000000")
+		if !errors.Is(err, userServices.ErrInvalidTwoFactorCode) {
+			t.Fatalf("Попытка %d: ожидалась ошибка неверного кода, получено: %v", i+1, err)
+		}
+	}
+
+	// после лимита токен не принимает даже верный код
+	_, err = service.VerifyLoginCode(ctx, result.ChallengeToken, recoveryCodes[0])
+	if !errors.Is(err, userServices.ErrTooManyTwoFactorAttempts) {
+		t.Errorf("Ожидалась ошибка ErrTooManyTwoFactorAttempts, получено: %v", err)
+	}
+
+	// новый вход по паролю выдает новый токен со своим счетчиком
+	result, _ = service.Login(ctx, "moderator@example.com", "password123")
+	if _, err := service.VerifyLoginCode(ctx, result.ChallengeToken, recoveryCodes[0]); err != nil {
+		t.Errorf("Ожидалось отсутствие ошибки для нового токена, получено: %v", err)
+	}
+}
+
+func TestUserService_VerifyLoginCode_RecoveryCodeUsedOnce(t *testing.T) {
+	ctx := context.Background()
+	service := services.NewUserService(NewMockUserRepository(), tests.NewMockTwoFactorRepository(), tests.NewMockAuditRepository(), tests.NewMockTransactor(), []byte("test-secret"), time.Hour)
+
+	_, recoveryCodes := enableTwoFactor(t, service, "moderator@example.com", "password123")
+
+	// параллельные запросы с одним кодом восстановления: токен получает только один
+	const requests = 5
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+	)
+	for i := 0; i < requests; i++ {
+		result, err := service.Login(ctx, "moderator@example.com", "password123")
+		if err != nil {
+			t.Fatalf("Ожидалось отсутствие ошибки, получено: %v", err)
+		}
+
+		wg.Add(1)
+		go func(challengeToken string) {
+			defer wg.Done()
+			if _, err := service.VerifyLoginCode(ctx, challengeToken, recoveryCodes[0]); err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}(result.ChallengeToken)
+	}
+	wg.Wait()
+
+	if succeeded != 1 {
+		t.Errorf("Код восстановления должен быть принят ровно один раз, принят %d", succeeded)
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/dkumancev/avito-pvz/pkg/application/auth"
 	"github.com/dkumancev/avito-pvz/pkg/application/services"
 	userServices "github.com/dkumancev/avito-pvz/pkg/application/services/user"
 	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/tests"
 	"github.com/golang-jwt/jwt/v5"
 )
 
@@ -41,6 +43,15 @@ func (m *MockUserRepository) Create(ctx context.Context, user domain.User) (doma
 	return user, nil
 }
 
+func (m *MockUserRepository) GetByID(ctx context.Context, id string) (domain.User, error) {
+	for _, user := range m.users {
+		if user.ID == id {
+			return user, nil
+		}
+	}
+	return domain.User{}, errors.New("user not found")
+}
+
 func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (domain.User, error) {
 	user, exists := m.users[email]
 	if !exists {
@@ -62,7 +73,7 @@ func TestUserService_Register_Success(t *testing.T) {
 	jwtSecret := []byte("test-secret")
 	tokenExpiry := 24 * time.Hour
 
-	service := services.NewUserService(mockRepo, jwtSecret, tokenExpiry)
+	service := services.NewUserService(mockRepo, tests.NewMockTwoFactorRepository(), tests.NewMockAuditRepository(), tests.NewMockTransactor(), jwtSecret, tokenExpiry)
 
 	email := "test@example.com"
 	password := "password123"
@@ -97,7 +108,7 @@ func TestUserService_Register_UserAlreadyExists(t *testing.T) {
 	jwtSecret := []byte("test-secret")
 	tokenExpiry := 24 * time.Hour
 
-	service := services.NewUserService(mockRepo, jwtSecret, tokenExpiry)
+	service := services.NewUserService(mockRepo, tests.NewMockTwoFactorRepository(), tests.NewMockAuditRepository(), tests.NewMockTransactor(), jwtSecret, tokenExpiry)
 
 	email := "existing@example.com"
 	password := "password123"
@@ -127,7 +138,7 @@ func TestUserService_Register_InvalidData(t *testing.T) {
 	jwtSecret := []byte("test-secret")
 	tokenExpiry := 24 * time.Hour
 
-	service := services.NewUserService(mockRepo, jwtSecret, tokenExpiry)
+	service := services.NewUserService(mockRepo, tests.NewMockTwoFactorRepository(), tests.NewMockAuditRepository(), tests.NewMockTransactor(), jwtSecret, tokenExpiry)
 
 	testCases := []struct {
 		name     string
@@ -179,7 +190,7 @@ func TestUserService_Login_Success(t *testing.T) {
 	jwtSecret := []byte("test-secret")
 	tokenExpiry := 24 * time.Hour
 
-	service := services.NewUserService(mockRepo, jwtSecret, tokenExpiry)
+	service := services.NewUserService(mockRepo, tests.NewMockTwoFactorRepository(), tests.NewMockAuditRepository(), tests.NewMockTransactor(), jwtSecret, tokenExpiry)
 
 	email := "test@example.com"
 	password := "password123"
@@ -188,13 +199,18 @@ func TestUserService_Login_Success(t *testing.T) {
 	createdUser, _ := service.Register(ctx, email, password, role)
 
 	// logining
-	token, err := service.Login(ctx, email, password)
+	result, err := service.Login(ctx, email, password)
 
 	// Проверки
 	if err != nil {
-		t.Errorf("Ожидалось отсутствие ошибки, получено: %v", err)
+		t.Fatalf("Ожидалось отсутствие ошибки, получено: %v", err)
 	}
 
+	if result.TwoFactorRequired() {
+		t.Error("Без включенной 2FA второй шаг входа не нужен")
+	}
+
+	token := result.Token
 	if token == "" {
 		t.Error("Ожидался непустой JWT токен")
 	}
@@ -239,7 +255,7 @@ func TestUserService_Login_InvalidCredentials(t *testing.T) {
 	jwtSecret := []byte("test-secret")
 	tokenExpiry := 24 * time.Hour
 
-	service := services.NewUserService(mockRepo, jwtSecret, tokenExpiry)
+	service := services.NewUserService(mockRepo, tests.NewMockTwoFactorRepository(), tests.NewMockAuditRepository(), tests.NewMockTransactor(), jwtSecret, tokenExpiry)
 
 	email := "test@example.com"
 	password := "password123"
@@ -266,7 +282,7 @@ func TestUserService_Login_InvalidCredentials(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			token, err := service.Login(ctx, tc.loginEmail, tc.loginPassword)
+			result, err := service.Login(ctx, tc.loginEmail, tc.loginPassword)
 
 			if err == nil {
 				t.Errorf("Ожидалась ошибка при тесте %s, но ошибки не возникло", tc.name)
@@ -276,7 +292,7 @@ func TestUserService_Login_InvalidCredentials(t *testing.T) {
 				t.Errorf("Ожидалась ошибка %v, получена %v", ErrInvalidCredentials, err)
 			}
 
-			if token != "" {
+			if result != nil {
 				t.Error("Токен должен быть пустым при неверных учетных данных")
 			}
 		})
@@ -289,7 +305,7 @@ func TestUserService_DummyLogin(t *testing.T) {
 	jwtSecret := []byte("test-secret")
 	tokenExpiry := 24 * time.Hour
 
-	service := services.NewUserService(mockRepo, jwtSecret, tokenExpiry)
+	service := services.NewUserService(mockRepo, tests.NewMockTwoFactorRepository(), tests.NewMockAuditRepository(), tests.NewMockTransactor(), jwtSecret, tokenExpiry)
 
 	testCases := []struct {
 		name string
@@ -349,6 +365,10 @@ func TestUserService_DummyLogin(t *testing.T) {
 			if claims["role"] != string(tc.role) {
 				t.Errorf("В токене ожидалась роль %s, получена %v", tc.role, claims["role"])
 			}
+
+			if claims[auth.DummyClaim] != true {
+				t.Errorf("В тестовом токене ожидалась отметка %s, получено %v", auth.DummyClaim, claims[auth.DummyClaim])
+			}
 		})
 	}
 }
@@ -0,0 +1,250 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/audit"
+	"github.com/dkumancev/avito-pvz/pkg/application/auth"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// количество одноразовых кодов восстановления
+const recoveryCodesCount = 10
+
+func (s *service) EnrollTwoFactor(ctx context.Context, userID string) (*TwoFactorEnrollment, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения пользователя: %w", err)
+	}
+
+	existing, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения настроек 2FA: %w", err)
+	}
+	if existing != nil && existing.IsEnabled() {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка генерации секрета: %w", err)
+	}
+
+	// Повторная привязка до подтверждения заменяет прежний секрет
+	twoFactor, err := domain.NewTwoFactor(user.ID, secret)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания настроек 2FA: %w", err)
+	}
+
+	err = s.twoFactorRepo.Save(ctx, twoFactor)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сохранения настроек 2FA: %w", err)
+	}
+
+	return &TwoFactorEnrollment{
+		Secret: secret,
+		URI:    auth.TOTPURI(totpIssuer, user.Email, secret),
+	}, nil
+}
+
+func (s *service) ConfirmTwoFactor(ctx context.Context, userID, code string) (*TwoFactorActivation, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения пользователя: %w", err)
+	}
+
+	twoFactor, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения настроек 2FA: %w", err)
+	}
+	if twoFactor == nil {
+		return nil, ErrTwoFactorNotEnrolled
+	}
+	if twoFactor.IsEnabled() {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	step, ok := auth.ValidateTOTP(twoFactor.Secret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	err = twoFactor.UseStep(step)
+	if err != nil {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка генерации кодов восстановления: %w", err)
+	}
+
+	before := *twoFactor
+
+	err = twoFactor.Enable(hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.twoFactorRepo.Save(ctx, twoFactor)
+		if err != nil {
+			return fmt.Errorf("ошибка сохранения настроек 2FA: %w", err)
+		}
+
+		return audit.Record(ctx, s.auditRepo, domain.AuditActionTwoFactorEnable, domain.AuditEntityTwoFactor, userID, before, twoFactor)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.generateTwoFactorToken(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorActivation{
+		RecoveryCodes: codes,
+		Token:         token,
+	}, nil
+}
+
+func (s *service) VerifyLoginCode(ctx context.Context, challengeToken, code string) (string, error) {
+	challenge, err := s.parseChallenge(challengeToken)
+	if err != nil {
+		return "", err
+	}
+	userID := challenge.userID
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", ErrInvalidChallenge
+	}
+
+	twoFactor, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("ошибка получения настроек 2FA: %w", err)
+	}
+	if twoFactor == nil || !twoFactor.IsEnabled() {
+		return "", ErrTwoFactorNotEnrolled
+	}
+
+	// Попытка учитывается до проверки кода, чтобы параллельные запросы
+	// по одному токену не обходили лимит
+	attempts, err := s.twoFactorRepo.RegisterChallengeAttempt(ctx, challenge.id, userID, challenge.expiresAt)
+	if err != nil {
+		return "", fmt.Errorf("ошибка учета попытки входа: %w", err)
+	}
+	if attempts > maxChallengeAttempts {
+		return "", ErrTooManyTwoFactorAttempts
+	}
+
+	// Сначала проверяем TOTP код, затем коды восстановления. Код гасится
+	// условным обновлением, поэтому из параллельных запросов с одним кодом
+	// токен получит только один
+	var used bool
+	if step, ok := auth.ValidateTOTP(twoFactor.Secret, code, time.Now()); ok {
+		used, err = s.twoFactorRepo.UseStep(ctx, userID, step)
+	} else {
+		used, err = s.twoFactorRepo.UseRecoveryCode(ctx, userID, hashRecoveryCode(code))
+	}
+	if err != nil {
+		return "", fmt.Errorf("ошибка проверки кода 2FA: %w", err)
+	}
+	if !used {
+		return "", ErrInvalidTwoFactorCode
+	}
+
+	return s.generateTwoFactorToken(user)
+}
+
+func (s *service) generateTwoFactorToken(user domain.User) (string, error) {
+	claims := s.userClaims(user)
+	claims[auth.TwoFactorClaim] = true
+	return s.signToken(claims)
+}
+
+// loginChallenge данные промежуточного токена входа
+type loginChallenge struct {
+	id        string
+	userID    string
+	expiresAt time.Time
+}
+
+// parseChallenge проверяет промежуточный токен входа
+func (s *service) parseChallenge(challengeToken string) (*loginChallenge, error) {
+	token, err := jwt.Parse(challengeToken, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("неподдерживаемый метод подписи")
+		}
+		return s.jwtSecret, nil
+	})
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidChallenge
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrInvalidChallenge
+	}
+
+	if challenge, _ := claims[auth.ChallengeClaim].(bool); !challenge {
+		return nil, ErrInvalidChallenge
+	}
+
+	userID, ok := claims["id"].(string)
+	if !ok || userID == "" {
+		return nil, ErrInvalidChallenge
+	}
+
+	// без jti попытки нельзя ограничить, такой токен не принимается
+	id, ok := claims["jti"].(string)
+	if !ok || id == "" {
+		return nil, ErrInvalidChallenge
+	}
+
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil || expiresAt == nil {
+		return nil, ErrInvalidChallenge
+	}
+
+	return &loginChallenge{id: id, userID: userID, expiresAt: expiresAt.Time}, nil
+}
+
+// generateRecoveryCodes создает коды восстановления вида xxxx-xxxx и их хэши для хранения
+func generateRecoveryCodes() ([]string, []string, error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+
+	codes := make([]string, 0, recoveryCodesCount)
+	hashes := make([]string, 0, recoveryCodesCount)
+	for i := 0; i < recoveryCodesCount; i++ {
+		raw := make([]byte, 5)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, err
+		}
+
+		encoded := strings.ToLower(encoding.EncodeToString(raw))
+		code := encoded[:4] + "-" + encoded[4:]
+
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode хэширует код без учета регистра и дефисов
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,71 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/audit"
+	"github.com/dkumancev/avito-pvz/pkg/application/auth"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+func (s *service) CreateWebhook(ctx context.Context, url string, eventTypes []string, secret string) (*domain.Webhook, error) {
+	if secret == "" {
+		var err error
+		secret, err = generateSecret()
+		if err != nil {
+			return nil, fmt.Errorf("ошибка генерации секрета: %w", err)
+		}
+	}
+
+	var createdBy string
+	if user, ok := auth.UserFromContext(ctx); ok {
+		createdBy = user.ID
+	}
+
+	webhook, err := domain.NewWebhook(url, eventTypes, secret, createdBy)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания вебхука: %w", err)
+	}
+
+	var saved *domain.Webhook
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		saved, err = s.webhookRepo.Create(ctx, webhook)
+		if err != nil {
+			return fmt.Errorf("ошибка сохранения вебхука: %w", err)
+		}
+
+		// секрет не попадает в журнал: domain.Webhook не сериализует его
+		return audit.Record(ctx, s.auditRepo, domain.AuditActionWebhookCreate, domain.AuditEntityWebhook, saved.ID, nil, saved)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return saved, nil
+}
+
+func (s *service) ListWebhooks(ctx context.Context) ([]*domain.Webhook, error) {
+	webhooks, err := s.webhookRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения вебхуков: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+func (s *service) DeleteWebhook(ctx context.Context, id string) error {
+	webhook, err := s.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("ошибка получения вебхука: %w", err)
+	}
+
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.webhookRepo.Delete(ctx, id)
+		if err != nil {
+			return fmt.Errorf("ошибка удаления вебхука: %w", err)
+		}
+
+		return audit.Record(ctx, s.auditRepo, domain.AuditActionWebhookDelete, domain.AuditEntityWebhook, id, webhook, nil)
+	})
+}
@@ -0,0 +1,66 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/audit"
+	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+func (s *service) ListDeliveries(ctx context.Context, filter repositories.WebhookDeliveryFilter) ([]*domain.WebhookDelivery, error) {
+	switch filter.Status {
+	case "", domain.DeliveryPending, domain.DeliveryDelivered, domain.DeliveryDead:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidDeliveryStatus, filter.Status)
+	}
+
+	_, err := s.webhookRepo.GetByID(ctx, filter.WebhookID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения вебхука: %w", err)
+	}
+
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 || filter.Limit > maxLimit {
+		filter.Limit = defaultLimit
+	}
+
+	deliveries, err := s.deliveryRepo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения журнала доставок: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (s *service) Redeliver(ctx context.Context, deliveryID string) (*domain.WebhookDelivery, error) {
+	delivery, err := s.deliveryRepo.GetByID(ctx, deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения доставки: %w", err)
+	}
+
+	before := *delivery
+
+	err = delivery.Redeliver(time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("ошибка повторной отправки: %w", err)
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.deliveryRepo.Update(ctx, delivery)
+		if err != nil {
+			return fmt.Errorf("ошибка сохранения доставки: %w", err)
+		}
+
+		return audit.Record(ctx, s.auditRepo, domain.AuditActionWebhookRedeliver, domain.AuditEntityDelivery, delivery.ID, before, delivery)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
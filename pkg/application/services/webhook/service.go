@@ -0,0 +1,69 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+// префикс сгенерированного секрета подписи
+const secretPrefix = "whsec_"
+
+const (
+	defaultLimit = 50
+	maxLimit     = 100
+)
+
+var ErrInvalidDeliveryStatus = errors.New("неизвестный статус доставки")
+
+type Service interface {
+	// Регистрация вебхука. Если секрет не передан, он генерируется; секрет
+	// возвращается в ответе на создание и больше нигде не показывается
+	CreateWebhook(ctx context.Context, url string, eventTypes []string, secret string) (*domain.Webhook, error)
+
+	// Список вебхуков
+	ListWebhooks(ctx context.Context) ([]*domain.Webhook, error)
+
+	// Удаление вебхука вместе с журналом доставок
+	DeleteWebhook(ctx context.Context, id string) error
+
+	// Журнал доставок вебхука с фильтром по статусу
+	ListDeliveries(ctx context.Context, filter repositories.WebhookDeliveryFilter) ([]*domain.WebhookDelivery, error)
+
+	// Повторная отправка доставки, в том числе исчерпавшей попытки
+	Redeliver(ctx context.Context, deliveryID string) (*domain.WebhookDelivery, error)
+}
+
+type service struct {
+	webhookRepo  repositories.WebhookRepository
+	deliveryRepo repositories.WebhookDeliveryRepository
+	auditRepo    repositories.AuditRepository
+	transactor   repositories.Transactor
+}
+
+func New(
+	webhookRepo repositories.WebhookRepository,
+	deliveryRepo repositories.WebhookDeliveryRepository,
+	auditRepo repositories.AuditRepository,
+	transactor repositories.Transactor,
+) Service {
+	return &service{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+		auditRepo:    auditRepo,
+		transactor:   transactor,
+	}
+}
+
+func generateSecret() (string, error) {
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return secretPrefix + hex.EncodeToString(secret), nil
+}
@@ -0,0 +1,110 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/auth"
+	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
+	"github.com/dkumancev/avito-pvz/pkg/application/services"
+	"github.com/dkumancev/avito-pvz/pkg/application/services/webhook"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/tests"
+)
+
+func TestWebhookService_CreateGeneratesSecret(t *testing.T) {
+	ctx := auth.WithUser(context.Background(), &domain.User{ID: "moderator-1", Role: domain.ModeratorRole})
+	auditRepo := tests.NewMockAuditRepository()
+	service := services.NewWebhookService(tests.NewMockWebhookRepository(), tests.NewMockWebhookDeliveryRepository(),
+		auditRepo, tests.NewMockTransactor())
+
+	// Act
+	created, err := service.CreateWebhook(ctx, "https://partner.example/hooks", []string{domain.EventReceptionClosed}, "")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !strings.HasPrefix(created.Secret, "whsec_") || len(created.Secret) < domain.MinWebhookSecretLength {
+		t.Errorf("Expected generated secret, got %q", created.Secret)
+	}
+	if created.CreatedBy != "moderator-1" {
+		t.Errorf("Expected creator to be recorded, got %q", created.CreatedBy)
+	}
+	if len(auditRepo.Entries) != 1 || auditRepo.Entries[0].Action != domain.AuditActionWebhookCreate {
+		t.Fatalf("Expected webhook.create audit entry, got: %+v", auditRepo.Entries)
+	}
+	if strings.Contains(string(auditRepo.Entries[0].After), created.Secret) {
+		t.Error("Expected secret not to be written to the audit log")
+	}
+
+	_, err = service.CreateWebhook(ctx, "https://partner.example/hooks", []string{"pvz.deleted"}, "")
+	if err == nil {
+		t.Error("Expected error for unknown event type")
+	}
+}
+
+func TestWebhookService_DeliveriesAndRedeliver(t *testing.T) {
+	ctx := context.Background()
+	webhookRepo := tests.NewMockWebhookRepository()
+	deliveryRepo := tests.NewMockWebhookDeliveryRepository()
+	service := services.NewWebhookService(webhookRepo, deliveryRepo, tests.NewMockAuditRepository(), tests.NewMockTransactor())
+
+	created, _ := service.CreateWebhook(ctx, "https://partner.example/hooks", []string{domain.EventPVZCreated}, "")
+	delivery, _ := domain.NewWebhookDelivery(created, &domain.Event{ID: "event-1", Type: domain.EventPVZCreated})
+	deliveryRepo.Create(ctx, delivery)
+	delivery.MarkFailed(500, "internal error", delivery.NextAttemptAt, 1)
+
+	// журнал с фильтром по статусу
+	dead, err := service.ListDeliveries(ctx, repositories.WebhookDeliveryFilter{WebhookID: created.ID, Status: domain.DeliveryDead})
+	if err != nil || len(dead) != 1 {
+		t.Fatalf("Expected one dead delivery, got: %v, %v", dead, err)
+	}
+	_, err = service.ListDeliveries(ctx, repositories.WebhookDeliveryFilter{WebhookID: created.ID, Status: "lost"})
+	if !errors.Is(err, webhook.ErrInvalidDeliveryStatus) {
+		t.Errorf("Expected ErrInvalidDeliveryStatus, got: %v", err)
+	}
+	_, err = service.ListDeliveries(ctx, repositories.WebhookDeliveryFilter{WebhookID: "unknown"})
+	if err == nil {
+		t.Error("Expected error for unknown webhook")
+	}
+
+	// Act
+	redelivered, err := service.Redeliver(ctx, delivery.ID)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if redelivered.Status != domain.DeliveryPending || redelivered.Attempts != 0 {
+		t.Errorf("Expected delivery to be queued again, got: %+v", redelivered)
+	}
+
+	_, err = service.Redeliver(ctx, delivery.ID)
+	if err == nil {
+		t.Error("Expected error when redelivering pending delivery")
+	}
+}
+
+func TestWebhookService_Delete(t *testing.T) {
+	ctx := context.Background()
+	service := services.NewWebhookService(tests.NewMockWebhookRepository(), tests.NewMockWebhookDeliveryRepository(),
+		tests.NewMockAuditRepository(), tests.NewMockTransactor())
+
+	created, _ := service.CreateWebhook(ctx, "https://partner.example/hooks", []string{domain.EventPVZCreated}, "")
+
+	err := service.DeleteWebhook(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	webhooks, _ := service.ListWebhooks(ctx)
+	if len(webhooks) != 0 {
+		t.Errorf("Expected no webhooks after delete, got %d", len(webhooks))
+	}
+	if service.DeleteWebhook(ctx, created.ID) == nil {
+		t.Error("Expected error when deleting unknown webhook")
+	}
+}
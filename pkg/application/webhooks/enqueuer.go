@@ -0,0 +1,58 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+// Enqueuer - events.Publisher, который создает доставки для вебхуков,
+// подписанных на событие. Публикатор вызывается в транзакции задачи outbox,
+// поэтому доставки создаются ровно один раз вместе с отметкой о публикации
+type Enqueuer struct {
+	webhookRepo  repositories.WebhookRepository
+	deliveryRepo repositories.WebhookDeliveryRepository
+}
+
+func NewEnqueuer(webhookRepo repositories.WebhookRepository, deliveryRepo repositories.WebhookDeliveryRepository) *Enqueuer {
+	return &Enqueuer{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+	}
+}
+
+func (e *Enqueuer) Publish(ctx context.Context, events []*domain.Event) error {
+	// подписки одного типа события читаются один раз на пачку
+	subscribers := make(map[string][]*domain.Webhook)
+	for _, event := range events {
+		webhooks, ok := subscribers[event.Type]
+		if !ok {
+			var err error
+			webhooks, err = e.webhookRepo.ListByEventType(ctx, event.Type)
+			if err != nil {
+				return fmt.Errorf("ошибка получения вебхуков: %w", err)
+			}
+			subscribers[event.Type] = webhooks
+		}
+
+		for _, webhook := range webhooks {
+			delivery, err := domain.NewWebhookDelivery(webhook, event)
+			if err != nil {
+				return err
+			}
+
+			err = e.deliveryRepo.Create(ctx, delivery)
+			if err != nil {
+				return fmt.Errorf("ошибка постановки доставки в очередь: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (e *Enqueuer) Close() error {
+	return nil
+}
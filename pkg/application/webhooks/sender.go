@@ -0,0 +1,38 @@
+package webhooks
+
+import (
+	"context"
+	"time"
+)
+
+// Request - одна попытка доставки
+type Request struct {
+	URL        string
+	Secret     string
+	DeliveryID string
+	EventID    string
+	EventType  string
+	Body       []byte
+}
+
+// Sender отправляет доставку получателю. statusCode - код ответа, 0 если
+// ответа нет. Ошибка означает, что доставку нужно повторить
+type Sender interface {
+	Send(ctx context.Context, req Request) (statusCode int, err error)
+}
+
+// Backoff возвращает задержку перед повтором после attempt неудачных попыток:
+// base, 2*base, 4*base и так далее, но не больше max
+func Backoff(attempt int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	if delay > max {
+		return max
+	}
+	return delay
+}
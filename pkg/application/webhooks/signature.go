@@ -0,0 +1,38 @@
+// Package webhooks ставит события в очередь доставки вебхукам и описывает
+// формат доставки: заголовки и подпись, которую проверяет получатель
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// заголовки доставки
+const (
+	HeaderEventType = "X-PVZ-Event"
+	HeaderEventID   = "X-PVZ-Event-Id"
+	HeaderDelivery  = "X-PVZ-Delivery"
+	HeaderTimestamp = "X-PVZ-Timestamp"
+	HeaderSignature = "X-PVZ-Signature"
+)
+
+// signaturePrefix указывает алгоритм подписи
+const signaturePrefix = "sha256="
+
+// Sign подписывает тело доставки: HMAC-SHA256 от "<timestamp>.<body>".
+// Метка времени входит в подпись, чтобы перехваченный запрос нельзя было
+// повторить позже
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify проверяет подпись доставки. Сравнение выполняется за постоянное время
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
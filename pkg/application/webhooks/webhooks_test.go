@@ -0,0 +1,83 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/tests"
+)
+
+func TestSign_Verify(t *testing.T) {
+	body := []byte(`{"id":"event-1"}`)
+	signature := Sign("secret", 1700000000, body)
+
+	if !Verify("secret", 1700000000, body, signature) {
+		t.Error("Подпись должна проходить проверку")
+	}
+	if Verify("other", 1700000000, body, signature) {
+		t.Error("Подпись другим секретом не должна проходить проверку")
+	}
+	if Verify("secret", 1700000001, body, signature) {
+		t.Error("Подпись с другой меткой времени не должна проходить проверку")
+	}
+	if Verify("secret", 1700000000, []byte(`{"id":"event-2"}`), signature) {
+		t.Error("Подпись измененного тела не должна проходить проверку")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	cases := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{4, 8 * time.Minute},
+		{10, time.Hour},
+	}
+
+	for _, tt := range cases {
+		if got := Backoff(tt.attempt, time.Minute, time.Hour); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, ожидалось %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestEnqueuer_CreatesDeliveriesForSubscribers(t *testing.T) {
+	ctx := context.Background()
+	webhookRepo := tests.NewMockWebhookRepository()
+	deliveryRepo := tests.NewMockWebhookDeliveryRepository()
+
+	closed, _ := domain.NewWebhook("https://a.example/hooks", []string{domain.EventReceptionClosed}, "0123456789abcdef", "")
+	all, _ := domain.NewWebhook("https://b.example/hooks", domain.EventTypes, "0123456789abcdef", "")
+	webhookRepo.Create(ctx, closed)
+	webhookRepo.Create(ctx, all)
+
+	pvzCreated := &domain.Event{ID: "event-1", Type: domain.EventPVZCreated}
+	receptionClosed := &domain.Event{ID: "event-2", Type: domain.EventReceptionClosed}
+	enqueuer := NewEnqueuer(webhookRepo, deliveryRepo)
+
+	err := enqueuer.Publish(ctx, []*domain.Event{pvzCreated, receptionClosed})
+	if err != nil {
+		t.Fatalf("Ожидалась постановка без ошибок, получено: %v", err)
+	}
+	// повторная публикация той же пачки не создает новых доставок
+	err = enqueuer.Publish(ctx, []*domain.Event{pvzCreated, receptionClosed})
+	if err != nil {
+		t.Fatalf("Ожидалась постановка без ошибок, получено: %v", err)
+	}
+
+	if len(deliveryRepo.Deliveries) != 3 {
+		t.Fatalf("Ожидалось 3 доставки, получено %d", len(deliveryRepo.Deliveries))
+	}
+	for _, delivery := range deliveryRepo.Deliveries {
+		if delivery.WebhookID == closed.ID && delivery.EventType != domain.EventReceptionClosed {
+			t.Errorf("Вебхук получил событие без подписки: %s", delivery.EventType)
+		}
+		if delivery.Status != domain.DeliveryPending {
+			t.Errorf("Новая доставка должна ожидать отправки, получено %s", delivery.Status)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// API-ключ сервисной интеграции
+type APIKey struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	CreatedBy  string     `json:"createdBy"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+func NewAPIKey(name, prefix, keyHash string, scopes []string, createdBy string, expiresAt *time.Time) (*APIKey, error) {
+	if name == "" {
+		return nil, errors.New("не указано название ключа")
+	}
+
+	if keyHash == "" {
+		return nil, errors.New("хэш ключа не может быть пустым")
+	}
+
+	if len(scopes) == 0 {
+		return nil, errors.New("ключ должен иметь хотя бы одно право")
+	}
+
+	now := time.Now()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, errors.New("срок действия ключа должен быть в будущем")
+	}
+
+	return &APIKey{
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   keyHash,
+		Scopes:    scopes,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// check действует ли ключ на указанный момент
+func (k *APIKey) IsActive(at time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	if k.ExpiresAt != nil && !k.ExpiresAt.After(at) {
+		return false
+	}
+	return true
+}
+
+// отзыв ключа
+func (k *APIKey) Revoke() error {
+	if k.RevokedAt != nil {
+		return errors.New("ключ уже отозван")
+	}
+	now := time.Now()
+	k.RevokedAt = &now
+	return nil
+}
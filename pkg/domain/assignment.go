@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// закрепление сотрудника за ПВЗ. Какие роли можно закреплять, решает политика прав
+type Assignment struct {
+	UserID     string    `json:"userId"`
+	PVZID      string    `json:"pvzId"`
+	AssignedAt time.Time `json:"assignedAt"`
+}
+
+func NewAssignment(user User, pvzID string) (*Assignment, error) {
+	if user.ID == "" {
+		return nil, errors.New("не указан пользователь для закрепления")
+	}
+
+	if pvzID == "" {
+		return nil, errors.New("не указан ПВЗ для закрепления")
+	}
+
+	return &Assignment{
+		UserID:     user.ID,
+		PVZID:      pvzID,
+		AssignedAt: time.Now(),
+	}, nil
+}
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// действия, записываемые в журнал аудита
+const (
+	AuditActionPVZCreate        = "pvz.create"
+	AuditActionReceptionCreate  = "reception.create"
+	AuditActionReceptionClose   = "reception.close"
+	AuditActionReceptionStale   = "reception.stale"
+	AuditActionProductAdd       = "product.add"
+	AuditActionProductRemove    = "product.remove"
+	AuditActionAssignmentCreate = "assignment.create"
+	AuditActionAssignmentDelete = "assignment.delete"
+	AuditActionAPIKeyCreate     = "api_key.create"
+	AuditActionAPIKeyRevoke     = "api_key.revoke"
+	AuditActionUserRegister     = "user.register"
+	AuditActionTwoFactorEnable  = "two_factor.enable"
+	AuditActionWebhookCreate    = "webhook.create"
+	AuditActionWebhookDelete    = "webhook.delete"
+	AuditActionWebhookRedeliver = "webhook.redeliver"
+)
+
+// типы сущностей журнала аудита
+const (
+	AuditEntityPVZ        = "pvz"
+	AuditEntityReception  = "reception"
+	AuditEntityProduct    = "product"
+	AuditEntityAssignment = "assignment"
+	AuditEntityAPIKey     = "api_key"
+	AuditEntityUser       = "user"
+	AuditEntityTwoFactor  = "two_factor"
+	AuditEntityWebhook    = "webhook"
+	AuditEntityDelivery   = "webhook_delivery"
+)
+
+// роли инициаторов, не являющихся пользователями
+const (
+	AuditActorService = "service" // внешняя система с API-ключом
+	AuditActorSystem  = "system"  // внутренний вызов без пользователя
+)
+
+// Запись журнала аудита: кто, что и с какой сущностью сделал.
+// Before и After - JSON снимки сущности до и после изменения
+type AuditEntry struct {
+	ID         string          `json:"id"`
+	ActorID    string          `json:"actorId,omitempty"`
+	ActorRole  string          `json:"actorRole"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entityType"`
+	EntityID   string          `json:"entityId"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
+
+// NewAuditEntry создает запись аудита. before и after сериализуются в JSON;
+// nil означает отсутствие сущности (до создания или после удаления)
+func NewAuditEntry(actorID, actorRole, action, entityType, entityID string, before, after any) (*AuditEntry, error) {
+	if action == "" {
+		return nil, errors.New("не указано действие")
+	}
+
+	if entityType == "" || entityID == "" {
+		return nil, errors.New("не указана сущность")
+	}
+
+	beforeJSON, err := snapshot(before)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сериализации состояния до изменения: %w", err)
+	}
+
+	afterJSON, err := snapshot(after)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сериализации состояния после изменения: %w", err)
+	}
+
+	return &AuditEntry{
+		ActorID:    actorID,
+		ActorRole:  actorRole,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     beforeJSON,
+		After:      afterJSON,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+func snapshot(value any) (json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+	return json.Marshal(value)
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewAuditEntry_Snapshots(t *testing.T) {
+	before := &Reception{ID: "reception-1", PVZID: "pvz-1", Status: ReceptionStatusInProgress}
+	after := &Reception{ID: "reception-1", PVZID: "pvz-1", Status: ReceptionStatusClosed}
+
+	entry, err := NewAuditEntry("user-1", string(ModeratorRole), AuditActionReceptionClose, AuditEntityReception, "reception-1", before, after)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if entry.CreatedAt.IsZero() {
+		t.Error("Expected timestamp to be set")
+	}
+
+	var snapshot map[string]any
+	if err := json.Unmarshal(entry.After, &snapshot); err != nil {
+		t.Fatalf("Expected valid JSON snapshot, got: %v", err)
+	}
+	if snapshot["status"] != ReceptionStatusClosed {
+		t.Errorf("Expected after status %s, got %v", ReceptionStatusClosed, snapshot["status"])
+	}
+}
+
+func TestNewAuditEntry_NilSnapshot(t *testing.T) {
+	entry, err := NewAuditEntry("", AuditActorSystem, AuditActionPVZCreate, AuditEntityPVZ, "pvz-1", nil, &PVZ{ID: "pvz-1"})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if entry.Before != nil {
+		t.Errorf("Expected empty before snapshot for created entity, got %s", entry.Before)
+	}
+}
+
+func TestNewAuditEntry_Invalid(t *testing.T) {
+	_, err := NewAuditEntry("user-1", string(ModeratorRole), "", AuditEntityPVZ, "pvz-1", nil, nil)
+	if err == nil {
+		t.Error("Expected error for empty action")
+	}
+
+	_, err = NewAuditEntry("user-1", string(ModeratorRole), AuditActionPVZCreate, AuditEntityPVZ, "", nil, nil)
+	if err == nil {
+		t.Error("Expected error for empty entity ID")
+	}
+}
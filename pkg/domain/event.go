@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// типы доменных событий для внешних систем
+const (
+	EventPVZCreated       = "pvz.created"
+	EventReceptionCreated = "reception.created"
+	EventReceptionClosed  = "reception.closed"
+	EventProductAdded     = "product.added"
+	EventProductRemoved   = "product.removed"
+)
+
+// EventTypes - все типы событий, на которые можно подписаться
+var EventTypes = []string{
+	EventPVZCreated,
+	EventReceptionCreated,
+	EventReceptionClosed,
+	EventProductAdded,
+	EventProductRemoved,
+}
+
+// IsEventType проверяет, что тип события известен
+func IsEventType(eventType string) bool {
+	for _, known := range EventTypes {
+		if known == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Event - доменное событие. Записывается в outbox в той же транзакции, что и
+// изменение, и публикуется позже, поэтому потребители получают событие, только
+// если изменение сохранено, и могут получить его повторно. ID позволяет
+// отбрасывать повторы
+type Event struct {
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregateType"`
+	AggregateID   string          `json:"aggregateId"`
+	Payload       json.RawMessage `json:"payload"`
+	OccurredAt    time.Time       `json:"occurredAt"`
+}
+
+// NewEvent создает событие. payload - состояние сущности после изменения,
+// сериализуется в JSON
+func NewEvent(eventType, aggregateType, aggregateID string, payload any) (*Event, error) {
+	if eventType == "" {
+		return nil, errors.New("не указан тип события")
+	}
+
+	if aggregateType == "" || aggregateID == "" {
+		return nil, errors.New("не указана сущность")
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сериализации события: %w", err)
+	}
+
+	return &Event{
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       data,
+		OccurredAt:    time.Now(),
+	}, nil
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewEvent_Payload(t *testing.T) {
+	reception := &Reception{ID: "reception-1", PVZID: "pvz-1", Status: ReceptionStatusClosed, ClosedBy: ReceptionClosedBySystem}
+
+	event, err := NewEvent(EventReceptionClosed, AuditEntityReception, reception.ID, reception)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if event.OccurredAt.IsZero() {
+		t.Error("Expected timestamp to be set")
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		t.Fatalf("Expected valid JSON payload, got: %v", err)
+	}
+	if payload["pvzId"] != "pvz-1" || payload["closedBy"] != ReceptionClosedBySystem {
+		t.Errorf("Unexpected payload: %s", event.Payload)
+	}
+}
+
+func TestNewEvent_RequiresAggregate(t *testing.T) {
+	_, err := NewEvent(EventPVZCreated, AuditEntityPVZ, "", &PVZ{})
+	if err == nil {
+		t.Error("Expected error for event without aggregate ID")
+	}
+}
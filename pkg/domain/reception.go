@@ -11,6 +11,12 @@ const (
 	ReceptionStatusClosed     = "close"
 )
 
+// Кто закрыл приемку
+const (
+	ReceptionClosedByUser   = "user"
+	ReceptionClosedBySystem = "system"
+)
+
 //  приемка товаров
 type Reception struct {
 	ID       string    `json:"id"`
@@ -18,6 +24,13 @@ type Reception struct {
 	PVZID    string    `json:"pvzId"`
 	Status   string    `json:"status"`
 	Products []Product `json:"-"` // Товары, связанные с приемкой (не вклчаются в JSON напрямую)
+
+	ClosedAt    *time.Time `json:"closedAt,omitempty"`
+	ClosedBy    string     `json:"closedBy,omitempty"`    // user или system
+	CloseReason string     `json:"closeReason,omitempty"` // причина закрытия системой
+
+	// StaleSince - когда приемка отмечена зависшей, если ее не закрыли автоматически
+	StaleSince *time.Time `json:"staleSince,omitempty"`
 }
 
 func NewReception(pvzID string) *Reception {
@@ -34,10 +47,38 @@ func (r *Reception) Close() error {
 	if r.Status == ReceptionStatusClosed {
 		return errors.New("приемка уже закрыта")
 	}
+	now := time.Now()
 	r.Status = ReceptionStatusClosed
+	r.ClosedAt = &now
+	r.ClosedBy = ReceptionClosedByUser
+	return nil
+}
+
+// закрытие приемки системой с указанием причины
+func (r *Reception) CloseBySystem(reason string) error {
+	if reason == "" {
+		return errors.New("не указана причина закрытия приемки")
+	}
+
+	err := r.Close()
+	if err != nil {
+		return err
+	}
+	r.ClosedBy = ReceptionClosedBySystem
+	r.CloseReason = reason
 	return nil
 }
 
+// отметка зависшей приемки без закрытия. Возвращает false, если приемка
+// закрыта или уже отмечена
+func (r *Reception) MarkStale(at time.Time) bool {
+	if !r.IsActive() || r.StaleSince != nil {
+		return false
+	}
+	r.StaleSince = &at
+	return true
+}
+
 // check активна ли приемка
 func (r *Reception) IsActive() bool {
 	return r.Status == ReceptionStatusInProgress
@@ -130,3 +130,50 @@ func TestReception_RemoveLastProduct_LIFO(t *testing.T) {
 		t.Error("Expected error when removing product from closed reception, got nil")
 	}
 }
+
+func TestReception_CloseBySystem(t *testing.T) {
+	reception := NewReception("pvz-123")
+
+	err := reception.CloseBySystem("")
+	if err == nil {
+		t.Error("Expected error when closing without reason, got nil")
+	}
+	if !reception.IsActive() {
+		t.Fatal("Expected reception to stay active after failed close")
+	}
+
+	err = reception.CloseBySystem("открыта дольше 24h")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if reception.Status != ReceptionStatusClosed || reception.ClosedAt == nil {
+		t.Errorf("Expected reception to be closed with time, got %s", reception.Status)
+	}
+	if reception.ClosedBy != ReceptionClosedBySystem || reception.CloseReason != "открыта дольше 24h" {
+		t.Errorf("Expected system close with reason, got %q %q", reception.ClosedBy, reception.CloseReason)
+	}
+
+	if reception.MarkStale(time.Now()) {
+		t.Error("Expected closed reception not to be marked stale")
+	}
+}
+
+func TestReception_MarkStale(t *testing.T) {
+	reception := NewReception("pvz-123")
+	at := time.Now()
+
+	if !reception.MarkStale(at) {
+		t.Fatal("Expected active reception to be marked stale")
+	}
+	if reception.MarkStale(at.Add(time.Hour)) {
+		t.Error("Expected second mark to be ignored")
+	}
+	if !reception.StaleSince.Equal(at) {
+		t.Errorf("Expected stale since %v, got %v", at, reception.StaleSince)
+	}
+
+	err := reception.Close()
+	if err != nil || reception.ClosedBy != ReceptionClosedByUser {
+		t.Errorf("Expected user close, got %q, err %v", reception.ClosedBy, err)
+	}
+}
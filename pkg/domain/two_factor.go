@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Настройки двухфакторной аутентификации пользователя
+type TwoFactor struct {
+	UserID             string     `json:"userId"`
+	Secret             string     `json:"-"`
+	EnabledAt          *time.Time `json:"enabledAt,omitempty"`
+	LastUsedStep       int64      `json:"-"`
+	RecoveryCodeHashes []string   `json:"-"`
+	CreatedAt          time.Time  `json:"createdAt"`
+}
+
+func NewTwoFactor(userID, secret string) (*TwoFactor, error) {
+	if userID == "" {
+		return nil, errors.New("не указан пользователь")
+	}
+
+	if secret == "" {
+		return nil, errors.New("секрет не может быть пустым")
+	}
+
+	return &TwoFactor{
+		UserID:    userID,
+		Secret:    secret,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// check подтверждена ли привязка
+func (t *TwoFactor) IsEnabled() bool {
+	return t.EnabledAt != nil
+}
+
+// включение после проверки первого кода
+func (t *TwoFactor) Enable(recoveryCodeHashes []string) error {
+	if t.IsEnabled() {
+		return errors.New("двухфакторная аутентификация уже включена")
+	}
+	now := time.Now()
+	t.EnabledAt = &now
+	t.RecoveryCodeHashes = recoveryCodeHashes
+	return nil
+}
+
+// фиксация принятого кода; коды из уже использованных интервалов отклоняются
+func (t *TwoFactor) UseStep(step int64) error {
+	if step <= t.LastUsedStep {
+		return errors.New("код уже был использован")
+	}
+	t.LastUsedStep = step
+	return nil
+}
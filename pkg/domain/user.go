@@ -15,6 +15,12 @@ const (
 	ModeratorRole UserRole = "moderator"
 )
 
+// допустимые роли пользователей. Дополняется ролями из конфигурации при старте сервиса
+var ValidRoles = map[UserRole]bool{
+	EmployeeRole:  true,
+	ModeratorRole: true,
+}
+
 type User struct {
 	ID           string    `json:"id"`
 	Email        string    `json:"email"`
@@ -33,7 +39,7 @@ func NewUser(email string, passwordHash string, role UserRole) (User, error) {
 		return User{}, errors.New("password hash cannot be empty")
 	}
 
-	if role != EmployeeRole && role != ModeratorRole {
+	if !ValidRoles[role] {
 		return User{}, errors.New("invalid role")
 	}
 
@@ -0,0 +1,189 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// минимальная длина секрета подписи вебхука
+const MinWebhookSecretLength = 16
+
+// Подписка внешней системы на события. Доставки подписываются секретом
+type Webhook struct {
+	ID         string    `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"eventTypes"`
+	Secret     string    `json:"-"`
+	CreatedBy  string    `json:"createdBy"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func NewWebhook(rawURL string, eventTypes []string, secret, createdBy string) (*Webhook, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, errors.New("адрес вебхука должен быть абсолютным http или https URL")
+	}
+	if isInternalHost(target.Hostname()) {
+		return nil, errors.New("адрес вебхука не может указывать на внутреннюю сеть")
+	}
+
+	if len(eventTypes) == 0 {
+		return nil, errors.New("вебхук должен быть подписан хотя бы на один тип событий")
+	}
+
+	seen := make(map[string]bool, len(eventTypes))
+	types := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		if !IsEventType(eventType) {
+			return nil, fmt.Errorf("неизвестный тип события: %s", eventType)
+		}
+		if !seen[eventType] {
+			seen[eventType] = true
+			types = append(types, eventType)
+		}
+	}
+
+	if len(secret) < MinWebhookSecretLength {
+		return nil, fmt.Errorf("секрет вебхука должен быть не короче %d символов", MinWebhookSecretLength)
+	}
+
+	return &Webhook{
+		URL:        rawURL,
+		EventTypes: types,
+		Secret:     secret,
+		CreatedBy:  createdBy,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// internalPrefixes - внутренние диапазоны, которые не покрывают методы netip.Addr
+var internalPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "эта сеть": Linux направляет ее на локальный хост
+	netip.MustParsePrefix("100.64.0.0/10"), // carrier-grade NAT
+	netip.MustParsePrefix("198.18.0.0/15"), // тестирование производительности сетей
+}
+
+// IsInternalAddress сообщает, что адрес относится к внутренней сети: loopback,
+// частные сети, link-local (в том числе 169.254.169.254 - метаданные облака),
+// multicast, неуказанный адрес или диапазоны internalPrefixes. Вебхуки на
+// такие адреса не отправляются
+func IsInternalAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() || addr.IsUnspecified() {
+		return true
+	}
+
+	for _, prefix := range internalPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// isInternalHost проверяет хост из URL без обращения к DNS: IP-адрес или
+// localhost. Имя, которое разрешается во внутренний адрес, отклоняется при
+// отправке
+func isInternalHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+
+	addr, err := netip.ParseAddr(host)
+	return err == nil && IsInternalAddress(addr)
+}
+
+// Subscribed проверяет, подписан ли вебхук на тип события
+func (w *Webhook) Subscribed(eventType string) bool {
+	for _, subscribed := range w.EventTypes {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// статусы доставки вебхука
+const (
+	DeliveryPending   = "pending"   // ожидает отправки или повтора
+	DeliveryDelivered = "delivered" // получатель ответил 2xx
+	DeliveryDead      = "dead"      // попытки исчерпаны, нужна повторная отправка вручную
+)
+
+// Доставка события одному вебхуку. Payload отправляется без изменений при
+// каждой попытке, поэтому получатель может отбрасывать повторы по EventID
+type WebhookDelivery struct {
+	ID             string          `json:"id"`
+	WebhookID      string          `json:"webhookId"`
+	EventID        string          `json:"eventId"`
+	EventType      string          `json:"eventType"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"nextAttemptAt"`
+	LastStatusCode int             `json:"lastStatusCode,omitempty"`
+	LastError      string          `json:"lastError,omitempty"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	DeliveredAt    *time.Time      `json:"deliveredAt,omitempty"`
+}
+
+func NewWebhookDelivery(webhook *Webhook, event *Event) (*WebhookDelivery, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сериализации события: %w", err)
+	}
+
+	now := time.Now()
+	return &WebhookDelivery{
+		WebhookID:     webhook.ID,
+		EventID:       event.ID,
+		EventType:     event.Type,
+		Payload:       payload,
+		Status:        DeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}, nil
+}
+
+// MarkDelivered отмечает успешную попытку
+func (d *WebhookDelivery) MarkDelivered(statusCode int, at time.Time) {
+	d.Attempts++
+	d.Status = DeliveryDelivered
+	d.LastStatusCode = statusCode
+	d.LastError = ""
+	d.DeliveredAt = &at
+}
+
+// MarkFailed отмечает неудачную попытку. Доставка повторяется в nextAttemptAt,
+// а после maxAttempts попыток попадает в dead
+func (d *WebhookDelivery) MarkFailed(statusCode int, reason string, nextAttemptAt time.Time, maxAttempts int) {
+	d.Attempts++
+	d.LastStatusCode = statusCode
+	d.LastError = reason
+	if d.Attempts >= maxAttempts {
+		d.Status = DeliveryDead
+		return
+	}
+	d.NextAttemptAt = nextAttemptAt
+}
+
+// Redeliver ставит доставку в очередь заново с полным числом попыток
+func (d *WebhookDelivery) Redeliver(at time.Time) error {
+	if d.Status == DeliveryPending {
+		return errors.New("доставка уже ожидает отправки")
+	}
+
+	d.Status = DeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = at
+	d.DeliveredAt = nil
+	return nil
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+const testWebhookSecret = "0123456789abcdef"
+
+func TestNewWebhook_Validation(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		eventTypes []string
+		secret     string
+	}{
+		{"относительный адрес", "/hooks", []string{EventPVZCreated}, testWebhookSecret},
+		{"неподдерживаемая схема", "ftp://partner.example/hooks", []string{EventPVZCreated}, testWebhookSecret},
+		{"без событий", "https://partner.example/hooks", nil, testWebhookSecret},
+		{"неизвестное событие", "https://partner.example/hooks", []string{"pvz.deleted"}, testWebhookSecret},
+		{"короткий секрет", "https://partner.example/hooks", []string{EventPVZCreated}, "short"},
+		{"localhost", "http://localhost:8080/hooks", []string{EventPVZCreated}, testWebhookSecret},
+		{"loopback", "http://127.0.0.1/hooks", []string{EventPVZCreated}, testWebhookSecret},
+		{"частная сеть", "http://10.1.2.3/hooks", []string{EventPVZCreated}, testWebhookSecret},
+		{"метаданные облака", "http://169.254.169.254/latest/meta-data", []string{EventPVZCreated}, testWebhookSecret},
+		{"IPv6 loopback", "http://[::1]/hooks", []string{EventPVZCreated}, testWebhookSecret},
+		{"IPv4 в IPv6", "http://[::ffff:192.168.0.1]/hooks", []string{EventPVZCreated}, testWebhookSecret},
+		{"сеть 0.0.0.0/8", "http://0.1.2.3/hooks", []string{EventPVZCreated}, testWebhookSecret},
+		{"carrier-grade NAT", "http://100.64.1.1/hooks", []string{EventPVZCreated}, testWebhookSecret},
+		{"сеть тестирования", "http://198.19.255.1/hooks", []string{EventPVZCreated}, testWebhookSecret},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewWebhook(tt.url, tt.eventTypes, tt.secret, "")
+			if err == nil {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+
+	webhook, err := NewWebhook("https://partner.example/hooks",
+		[]string{EventReceptionClosed, EventPVZCreated, EventReceptionClosed}, testWebhookSecret, "user-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(webhook.EventTypes) != 2 {
+		t.Errorf("Expected duplicate event types to be removed, got: %v", webhook.EventTypes)
+	}
+	if !webhook.Subscribed(EventPVZCreated) || webhook.Subscribed(EventProductAdded) {
+		t.Errorf("Unexpected subscription: %v", webhook.EventTypes)
+	}
+}
+
+func TestWebhookDelivery_RetriesAndDeadLetter(t *testing.T) {
+	webhook := &Webhook{ID: "webhook-1"}
+	event := &Event{ID: "event-1", Type: EventPVZCreated}
+	delivery, err := NewWebhookDelivery(webhook, event)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	next := time.Now().Add(time.Minute)
+	delivery.MarkFailed(500, "internal error", next, 2)
+	if delivery.Status != DeliveryPending || delivery.Attempts != 1 || !delivery.NextAttemptAt.Equal(next) {
+		t.Errorf("Expected retry to be scheduled, got: %+v", delivery)
+	}
+
+	delivery.MarkFailed(0, "timeout", next.Add(time.Minute), 2)
+	if delivery.Status != DeliveryDead {
+		t.Errorf("Expected delivery to be dead after max attempts, got: %s", delivery.Status)
+	}
+
+	err = delivery.Redeliver(time.Now())
+	if err != nil || delivery.Status != DeliveryPending || delivery.Attempts != 0 {
+		t.Errorf("Expected delivery to be queued again, got: %+v, %v", delivery, err)
+	}
+	if delivery.Redeliver(time.Now()) == nil {
+		t.Error("Expected error when redelivering pending delivery")
+	}
+
+	delivery.MarkDelivered(204, time.Now())
+	if delivery.Status != DeliveryDelivered || delivery.DeliveredAt == nil || delivery.LastError != "" {
+		t.Errorf("Expected delivery to be delivered, got: %+v", delivery)
+	}
+}
@@ -0,0 +1,66 @@
+// Package buildinfo содержит сведения о сборке приложения
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Значения задаются при сборке:
+//
+//	go build -ldflags "-X github.com/dkumancev/avito-pvz/pkg/infrastructure/buildinfo.Version=v1.2.0
+//	  -X github.com/dkumancev/avito-pvz/pkg/infrastructure/buildinfo.Commit=$(git rev-parse HEAD)"
+//
+// Если они не заданы, коммит и время берутся из сведений VCS, которые go build
+// добавляет в бинарник
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+// Info - сведения о сборке
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"buildTime"`
+	GoVersion string `json:"goVersion"`
+	Modified  bool   `json:"modified"`
+}
+
+// Get возвращает сведения о текущей сборке
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	// при go install модуль получает версию из тега
+	if info.Version == "dev" && build.Main.Version != "" && build.Main.Version != "(devel)" {
+		info.Version = build.Main.Version
+	}
+
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if info.Commit == "" {
+				info.Commit = setting.Value
+			}
+		case "vcs.time":
+			if info.BuildTime == "" {
+				info.BuildTime = setting.Value
+			}
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+
+	return info
+}
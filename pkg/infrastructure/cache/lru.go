@@ -0,0 +1,110 @@
+// Package cache содержит кэш в памяти процесса и декораторы репозиториев,
+// читающие через него
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time
+}
+
+// LRU - кэш ограниченного размера с временем жизни записей. При
+// переполнении вытесняется запись, к которой дольше всего не обращались
+type LRU[K comparable, V any] struct {
+	size int
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List // от недавно использованных к давно
+	entries map[K]*list.Element
+}
+
+func NewLRU[K comparable, V any](size int, ttl time.Duration) *LRU[K, V] {
+	return &LRU[K, V]{
+		size:    size,
+		ttl:     ttl,
+		now:     time.Now,
+		order:   list.New(),
+		entries: make(map[K]*list.Element),
+	}
+}
+
+// Get возвращает значение, если оно есть и не устарело
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+	elem, ok := c.entries[key]
+	if !ok {
+		return zero, false
+	}
+
+	e := elem.Value.(*entry[K, V])
+	if !c.now().Before(e.expires) {
+		c.remove(elem)
+		return zero, false
+	}
+
+	c.order.MoveToFront(elem)
+	return e.value, true
+}
+
+// Set сохраняет значение и вытесняет давно использованные записи сверх размера
+func (c *LRU[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := c.now().Add(c.ttl)
+	if elem, ok := c.entries[key]; ok {
+		e := elem.Value.(*entry[K, V])
+		e.value = value
+		e.expires = expires
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&entry[K, V]{key: key, value: value, expires: expires})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+// Delete удаляет запись
+func (c *LRU[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+}
+
+// Purge удаляет все записи
+func (c *LRU[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	c.entries = make(map[K]*list.Element)
+}
+
+// Len возвращает количество записей, включая устаревшие, но еще не удаленные
+func (c *LRU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *LRU[K, V]) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*entry[K, V]).key)
+}
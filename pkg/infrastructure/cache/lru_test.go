@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRU[string, int](2, time.Minute)
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a") // b становится самой давней
+	c.Set("c", 3)
+
+	_, ok := c.Get("b")
+	assert.False(t, ok, "b должна быть вытеснена")
+	value, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestLRU_ExpiresEntries(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	c := NewLRU[string, int](10, time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Set("a", 1)
+	now = now.Add(59 * time.Second)
+	_, ok := c.Get("a")
+	assert.True(t, ok)
+
+	now = now.Add(time.Second)
+	_, ok = c.Get("a")
+	assert.False(t, ok, "запись устарела")
+	assert.Equal(t, 0, c.Len(), "устаревшая запись удаляется при чтении")
+
+	c.Set("b", 2)
+	c.Purge()
+	_, ok = c.Get("b")
+	assert.False(t, ok)
+}
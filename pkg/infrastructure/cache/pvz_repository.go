@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+// PVZRepository читает ПВЗ по идентификатору через кэш. ПВЗ не меняются
+// после создания, поэтому кэшируется только GetByID; список зависит от
+// приемок и всегда читается из БД. Отсутствующие ПВЗ не кэшируются: ПВЗ,
+// созданный на другой реплике, сразу становится доступен. Инвалидация между
+// репликами не нужна: запись в кэше может только устареть по TTL
+type PVZRepository struct {
+	next  repositories.PVZRepository
+	cache *LRU[string, domain.PVZ]
+}
+
+var _ repositories.PVZRepository = (*PVZRepository)(nil)
+
+func NewPVZRepository(next repositories.PVZRepository, size int, ttl time.Duration) *PVZRepository {
+	return &PVZRepository{
+		next:  next,
+		cache: NewLRU[string, domain.PVZ](size, ttl),
+	}
+}
+
+// Create не заполняет кэш: транзакция, в которой создан ПВЗ, еще может
+// откатиться
+func (r *PVZRepository) Create(ctx context.Context, pvz *domain.PVZ) (*domain.PVZ, error) {
+	created, err := r.next.Create(ctx, pvz)
+	if err != nil {
+		return nil, err
+	}
+
+	r.cache.Delete(created.ID)
+	return created, nil
+}
+
+// GetByID возвращает копию ПВЗ из кэша или читает его из БД
+func (r *PVZRepository) GetByID(ctx context.Context, id string) (*domain.PVZ, error) {
+	if cached, ok := r.cache.Get(id); ok {
+		return &cached, nil
+	}
+
+	pvz, err := r.next.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.cache.Set(id, *pvz)
+	return pvz, nil
+}
+
+func (r *PVZRepository) List(ctx context.Context, filter repositories.PVZFilter) ([]*domain.PVZ, error) {
+	return r.next.List(ctx, filter)
+}
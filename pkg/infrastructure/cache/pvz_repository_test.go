@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingPVZRepository считает обращения к БД
+type countingPVZRepository struct {
+	*tests.MockPVZRepository
+	gets int
+}
+
+func (r *countingPVZRepository) GetByID(ctx context.Context, id string) (*domain.PVZ, error) {
+	r.gets++
+	return r.MockPVZRepository.GetByID(ctx, id)
+}
+
+func TestPVZRepository_ReadThrough(t *testing.T) {
+	ctx := context.Background()
+	next := &countingPVZRepository{MockPVZRepository: tests.NewMockPVZRepository()}
+	next.Add(&domain.PVZ{ID: "pvz-1", City: "Москва"})
+	repo := NewPVZRepository(next, 10, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		pvz, err := repo.GetByID(ctx, "pvz-1")
+		require.NoError(t, err)
+		assert.Equal(t, "Москва", pvz.City)
+
+		// изменение возвращенного ПВЗ не портит кэш
+		pvz.City = "Казань"
+	}
+	assert.Equal(t, 1, next.gets, "повторные чтения идут из кэша")
+
+	expired := time.Now().Add(2 * time.Minute)
+	repo.cache.now = func() time.Time { return expired }
+	_, err := repo.GetByID(ctx, "pvz-1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, next.gets, "после истечения TTL ПВЗ читается из БД")
+}
+
+func TestPVZRepository_DoesNotCacheMisses(t *testing.T) {
+	ctx := context.Background()
+	next := &countingPVZRepository{MockPVZRepository: tests.NewMockPVZRepository()}
+	repo := NewPVZRepository(next, 10, time.Minute)
+
+	_, err := repo.GetByID(ctx, "pvz-1")
+	require.Error(t, err)
+
+	// ПВЗ создан, например, на другой реплике
+	next.Add(&domain.PVZ{ID: "pvz-1", City: "Москва"})
+	pvz, err := repo.GetByID(ctx, "pvz-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Москва", pvz.City)
+}
@@ -0,0 +1,143 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/segmentio/kafka-go"
+)
+
+func testEvents(t *testing.T) []*domain.Event {
+	t.Helper()
+	var events []*domain.Event
+	for i, id := range []string{"pvz-1", "pvz-2"} {
+		event, err := domain.NewEvent(domain.EventPVZCreated, domain.AuditEntityPVZ, id, map[string]string{"city": "Москва"})
+		if err != nil {
+			t.Fatalf("Ошибка создания события: %v", err)
+		}
+		event.ID = []string{"event-1", "event-2"}[i]
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestMemoryBus_DeliversToSubscribers(t *testing.T) {
+	bus := NewMemoryBus()
+	var first, second []string
+	bus.Subscribe(func(ctx context.Context, event *domain.Event) error {
+		first = append(first, event.ID)
+		return nil
+	})
+	bus.Subscribe(func(ctx context.Context, event *domain.Event) error {
+		second = append(second, event.ID)
+		return nil
+	})
+
+	err := bus.Publish(context.Background(), testEvents(t))
+	if err != nil {
+		t.Fatalf("Ожидалась публикация без ошибок, получено: %v", err)
+	}
+	if len(first) != 2 || len(second) != 2 || first[0] != "event-1" || first[1] != "event-2" {
+		t.Errorf("Подписчики должны получить события по порядку: %v, %v", first, second)
+	}
+
+	bus.Subscribe(func(ctx context.Context, event *domain.Event) error {
+		return errors.New("подписчик недоступен")
+	})
+	err = bus.Publish(context.Background(), testEvents(t))
+	if err == nil {
+		t.Error("Ошибка подписчика должна прерывать публикацию")
+	}
+}
+
+func TestFilePublisher_AppendsJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	for i := 0; i < 2; i++ {
+		publisher, err := NewFilePublisher(path)
+		if err != nil {
+			t.Fatalf("Ошибка открытия файла: %v", err)
+		}
+		err = publisher.Publish(context.Background(), testEvents(t))
+		if err != nil {
+			t.Fatalf("Ожидалась публикация без ошибок, получено: %v", err)
+		}
+		publisher.Close()
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Ошибка чтения файла: %v", err)
+	}
+	defer file.Close()
+
+	var ids []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event domain.Event
+		err = json.Unmarshal(scanner.Bytes(), &event)
+		if err != nil {
+			t.Fatalf("Строка не является событием: %q", scanner.Text())
+		}
+		ids = append(ids, event.ID)
+	}
+	if len(ids) != 4 || ids[0] != "event-1" || ids[3] != "event-2" {
+		t.Errorf("События должны дописываться в файл по порядку: %v", ids)
+	}
+}
+
+type fakeWriter struct {
+	msgs []kafka.Message
+	err  error
+}
+
+func (w *fakeWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.msgs = append(w.msgs, msgs...)
+	return nil
+}
+
+func (w *fakeWriter) Close() error { return nil }
+
+func TestKafkaPublisher_BuildsMessages(t *testing.T) {
+	writer := &fakeWriter{}
+	publisher := NewKafkaPublisherWithWriter(writer)
+
+	err := publisher.Publish(context.Background(), testEvents(t))
+	if err != nil {
+		t.Fatalf("Ожидалась публикация без ошибок, получено: %v", err)
+	}
+	if len(writer.msgs) != 2 {
+		t.Fatalf("Ожидалось 2 сообщения, получено %d", len(writer.msgs))
+	}
+
+	msg := writer.msgs[0]
+	if string(msg.Key) != "pvz-1" {
+		t.Errorf("Ключ сообщения должен быть идентификатором сущности, получено %q", msg.Key)
+	}
+	headers := map[string]string{}
+	for _, h := range msg.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+	if headers["event-id"] != "event-1" || headers["event-type"] != domain.EventPVZCreated {
+		t.Errorf("Неверные заголовки сообщения: %v", headers)
+	}
+	var event domain.Event
+	err = json.Unmarshal(msg.Value, &event)
+	if err != nil || event.ID != "event-1" || event.AggregateType != domain.AuditEntityPVZ {
+		t.Errorf("Тело сообщения должно быть событием: %s", msg.Value)
+	}
+
+	writer.err = errors.New("брокер недоступен")
+	err = publisher.Publish(context.Background(), testEvents(t))
+	if err == nil {
+		t.Error("Ожидалась ошибка отправки")
+	}
+}
@@ -0,0 +1,62 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+// FilePublisher дописывает события в файл, по одному JSON-объекту на строку
+type FilePublisher struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func NewFilePublisher(path string) (*FilePublisher, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия файла событий: %w", err)
+	}
+
+	return &FilePublisher{file: file}, nil
+}
+
+// Publish записывает пачку одной операцией и синхронизирует файл, чтобы
+// события, отмеченные опубликованными, не терялись при сбое
+func (p *FilePublisher) Publish(ctx context.Context, events []*domain.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	w := bufio.NewWriter(p.file)
+	enc := json.NewEncoder(w)
+	for _, event := range events {
+		err := enc.Encode(event)
+		if err != nil {
+			return fmt.Errorf("ошибка сериализации события: %w", err)
+		}
+	}
+
+	err := w.Flush()
+	if err != nil {
+		return fmt.Errorf("ошибка записи событий: %w", err)
+	}
+
+	err = p.file.Sync()
+	if err != nil {
+		return fmt.Errorf("ошибка записи событий: %w", err)
+	}
+
+	return nil
+}
+
+func (p *FilePublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.file.Close()
+}
@@ -0,0 +1,76 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/segmentio/kafka-go"
+)
+
+// MessageWriter - часть kafka.Writer, которой пользуется KafkaPublisher
+type MessageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// KafkaConfig - адреса брокеров и топик событий
+type KafkaConfig struct {
+	Brokers []string
+	Topic   string
+}
+
+// KafkaPublisher отправляет события в Kafka-совместимый брокер (Kafka,
+// Redpanda). Ключ сообщения - идентификатор сущности, поэтому события одной
+// сущности попадают в одну партицию и читаются по порядку
+type KafkaPublisher struct {
+	writer MessageWriter
+}
+
+func NewKafkaPublisher(cfg KafkaConfig) *KafkaPublisher {
+	return NewKafkaPublisherWithWriter(&kafka.Writer{
+		Addr:                   kafka.TCP(cfg.Brokers...),
+		Topic:                  cfg.Topic,
+		Balancer:               &kafka.Hash{},
+		RequiredAcks:           kafka.RequireAll,
+		AllowAutoTopicCreation: true,
+		BatchTimeout:           10 * time.Millisecond,
+	})
+}
+
+func NewKafkaPublisherWithWriter(writer MessageWriter) *KafkaPublisher {
+	return &KafkaPublisher{writer: writer}
+}
+
+func (p *KafkaPublisher) Publish(ctx context.Context, events []*domain.Event) error {
+	msgs := make([]kafka.Message, 0, len(events))
+	for _, event := range events {
+		value, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("ошибка сериализации события: %w", err)
+		}
+
+		msgs = append(msgs, kafka.Message{
+			Key:   []byte(event.AggregateID),
+			Value: value,
+			Headers: []kafka.Header{
+				{Key: "event-id", Value: []byte(event.ID)},
+				{Key: "event-type", Value: []byte(event.Type)},
+			},
+			Time: event.OccurredAt,
+		})
+	}
+
+	err := p.writer.WriteMessages(ctx, msgs...)
+	if err != nil {
+		return fmt.Errorf("ошибка отправки событий в брокер: %w", err)
+	}
+
+	return nil
+}
+
+func (p *KafkaPublisher) Close() error {
+	return p.writer.Close()
+}
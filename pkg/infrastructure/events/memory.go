@@ -0,0 +1,55 @@
+// Package events содержит реализации events.Publisher: шину в памяти процесса,
+// запись в JSONL-файл и продюсер Kafka-совместимого брокера
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+)
+
+// Handler обрабатывает опубликованное событие. Ошибка обработчика прерывает
+// публикацию, и пачка будет опубликована повторно
+type Handler func(ctx context.Context, event *domain.Event) error
+
+// MemoryBus передает события подписчикам внутри процесса. Подходит для
+// разработки и для подписчиков, работающих в том же процессе
+type MemoryBus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{}
+}
+
+// Subscribe добавляет обработчик всех событий
+func (b *MemoryBus) Subscribe(handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, handler)
+}
+
+func (b *MemoryBus) Publish(ctx context.Context, events []*domain.Event) error {
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	for _, event := range events {
+		for _, handler := range handlers {
+			err := handler(ctx, event)
+			if err != nil {
+				return fmt.Errorf("ошибка обработки события %s: %w", event.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (b *MemoryBus) Close() error {
+	return nil
+}
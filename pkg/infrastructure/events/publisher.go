@@ -0,0 +1,26 @@
+package events
+
+import (
+	"fmt"
+
+	"github.com/dkumancev/avito-pvz/config"
+	appevents "github.com/dkumancev/avito-pvz/pkg/application/events"
+)
+
+// NewPublisher создает публикатор событий, выбранный в OUTBOX_PUBLISHER
+func NewPublisher(cfg config.OutboxConfig) (appevents.Publisher, error) {
+	switch cfg.Publisher {
+	case config.OutboxPublisherMemory:
+		return NewMemoryBus(), nil
+	case config.OutboxPublisherFile:
+		publisher, err := NewFilePublisher(cfg.FilePath)
+		if err != nil {
+			return nil, err
+		}
+		return publisher, nil
+	case config.OutboxPublisherKafka:
+		return NewKafkaPublisher(KafkaConfig{Brokers: cfg.KafkaBrokers, Topic: cfg.KafkaTopic}), nil
+	default:
+		return nil, fmt.Errorf("неизвестный способ публикации событий: %s", cfg.Publisher)
+	}
+}
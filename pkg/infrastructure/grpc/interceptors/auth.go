@@ -0,0 +1,76 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// AuthUnaryInterceptor проверяет JWT токен из метаданных "authorization: Bearer <token>"
+// или API-ключ из метаданных "x-api-key" и сохраняет вызывающую сторону в контексте вызова
+func AuthUnaryInterceptor(tokens *auth.TokenParser, apiKeys auth.APIKeyAuthenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "отсутствует токен авторизации")
+		}
+
+		if keys := md.Get("x-api-key"); len(keys) > 0 && apiKeys != nil {
+			principal, err := apiKeys.Authenticate(ctx, keys[0])
+			if err != nil {
+				if !errors.Is(err, auth.ErrInvalidAPIKey) {
+					return nil, status.Error(codes.Internal, "ошибка проверки API-ключа")
+				}
+				return nil, status.Error(codes.Unauthenticated, "недействительный API-ключ")
+			}
+			return handler(auth.WithServicePrincipal(ctx, principal), req)
+		}
+
+		values := md.Get("authorization")
+		if len(values) == 0 {
+			return nil, status.Error(codes.Unauthenticated, "отсутствует токен авторизации")
+		}
+
+		parts := strings.Split(values[0], " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			return nil, status.Error(codes.Unauthenticated, "неверный формат токена авторизации")
+		}
+
+		ctx, err := tokens.Authenticate(ctx, parts[1])
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// PermissionUnaryInterceptor проверяет права пользователя или API-ключа для методов из methodPermissions.
+// Методы, отсутствующие в списке, требуют только аутентификации
+func PermissionUnaryInterceptor(policy *auth.Policy, methodPermissions map[string]auth.Permission) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		permission, ok := methodPermissions[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		err := auth.Authorize(ctx, policy, permission)
+		if errors.Is(err, auth.ErrUnauthenticated) {
+			return nil, status.Error(codes.Unauthenticated, "ошибка авторизации")
+		}
+		if errors.Is(err, auth.ErrTwoFactorRequired) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		if err != nil {
+			return nil, status.Error(codes.PermissionDenied, "недостаточно прав для выполнения операции")
+		}
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,55 @@
+package interceptors
+
+import (
+	"context"
+	"math"
+	"net"
+	"strconv"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/metrics"
+	"github.com/dkumancev/avito-pvz/pkg/application/ratelimit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// RateLimitUnaryInterceptor ограничивает частоту вызовов метода по пользователю
+// или API-ключу, поэтому ставится после AuthUnaryInterceptor. Ограничение для
+// метода задается по полному имени, например /pvz.v1.PVZService/GetPVZList.
+// При превышении возвращается ResourceExhausted и метаданные retry-after в секундах
+func RateLimitUnaryInterceptor(limiter *ratelimit.Limiter, m metrics.RateLimitMetrics) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		subject := ratelimit.SubjectFromContext(ctx, peerIP(ctx))
+
+		decision := limiter.Allow(info.FullMethod, subject)
+		if decision.Allowed {
+			return handler(ctx, req)
+		}
+
+		m.Throttled("grpc", info.FullMethod, string(subject.Kind))
+		retryAfter := int(math.Max(math.Ceil(decision.RetryAfter.Seconds()), 1))
+		grpc.SetHeader(ctx, metadata.Pairs(
+			"retry-after", strconv.Itoa(retryAfter),
+			"ratelimit-limit", strconv.Itoa(decision.Limit.Requests),
+			"ratelimit-remaining", strconv.Itoa(decision.Remaining),
+		))
+		return nil, status.Errorf(codes.ResourceExhausted,
+			"слишком много запросов, повторите через %d с", retryAfter)
+	}
+}
+
+// peerIP возвращает IP клиента из адреса соединения
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
@@ -0,0 +1,69 @@
+package interceptors
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/requestctx"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RequestIDMetadataKey - ключ метаданных с идентификатором запроса
+const RequestIDMetadataKey = "x-request-id"
+
+// RequestIDUnaryInterceptor берет идентификатор запроса из метаданных x-request-id
+// или генерирует новый, сохраняет его в контексте и возвращает клиенту
+// в заголовках ответа (в том числе при ошибке)
+func RequestIDUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		var requestID string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(RequestIDMetadataKey); len(values) > 0 {
+				requestID = values[0]
+			}
+		}
+		if !requestctx.IsValidID(requestID) {
+			requestID = requestctx.NewID()
+		}
+
+		ctx = requestctx.New(ctx, requestID)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadataKey, requestID))
+
+		return handler(ctx, req)
+	}
+}
+
+// LoggingUnaryInterceptor логирует завершение каждого вызова. Должен идти после
+// RequestIDUnaryInterceptor, чтобы записи содержали идентификатор запроса
+func LoggingUnaryInterceptor(log *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+
+		duration := time.Since(start)
+		code := status.Code(err)
+
+		level := slog.LevelInfo
+		switch code {
+		case codes.OK:
+		case codes.Internal, codes.Unknown, codes.Unavailable, codes.DataLoss:
+			level = slog.LevelError
+		default:
+			level = slog.LevelWarn
+		}
+
+		log.Log(ctx, level, "Завершен gRPC вызов",
+			"method", info.FullMethod,
+			"code", code.String(),
+			"duration", duration.String(),
+			"duration_ms", float64(duration.Microseconds())/1000.0,
+		)
+
+		return resp, err
+	}
+}
@@ -0,0 +1,69 @@
+package interceptors
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const tracerName = "github.com/dkumancev/avito-pvz/pkg/infrastructure/grpc"
+
+// metadataCarrier адаптер метаданных gRPC для W3C propagator
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// TracingUnaryInterceptor начинает серверный span вызова, продолжая трассу из метаданных traceparent.
+// Должен стоять первым в цепочке, чтобы span охватывал аутентификацию
+func TracingUnaryInterceptor() grpc.UnaryServerInterceptor {
+	tracer := otel.Tracer(tracerName)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md.Copy()))
+
+		ctx, span := tracer.Start(ctx, info.FullMethod,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("rpc.system", "grpc"),
+				attribute.String("rpc.method", info.FullMethod),
+			),
+		)
+		defer span.End()
+
+		resp, err := handler(ctx, req)
+
+		code := status.Code(err)
+		span.SetAttributes(attribute.String("rpc.grpc.status_code", code.String()))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, err.Error())
+		}
+
+		return resp, err
+	}
+}
@@ -1,27 +1,69 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	"net"
+	"strings"
 
 	"github.com/dkumancev/avito-pvz/pkg/application/services/pvz"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/grpc/pb"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/grpc/service"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 )
 
+// healthMethodPrefix методы grpc.health.v1 вызываются балансировщиками и
+// /readyz без учетных данных
+var healthMethodPrefix = "/" + healthpb.Health_ServiceDesc.ServiceName + "/"
+
 type GRPCServer struct {
 	server     *grpc.Server
+	health     *health.Server
 	pvzService pvz.Service
 	port       int
 }
 
-func NewGRPCServer(pvzService pvz.Service, port int) *GRPCServer {
-	return &GRPCServer{
+// NewGRPCServer создает сервер сразу, чтобы остановка по сигналу не зависела
+// от того, успел ли Start начать прием соединений. Вызовы health-сервиса
+// обходят interceptors: проверки не аутентифицируются и не расходуют лимиты
+func NewGRPCServer(pvzService pvz.Service, port int, interceptors ...grpc.UnaryServerInterceptor) *GRPCServer {
+	s := &GRPCServer{
+		server:     grpc.NewServer(grpc.ChainUnaryInterceptor(skipHealth(interceptors)...)),
+		health:     health.NewServer(),
 		pvzService: pvzService,
 		port:       port,
 	}
+
+	pvzServiceServer := service.NewPVZServiceServer(s.pvzService)
+	pb.RegisterPVZServiceServer(s.server, pvzServiceServer)
+
+	// стандартный сервис grpc.health.v1 для проверок готовности и балансировщиков
+	s.health.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	s.health.SetServingStatus("pvz.v1.PVZService", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(s.server, s.health)
+
+	reflection.Register(s.server)
+
+	return s
+}
+
+// skipHealth оборачивает interceptors так, что вызовы health-сервиса идут
+// сразу в обработчик
+func skipHealth(interceptors []grpc.UnaryServerInterceptor) []grpc.UnaryServerInterceptor {
+	wrapped := make([]grpc.UnaryServerInterceptor, 0, len(interceptors))
+	for _, interceptor := range interceptors {
+		interceptor := interceptor
+		wrapped = append(wrapped, func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if strings.HasPrefix(info.FullMethod, healthMethodPrefix) {
+				return handler(ctx, req)
+			}
+			return interceptor(ctx, req, info, handler)
+		})
+	}
+	return wrapped
 }
 
 func (s *GRPCServer) Start() error {
@@ -30,13 +72,6 @@ func (s *GRPCServer) Start() error {
 		return fmt.Errorf("failed to listen on port %d: %w", s.port, err)
 	}
 
-	s.server = grpc.NewServer()
-
-	pvzServiceServer := service.NewPVZServiceServer(s.pvzService)
-	pb.RegisterPVZServiceServer(s.server, pvzServiceServer)
-
-	reflection.Register(s.server)
-
 	if err := s.server.Serve(lis); err != nil {
 		return fmt.Errorf("failed to serve: %w", err)
 	}
@@ -44,8 +79,35 @@ func (s *GRPCServer) Start() error {
 	return nil
 }
 
-func (s *GRPCServer) Stop() {
-	if s.server != nil {
+// SetNotServing переводит health-сервис в NOT_SERVING: балансировщики
+// перестают направлять новые вызовы, текущие продолжают выполняться
+func (s *GRPCServer) SetNotServing() {
+	s.health.Shutdown()
+}
+
+// Shutdown дожидается завершения текущих вызовов. Если они не уложились до
+// отмены ctx, оставшиеся соединения закрываются принудительно
+func (s *GRPCServer) Shutdown(ctx context.Context) error {
+	s.SetNotServing()
+
+	done := make(chan struct{})
+	go func() {
 		s.server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		<-done
+		return fmt.Errorf("gRPC вызовы не завершились до таймаута: %w", ctx.Err())
 	}
 }
+
+func (s *GRPCServer) Stop() {
+	// клиенты health-проверок сразу видят NOT_SERVING, пока завершаются текущие вызовы
+	s.SetNotServing()
+	s.server.GracefulStop()
+}
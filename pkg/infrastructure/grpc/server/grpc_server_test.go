@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/auth"
+	"github.com/dkumancev/avito-pvz/pkg/application/metrics"
+	"github.com/dkumancev/avito-pvz/pkg/application/ratelimit"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/grpc/interceptors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCServer_HealthWithoutCredentials(t *testing.T) {
+	policy := auth.NewPolicy(auth.DefaultRolePermissions())
+	// один запрос без аутентификации на IP, чтобы повторные проверки упирались в лимит
+	limiter := ratelimit.New(ratelimit.Config{Public: ratelimit.Limit{Requests: 1, Period: time.Minute}})
+
+	chain := []grpc.UnaryServerInterceptor{
+		interceptors.AuthUnaryInterceptor(auth.NewTokenParser([]byte("test-secret"), false), nil),
+		interceptors.RateLimitUnaryInterceptor(limiter, metrics.NewNoopRateLimit()),
+		interceptors.PermissionUnaryInterceptor(policy, MethodPermissions),
+	}
+	s := NewGRPCServer(nil, 0, chain...)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Ожидалось отсутствие ошибки, получено: %v", err)
+	}
+	go s.server.Serve(lis)
+	defer s.server.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Ожидалось отсутствие ошибки, получено: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := healthpb.NewHealthClient(conn)
+	for i := 0; i < 3; i++ {
+		resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+		if err != nil {
+			t.Fatalf("Проверка %d: ожидалось отсутствие ошибки, получено: %v", i+1, err)
+		}
+		if resp.Status != healthpb.HealthCheckResponse_SERVING {
+			t.Errorf("Ожидался статус SERVING, получен %s", resp.Status)
+		}
+	}
+
+	// остальные методы по-прежнему требуют учетных данных
+	wrapped := skipHealth(chain)
+	_, err = wrapped[0](ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/pvz.v1.PVZService/GetPVZList"},
+		func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil })
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Ожидался код Unauthenticated, получено: %v", err)
+	}
+}
@@ -0,0 +1,10 @@
+package server
+
+import (
+	"github.com/dkumancev/avito-pvz/pkg/application/auth"
+)
+
+// MethodPermissions права, необходимые для вызова методов gRPC API
+var MethodPermissions = map[string]auth.Permission{
+	"/pvz.v1.PVZService/GetPVZList": auth.PermissionPVZRead,
+}
@@ -0,0 +1,73 @@
+package health
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Pinger соединение, поддерживающее проверку доступности (например, *sqlx.DB)
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// NewPostgresChecker проверяет доступность базы данных
+func NewPostgresChecker(db Pinger) Checker {
+	return NewChecker("postgres", func(ctx context.Context) error {
+		if err := db.PingContext(ctx); err != nil {
+			return fmt.Errorf("база данных недоступна: %w", err)
+		}
+		return nil
+	})
+}
+
+// MigrationVersions источник текущей и ожидаемой версий схемы БД
+type MigrationVersions interface {
+	GetCurrentVersion() (int64, error)
+	ExpectedVersion() (int64, error)
+}
+
+// NewMigrationChecker проверяет, что схема БД соответствует миграциям, с которыми собран сервис
+func NewMigrationChecker(versions MigrationVersions) Checker {
+	return NewChecker("migrations", func(ctx context.Context) error {
+		expected, err := versions.ExpectedVersion()
+		if err != nil {
+			return err
+		}
+
+		current, err := versions.GetCurrentVersion()
+		if err != nil {
+			return err
+		}
+
+		if current != expected {
+			return fmt.Errorf("версия схемы БД %d, ожидается %d", current, expected)
+		}
+		return nil
+	})
+}
+
+// NewGRPCChecker проверяет, что gRPC сервер по адресу target отвечает SERVING
+// через стандартный сервис grpc.health.v1
+func NewGRPCChecker(target string) Checker {
+	return NewChecker("grpc", func(ctx context.Context) error {
+		conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return fmt.Errorf("ошибка подключения к gRPC серверу: %w", err)
+		}
+		defer conn.Close()
+
+		resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+		if err != nil {
+			return fmt.Errorf("gRPC сервер недоступен: %w", err)
+		}
+
+		if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			return fmt.Errorf("gRPC сервер в состоянии %s", resp.GetStatus())
+		}
+		return nil
+	})
+}
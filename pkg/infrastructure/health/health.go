@@ -0,0 +1,192 @@
+// Package health реализует проверки живости (liveness) и готовности (readiness) сервиса
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// Checker проверка одной зависимости сервиса
+type Checker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+type checkerFunc struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// NewChecker создает проверку из функции
+func NewChecker(name string, check func(ctx context.Context) error) Checker {
+	return &checkerFunc{name: name, check: check}
+}
+
+func (c *checkerFunc) Name() string {
+	return c.name
+}
+
+func (c *checkerFunc) Check(ctx context.Context) error {
+	return c.check(ctx)
+}
+
+// CheckResult результат отдельной проверки
+type CheckResult struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Report ответ эндпоинтов /livez и /readyz
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// Health набор проверок живости и готовности
+type Health struct {
+	timeout         time.Duration
+	mu              sync.RWMutex
+	livenessChecks  []Checker
+	readinessChecks []Checker
+	shuttingDown    atomic.Bool
+}
+
+// New создает набор проверок. timeout ограничивает время каждой проверки
+func New(timeout time.Duration) *Health {
+	return &Health{
+		timeout: timeout,
+	}
+}
+
+// AddLivenessCheck добавляет проверку живости. Провал означает, что процесс нужно перезапустить,
+// поэтому сюда не стоит добавлять внешние зависимости
+func (h *Health) AddLivenessCheck(checker Checker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.livenessChecks = append(h.livenessChecks, checker)
+}
+
+// AddReadinessCheck добавляет проверку готовности принимать трафик
+func (h *Health) AddReadinessCheck(checker Checker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.readinessChecks = append(h.readinessChecks, checker)
+}
+
+// SetShuttingDown переводит сервис в состояние завершения: readiness начинает
+// отвечать ошибкой, чтобы балансировщик перестал направлять новые запросы
+func (h *Health) SetShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+// Liveness выполняет проверки живости
+func (h *Health) Liveness(ctx context.Context) Report {
+	h.mu.RLock()
+	checks := append([]Checker(nil), h.livenessChecks...)
+	h.mu.RUnlock()
+
+	return h.run(ctx, checks)
+}
+
+// Readiness выполняет проверки готовности
+func (h *Health) Readiness(ctx context.Context) Report {
+	h.mu.RLock()
+	checks := append([]Checker(nil), h.readinessChecks...)
+	h.mu.RUnlock()
+
+	report := h.run(ctx, checks)
+	if h.shuttingDown.Load() {
+		report.Status = StatusFail
+		report.Checks["shutdown"] = CheckResult{
+			Status:   StatusFail,
+			Error:    "сервис завершает работу",
+			Duration: "0s",
+		}
+	}
+
+	return report
+}
+
+// LivenessHandler HTTP обработчик /livez
+func (h *Health) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, h.Liveness(r.Context()))
+	})
+}
+
+// ReadinessHandler HTTP обработчик /readyz
+func (h *Health) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, h.Readiness(r.Context()))
+	})
+}
+
+// run выполняет проверки параллельно, каждую со своим таймаутом
+func (h *Health) run(ctx context.Context, checks []Checker) Report {
+	report := Report{
+		Status: StatusOK,
+		Checks: make(map[string]CheckResult, len(checks)),
+	}
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, checker := range checks {
+		wg.Add(1)
+		go func(i int, checker Checker) {
+			defer wg.Done()
+			results[i] = h.runCheck(ctx, checker)
+		}(i, checker)
+	}
+	wg.Wait()
+
+	for i, checker := range checks {
+		report.Checks[checker.Name()] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+
+	return report
+}
+
+func (h *Health) runCheck(ctx context.Context, checker Checker) CheckResult {
+	if h.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := checker.Check(ctx)
+	result := CheckResult{
+		Status:   StatusOK,
+		Duration: time.Since(start).String(),
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+func writeReport(w http.ResponseWriter, report Report) {
+	status := http.StatusOK
+	if report.Status != StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
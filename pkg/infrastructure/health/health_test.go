@@ -0,0 +1,82 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestReadinessHandler(t *testing.T) {
+	checks := New(50 * time.Millisecond)
+	checks.AddReadinessCheck(NewChecker("postgres", func(ctx context.Context) error { return nil }))
+
+	rec := httptest.NewRecorder()
+	checks.ReadinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Ожидался статус 200, получен %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Ожидался Content-Type application/json, получен %q", ct)
+	}
+
+	var report Report
+	if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+		t.Fatalf("Не удалось разобрать ответ: %v", err)
+	}
+	if report.Status != StatusOK || report.Checks["postgres"].Status != StatusOK {
+		t.Errorf("Неожиданный отчет: %+v", report)
+	}
+}
+
+func TestReadiness_FailingAndSlowChecks(t *testing.T) {
+	checks := New(20 * time.Millisecond)
+	checks.AddReadinessCheck(NewChecker("postgres", func(ctx context.Context) error { return nil }))
+	checks.AddReadinessCheck(NewChecker("migrations", func(ctx context.Context) error {
+		return errors.New("версия схемы БД 3, ожидается 4")
+	}))
+	checks.AddReadinessCheck(NewChecker("grpc", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+
+	rec := httptest.NewRecorder()
+	checks.ReadinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Ожидался статус 503, получен %d", rec.Code)
+	}
+
+	var report Report
+	json.NewDecoder(rec.Body).Decode(&report)
+	if report.Checks["postgres"].Status != StatusOK {
+		t.Error("Успешная проверка должна оставаться ok")
+	}
+	if report.Checks["migrations"].Error == "" {
+		t.Error("Ожидалось описание ошибки проверки миграций")
+	}
+	if report.Checks["grpc"].Status != StatusFail {
+		t.Error("Зависшая проверка должна завершиться по таймауту")
+	}
+}
+
+func TestReadiness_ShuttingDown(t *testing.T) {
+	checks := New(time.Second)
+	checks.AddReadinessCheck(NewChecker("postgres", func(ctx context.Context) error { return nil }))
+
+	checks.SetShuttingDown()
+
+	report := checks.Readiness(context.Background())
+	if report.Status != StatusFail || report.Checks["shutdown"].Status != StatusFail {
+		t.Errorf("Во время завершения readiness должен отвечать ошибкой: %+v", report)
+	}
+
+	// liveness не зависит от завершения работы
+	if checks.Liveness(context.Background()).Status != StatusOK {
+		t.Error("Liveness должен оставаться ok во время завершения")
+	}
+}
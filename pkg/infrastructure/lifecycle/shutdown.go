@@ -0,0 +1,95 @@
+// Package lifecycle выполняет завершение работы сервиса по шагам в заданном
+// порядке с общим таймаутом
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Phase - шаг завершения работы
+type Phase struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Shutdown выполняет шаги по очереди. Ошибка шага не прерывает следующие:
+// ресурсы должны освобождаться, даже если предыдущий шаг не уложился в срок
+type Shutdown struct {
+	timeout time.Duration
+	logger  *slog.Logger
+	phases  []Phase
+}
+
+func NewShutdown(timeout time.Duration, logger *slog.Logger) *Shutdown {
+	return &Shutdown{
+		timeout: timeout,
+		logger:  logger,
+	}
+}
+
+// Add добавляет шаг в конец очереди
+func (s *Shutdown) Add(name string, run func(ctx context.Context) error) {
+	s.phases = append(s.phases, Phase{Name: name, Run: run})
+}
+
+// Run выполняет шаги с общим таймаутом, логируя длительность каждого.
+// Возвращает ошибки всех шагов
+func (s *Shutdown) Run() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	start := time.Now()
+	s.logger.Info("Завершение работы", "timeout", s.timeout.String(), "phases", len(s.phases))
+
+	var errs []error
+	for _, phase := range s.phases {
+		phaseStart := time.Now()
+		err := phase.Run(ctx)
+		duration := time.Since(phaseStart)
+
+		if err != nil {
+			s.logger.Error("Шаг завершения работы выполнен с ошибкой",
+				"phase", phase.Name,
+				"duration", duration.String(),
+				"error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", phase.Name, err))
+			continue
+		}
+		s.logger.Info("Шаг завершения работы выполнен",
+			"phase", phase.Name,
+			"duration", duration.String())
+	}
+
+	err := errors.Join(errs...)
+	if err != nil {
+		s.logger.Error("Завершение работы выполнено с ошибками", "duration", time.Since(start).String())
+		return err
+	}
+	s.logger.Info("Завершение работы выполнено", "duration", time.Since(start).String())
+	return nil
+}
+
+// Delay возвращает шаг, который ждет d или отмены ctx. Используется как
+// пауза перед остановкой: балансировщик успевает увидеть, что сервис не
+// готов, и перестает направлять новые запросы
+func Delay(d time.Duration) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if d <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		}
+	}
+}
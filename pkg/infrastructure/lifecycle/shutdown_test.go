@@ -0,0 +1,73 @@
+package lifecycle
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShutdown_RunsPhasesInOrder(t *testing.T) {
+	var buf bytes.Buffer
+	shutdown := NewShutdown(time.Second, slog.New(slog.NewTextHandler(&buf, nil)))
+
+	var order []string
+	failure := errors.New("сервер не остановился")
+	shutdown.Add("readiness", func(ctx context.Context) error {
+		order = append(order, "readiness")
+		return nil
+	})
+	shutdown.Add("http", func(ctx context.Context) error {
+		order = append(order, "http")
+		return failure
+	})
+	shutdown.Add("database", func(ctx context.Context) error {
+		order = append(order, "database")
+		return nil
+	})
+
+	err := shutdown.Run()
+	if !errors.Is(err, failure) {
+		t.Fatalf("Ожидалась ошибка шага http, получено: %v", err)
+	}
+	// ошибка шага не мешает закрыть БД
+	if strings.Join(order, ",") != "readiness,http,database" {
+		t.Errorf("Неверный порядок шагов: %v", order)
+	}
+
+	logs := buf.String()
+	for _, phase := range []string{"phase=readiness", "phase=http", "phase=database"} {
+		if !strings.Contains(logs, phase) {
+			t.Errorf("В логах нет шага %s: %s", phase, logs)
+		}
+	}
+	if !strings.Contains(logs, "duration=") {
+		t.Errorf("В логах нет длительности шагов: %s", logs)
+	}
+}
+
+func TestShutdown_TimeoutSharedByPhases(t *testing.T) {
+	shutdown := NewShutdown(20*time.Millisecond, slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil)))
+
+	shutdown.Add("pre_stop_delay", Delay(time.Hour))
+	var lastCtxErr error
+	shutdown.Add("database", func(ctx context.Context) error {
+		lastCtxErr = ctx.Err()
+		return nil
+	})
+
+	start := time.Now()
+	err := shutdown.Run()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Ожидался таймаут паузы, получено: %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("Пауза должна прерываться по общему таймауту")
+	}
+	if lastCtxErr == nil {
+		t.Error("Следующий шаг должен видеть истекший таймаут")
+	}
+}
@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/requestctx"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ContextHandler добавляет к записям лога сведения о запросе из контекста:
+// request_id, user_id, pvz_id и trace_id. Сведения берутся только при вызовах
+// с контекстом (InfoContext, ErrorContext и т.д.)
+type ContextHandler struct {
+	slog.Handler
+}
+
+// NewContextHandler оборачивает обработчик записей лога
+func NewContextHandler(handler slog.Handler) *ContextHandler {
+	return &ContextHandler{Handler: handler}
+}
+
+func (h *ContextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if values, ok := requestctx.FromContext(ctx); ok {
+		record.AddAttrs(slog.String("request_id", values.RequestID))
+		if values.UserID != "" {
+			record.AddAttrs(slog.String("user_id", values.UserID))
+		}
+		if values.PVZID != "" {
+			record.AddAttrs(slog.String("pvz_id", values.PVZID))
+		}
+	}
+
+	spanContext := trace.SpanContextFromContext(ctx)
+	if spanContext.IsValid() {
+		record.AddAttrs(slog.String("trace_id", spanContext.TraceID().String()))
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ContextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	return &ContextHandler{Handler: h.Handler.WithGroup(name)}
+}
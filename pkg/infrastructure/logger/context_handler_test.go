@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/requestctx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestContextHandler_AddsRequestFields(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Config{Output: &buf, Format: "json"})
+
+	ctx := requestctx.New(context.Background(), "req-1")
+	requestctx.SetUserID(ctx, "user-1")
+	requestctx.SetPVZID(ctx, "pvz-1")
+
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+	ctx = trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+
+	log.With("component", "test").InfoContext(ctx, "сообщение")
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "req-1", record["request_id"])
+	assert.Equal(t, "user-1", record["user_id"])
+	assert.Equal(t, "pvz-1", record["pvz_id"])
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", record["trace_id"])
+	assert.Equal(t, "test", record["component"])
+}
+
+func TestContextHandler_WithoutRequest(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Config{Output: &buf, Format: "json"})
+
+	log.InfoContext(context.Background(), "сообщение")
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.NotContains(t, record, "request_id")
+	assert.NotContains(t, record, "trace_id")
+}
@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// LevelsResponse - текущие уровни логирования
+type LevelsResponse struct {
+	Level      string            `json:"level"`
+	Components map[string]string `json:"components"`
+}
+
+// LevelRequest - изменение уровня. Пустой component меняет общий уровень,
+// пустой level у компонента возвращает его к общему уровню
+type LevelRequest struct {
+	Component string `json:"component"`
+	Level     string `json:"level"`
+}
+
+type errorResponse struct {
+	Message string `json:"message"`
+}
+
+// LevelsHandler отдает уровни логирования по GET и меняет их по PUT
+func LevelsHandler(levels *Levels) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var req LevelRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeJSON(w, http.StatusBadRequest, errorResponse{Message: "Неверный формат запроса"})
+				return
+			}
+
+			if req.Component != "" && req.Level == "" {
+				levels.Reset(req.Component)
+			} else if err := levels.Set(req.Component, req.Level); err != nil {
+				writeJSON(w, http.StatusBadRequest, errorResponse{Message: err.Error()})
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Message: "Метод не поддерживается"})
+			return
+		}
+
+		level, components := levels.Snapshot()
+		writeJSON(w, http.StatusOK, LevelsResponse{Level: level, Components: components})
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
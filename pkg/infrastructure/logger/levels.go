@@ -0,0 +1,163 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// Levels хранит общий уровень логирования и уровни отдельных компонентов.
+// Уровни можно менять во время работы, изменения сразу видны всем логгерам
+type Levels struct {
+	mu         sync.RWMutex
+	root       slog.Level
+	components map[string]slog.Level
+}
+
+// NewLevels создает уровни из общего уровня и уровней компонентов
+func NewLevels(root string, components map[string]string) (*Levels, error) {
+	rootLevel, err := ParseLevel(root)
+	if err != nil {
+		return nil, err
+	}
+
+	levels := &Levels{
+		root:       rootLevel,
+		components: make(map[string]slog.Level, len(components)),
+	}
+	for component, value := range components {
+		level, err := ParseLevel(value)
+		if err != nil {
+			return nil, fmt.Errorf("компонент %s: %w", component, err)
+		}
+		levels.components[component] = level
+	}
+
+	return levels, nil
+}
+
+// Level возвращает действующий уровень компонента. Пустое имя - общий уровень
+func (l *Levels) Level(component string) slog.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if level, ok := l.components[component]; ok {
+		return level
+	}
+	return l.root
+}
+
+// Set меняет уровень компонента, а при пустом имени - общий уровень
+func (l *Levels) Set(component, value string) error {
+	level, err := ParseLevel(value)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if component == "" {
+		l.root = level
+	} else {
+		l.components[component] = level
+	}
+	return nil
+}
+
+// Reset убирает уровень компонента, после чего он использует общий уровень
+func (l *Levels) Reset(component string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.components, component)
+}
+
+// Snapshot возвращает общий уровень и уровни компонентов в текстовом виде
+func (l *Levels) Snapshot() (string, map[string]string) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	components := make(map[string]string, len(l.components))
+	for component, level := range l.components {
+		components[component] = FormatLevel(level)
+	}
+	return FormatLevel(l.root), components
+}
+
+// ParseLevel разбирает уровень логирования: debug, info, warn или error
+func ParseLevel(value string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case LevelDebug:
+		return slog.LevelDebug, nil
+	case LevelInfo, "":
+		return slog.LevelInfo, nil
+	case LevelWarn, "warning":
+		return slog.LevelWarn, nil
+	case LevelError:
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("неизвестный уровень логирования: %s", value)
+	}
+}
+
+// FormatLevel возвращает имя уровня в том виде, в котором он задается в конфигурации
+func FormatLevel(level slog.Level) string {
+	switch {
+	case level < slog.LevelInfo:
+		return LevelDebug
+	case level < slog.LevelWarn:
+		return LevelInfo
+	case level < slog.LevelError:
+		return LevelWarn
+	default:
+		return LevelError
+	}
+}
+
+// ParseComponentLevels разбирает уровни компонентов из строки вида
+// "db=warn,http=debug"
+func ParseComponentLevels(value string) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		component, level, ok := strings.Cut(item, "=")
+		component = strings.TrimSpace(component)
+		if !ok || component == "" {
+			return nil, fmt.Errorf("неверный формат уровня компонента: %s", item)
+		}
+
+		_, err := ParseLevel(level)
+		if err != nil {
+			return nil, fmt.Errorf("компонент %s: %w", component, err)
+		}
+		result[component] = strings.ToLower(strings.TrimSpace(level))
+	}
+
+	return result, nil
+}
+
+// levelHandler пропускает записи не ниже текущего уровня компонента
+type levelHandler struct {
+	slog.Handler
+	levels    *Levels
+	component string
+}
+
+func (h *levelHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.levels.Level(h.component)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithAttrs(attrs), levels: h.levels, component: h.component}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithGroup(name), levels: h.levels, component: h.component}
+}
@@ -1,10 +1,14 @@
 package logger
 
 import (
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
 	"strings"
+
+	"github.com/dkumancev/avito-pvz/config"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 const (
@@ -14,21 +18,57 @@ const (
 	LevelError = "error"
 )
 
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Компоненты приложения, для которых можно задать свой уровень логирования
+const (
+	ComponentHTTP     = "http"
+	ComponentGRPC     = "grpc"
+	ComponentDB       = "db"
+	ComponentServices = "services"
+	ComponentMetrics  = "metrics"
+	ComponentJobs     = "jobs"
+)
+
 type Config struct {
 	Level     string
 	AddSource bool
 	Output    io.Writer
 	Format    string // json или text
+
+	// File - путь к файлу лога. Если задан, записи пишутся в файл с ротацией
+	// вместо Output
+	File       string
+	MaxSizeMB  int  // размер файла, после которого начинается новый
+	MaxBackups int  // сколько старых файлов хранить
+	MaxAgeDays int  // сколько дней хранить старые файлы
+	Compress   bool // сжимать старые файлы gzip
+
+	// ComponentLevels - уровни отдельных компонентов, переопределяющие Level
+	ComponentLevels map[string]string
 }
 
 var defaultConfig = Config{
 	Level:     LevelInfo,
 	AddSource: false,
 	Output:    os.Stdout,
-	Format:    "text",
+	Format:    FormatText,
 }
 
-func NewLogger(cfg Config) *slog.Logger {
+// Logger - корневой логгер приложения. Логгеры компонентов получаются через
+// Component и подчиняются уровням из Levels, которые можно менять на лету
+type Logger struct {
+	*slog.Logger
+	handler slog.Handler
+	levels  *Levels
+	closer  io.Closer
+}
+
+// New создает логгер по конфигурации
+func New(cfg Config) (*Logger, error) {
 	if cfg.Level == "" {
 		cfg.Level = defaultConfig.Level
 	}
@@ -41,31 +81,105 @@ func NewLogger(cfg Config) *slog.Logger {
 		cfg.Format = defaultConfig.Format
 	}
 
-	var level slog.Level
-	switch strings.ToLower(cfg.Level) {
-	case LevelDebug:
-		level = slog.LevelDebug
-	case LevelWarn:
-		level = slog.LevelWarn
-	case LevelError:
-		level = slog.LevelError
-	default:
-		level = slog.LevelInfo
+	levels, err := NewLevels(cfg.Level, cfg.ComponentLevels)
+	if err != nil {
+		return nil, err
+	}
+
+	output := cfg.Output
+	var closer io.Closer
+	if cfg.File != "" {
+		file := &lumberjack.Logger{
+			Filename:   cfg.File,
+			MaxSize:    cfg.MaxSizeMB,
+			MaxBackups: cfg.MaxBackups,
+			MaxAge:     cfg.MaxAgeDays,
+			Compress:   cfg.Compress,
+		}
+		output = file
+		closer = file
 	}
 
+	// Фильтрация по уровню выполняется в levelHandler, поэтому базовый
+	// обработчик пропускает все записи
 	opts := &slog.HandlerOptions{
 		AddSource: cfg.AddSource,
-		Level:     level,
+		Level:     slog.Level(-8),
 	}
 
 	var handler slog.Handler
-	if strings.ToLower(cfg.Format) == "json" {
-		handler = slog.NewJSONHandler(cfg.Output, opts)
-	} else {
-		handler = slog.NewTextHandler(cfg.Output, opts)
+	switch strings.ToLower(cfg.Format) {
+	case FormatJSON:
+		handler = slog.NewJSONHandler(output, opts)
+	case FormatText:
+		handler = slog.NewTextHandler(output, opts)
+	default:
+		return nil, fmt.Errorf("неизвестный формат логов: %s", cfg.Format)
+	}
+	handler = NewContextHandler(handler)
+
+	return &Logger{
+		Logger:  slog.New(&levelHandler{Handler: handler, levels: levels}),
+		handler: handler,
+		levels:  levels,
+		closer:  closer,
+	}, nil
+}
+
+// NewFromConfig создает логгер приложения по настройкам из конфигурации
+func NewFromConfig(cfg *config.Config) (*Logger, error) {
+	componentLevels, err := ParseComponentLevels(cfg.Log.ComponentLevels)
+	if err != nil {
+		return nil, err
 	}
 
-	return slog.New(handler)
+	return New(Config{
+		Level:           cfg.App.LogLevel,
+		AddSource:       cfg.Log.AddSource,
+		Format:          cfg.Log.Format,
+		File:            cfg.Log.File,
+		MaxSizeMB:       cfg.Log.MaxSizeMB,
+		MaxBackups:      cfg.Log.MaxBackups,
+		MaxAgeDays:      cfg.Log.MaxAgeDays,
+		Compress:        cfg.Log.Compress,
+		ComponentLevels: componentLevels,
+	})
+}
+
+// NewLogger создает логгер без поддержки файла и уровней компонентов.
+// Некорректные уровень и формат заменяются значениями по умолчанию
+func NewLogger(cfg Config) *slog.Logger {
+	cfg.File = ""
+	cfg.ComponentLevels = nil
+	if _, err := ParseLevel(cfg.Level); err != nil {
+		cfg.Level = defaultConfig.Level
+	}
+	if f := strings.ToLower(cfg.Format); f != FormatJSON && f != FormatText {
+		cfg.Format = defaultConfig.Format
+	}
+
+	log, _ := New(cfg)
+	return log.Logger
+}
+
+// Component возвращает логгер компонента с атрибутом component. Уровень
+// компонента берется из Levels, а если он не задан - общий
+func (l *Logger) Component(name string) *slog.Logger {
+	handler := &levelHandler{Handler: l.handler, levels: l.levels, component: name}
+	return slog.New(handler).With("component", name)
+}
+
+// Levels возвращает уровни логирования для изменения во время работы
+func (l *Logger) Levels() *Levels {
+	return l.levels
+}
+
+// Close закрывает файл лога, если он используется
+func (l *Logger) Close() error {
+	if l.closer == nil {
+		return nil
+	}
+	return l.closer.Close()
 }
 
 // SanitizeCredentials удаляет чувствительные данные из строки или заменяет их на маску
@@ -97,11 +211,3 @@ func SanitizeError(err error) string {
 
 	return errMsg
 }
-
-func NewLoggerFromEnvironment(logLevel string) *slog.Logger {
-	return NewLogger(Config{
-		Level:     logLevel,
-		AddSource: logLevel == LevelDebug, // В режиме отладки добавляем информацию об источнике
-		Format:    "text",                 // По умолчанию текстовый формат
-	})
-}
@@ -0,0 +1,148 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_ComponentLevels(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := New(Config{
+		Level:           LevelInfo,
+		Output:          &buf,
+		Format:          FormatJSON,
+		ComponentLevels: map[string]string{ComponentDB: LevelWarn, ComponentHTTP: LevelDebug},
+	})
+	require.NoError(t, err)
+
+	log.Debug("корневой debug")
+	log.Component(ComponentDB).Info("db info")
+	log.Component(ComponentDB).Warn("db warn")
+	log.Component(ComponentHTTP).Debug("http debug")
+	log.Component(ComponentGRPC).Info("grpc info")
+
+	output := buf.String()
+	assert.NotContains(t, output, "корневой debug")
+	assert.NotContains(t, output, "db info")
+	assert.Contains(t, output, "db warn")
+	assert.Contains(t, output, "http debug")
+	assert.Contains(t, output, `"component":"grpc"`)
+}
+
+func TestNew_LevelChangeAtRuntime(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := New(Config{Level: LevelInfo, Output: &buf, Format: FormatText})
+	require.NoError(t, err)
+
+	dbLogger := log.Component(ComponentDB).With("repository", "pvz")
+	dbLogger.Debug("до изменения")
+
+	require.NoError(t, log.Levels().Set(ComponentDB, LevelDebug))
+	dbLogger.Debug("после изменения")
+
+	log.Levels().Reset(ComponentDB)
+	dbLogger.Debug("после сброса")
+
+	output := buf.String()
+	assert.NotContains(t, output, "до изменения")
+	assert.Contains(t, output, "после изменения")
+	assert.NotContains(t, output, "после сброса")
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	_, err := New(Config{Level: "verbose"})
+	assert.Error(t, err)
+
+	_, err = New(Config{Format: "xml"})
+	assert.Error(t, err)
+
+	_, err = New(Config{ComponentLevels: map[string]string{ComponentDB: "trace"}})
+	assert.Error(t, err)
+}
+
+func TestNew_FileOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	log, err := New(Config{Format: FormatJSON, File: path, MaxSizeMB: 1})
+	require.NoError(t, err)
+
+	log.Info("запись в файл")
+	require.NoError(t, log.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "запись в файл")
+}
+
+func TestParseComponentLevels(t *testing.T) {
+	levels, err := ParseComponentLevels(" db=WARN, http=debug ,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"db": "warn", "http": "debug"}, levels)
+
+	_, err = ParseComponentLevels("db")
+	assert.Error(t, err)
+
+	_, err = ParseComponentLevels("db=trace")
+	assert.Error(t, err)
+}
+
+func TestLevelsHandler(t *testing.T) {
+	levels, err := NewLevels(LevelInfo, nil)
+	require.NoError(t, err)
+	handler := LevelsHandler(levels)
+
+	put := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/log/level", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := put(`{"component":"db","level":"debug"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var result LevelsResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	assert.Equal(t, LevelInfo, result.Level)
+	assert.Equal(t, map[string]string{"db": LevelDebug}, result.Components)
+	assert.True(t, levels.Level(ComponentDB) < levels.Level(""))
+
+	rec = put(`{"level":"error"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, levels.Level("") > levels.Level(ComponentDB))
+
+	rec = put(`{"component":"db"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, levels.Level(""), levels.Level(ComponentDB))
+
+	rec = put(`{"level":"verbose"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/log/level", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	assert.Equal(t, LevelError, result.Level)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/log/level", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestLevelHandler_EnabledWithContext(t *testing.T) {
+	log, err := New(Config{Level: LevelWarn, Output: &bytes.Buffer{}})
+	require.NoError(t, err)
+
+	assert.False(t, log.Enabled(context.Background(), slog.LevelDebug))
+	assert.True(t, log.Component(ComponentHTTP).Enabled(context.Background(), slog.LevelError))
+}
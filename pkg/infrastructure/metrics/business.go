@@ -0,0 +1,113 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// BusinessMetrics реализация бизнес-метрик сервисов на Prometheus
+type BusinessMetrics struct {
+	PVZCreatedTotal       *prometheus.CounterVec
+	ReceptionsOpenedTotal *prometheus.CounterVec
+	ReceptionsClosedTotal *prometheus.CounterVec
+	ReceptionDuration     *prometheus.HistogramVec
+	ProductsAddedTotal    *prometheus.CounterVec
+	ProductsRemovedTotal  *prometheus.CounterVec
+
+	ReceptionsAutoClosedTotal *prometheus.CounterVec
+	ReceptionsStaleTotal      *prometheus.CounterVec
+}
+
+func NewBusinessMetrics() *BusinessMetrics {
+	return &BusinessMetrics{
+		PVZCreatedTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "pvz_created_total",
+				Help: "Количество созданных ПВЗ",
+			},
+			[]string{"city"},
+		),
+		ReceptionsOpenedTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "receptions_opened_total",
+				Help: "Количество открытых приемок",
+			},
+			[]string{"city"},
+		),
+		ReceptionsClosedTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "receptions_closed_total",
+				Help: "Количество закрытых приемок",
+			},
+			[]string{"city"},
+		),
+		ReceptionDuration: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name: "reception_duration_seconds",
+				Help: "Длительность приемки от открытия до закрытия в секундах",
+				// от минуты до суток
+				Buckets: []float64{60, 300, 900, 1800, 3600, 7200, 14400, 28800, 86400},
+			},
+			[]string{"city"},
+		),
+		ProductsAddedTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "products_added_total",
+				Help: "Количество добавленных товаров",
+			},
+			[]string{"city", "type"},
+		),
+		ProductsRemovedTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "products_removed_total",
+				Help: "Количество удаленных товаров",
+			},
+			[]string{"city", "type"},
+		),
+		ReceptionsAutoClosedTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "receptions_auto_closed_total",
+				Help: "Количество зависших приемок, закрытых системой",
+			},
+			[]string{"city"},
+		),
+		ReceptionsStaleTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "receptions_marked_stale_total",
+				Help: "Количество зависших приемок, отмеченных без закрытия",
+			},
+			[]string{"city"},
+		),
+	}
+}
+
+func (m *BusinessMetrics) PVZCreated(city string) {
+	m.PVZCreatedTotal.WithLabelValues(city).Inc()
+}
+
+func (m *BusinessMetrics) ReceptionOpened(city string) {
+	m.ReceptionsOpenedTotal.WithLabelValues(city).Inc()
+}
+
+func (m *BusinessMetrics) ReceptionClosed(city string, duration time.Duration) {
+	m.ReceptionsClosedTotal.WithLabelValues(city).Inc()
+	m.ReceptionDuration.WithLabelValues(city).Observe(duration.Seconds())
+}
+
+func (m *BusinessMetrics) ProductAdded(city, productType string) {
+	m.ProductsAddedTotal.WithLabelValues(city, productType).Inc()
+}
+
+func (m *BusinessMetrics) ProductRemoved(city, productType string) {
+	m.ProductsRemovedTotal.WithLabelValues(city, productType).Inc()
+}
+
+func (m *BusinessMetrics) ReceptionAutoClosed(city string) {
+	m.ReceptionsAutoClosedTotal.WithLabelValues(city).Inc()
+}
+
+func (m *BusinessMetrics) ReceptionMarkedStale(city string) {
+	m.ReceptionsStaleTotal.WithLabelValues(city).Inc()
+}
@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// DBMetrics метрики запросов репозиториев к PostgreSQL
+type DBMetrics struct {
+	QueryDuration *prometheus.HistogramVec
+}
+
+func NewDBMetrics() *DBMetrics {
+	return &DBMetrics{
+		QueryDuration: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "db_query_duration_seconds",
+				Help:    "Длительность выполнения методов репозиториев в секундах",
+				Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+			},
+			[]string{"repository", "method"},
+		),
+	}
+}
+
+// ObserveQuery реализует db.QueryRecorder
+func (m *DBMetrics) ObserveQuery(repository, method string, duration time.Duration) {
+	m.QueryDuration.WithLabelValues(repository, method).Observe(duration.Seconds())
+}
+
+// RegisterDBStats регистрирует сборщик состояния пула соединений (sql.DBStats):
+// go_sql_open_connections, go_sql_in_use_connections, go_sql_idle_connections,
+// go_sql_wait_count_total, go_sql_wait_duration_seconds_total и др.
+// с меткой db_name
+func RegisterDBStats(db *sql.DB, dbName string) error {
+	return prometheus.Register(collectors.NewDBStatsCollector(db, dbName))
+}
@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// JobMetrics реализация метрик фоновых задач на Prometheus
+type JobMetrics struct {
+	RunsTotal   *prometheus.CounterVec
+	RunDuration *prometheus.HistogramVec
+	LastSuccess *prometheus.GaugeVec
+	Leader      *prometheus.GaugeVec
+}
+
+func NewJobMetrics() *JobMetrics {
+	return &JobMetrics{
+		RunsTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "job_runs_total",
+				Help: "Количество проходов фоновой задачи",
+			},
+			[]string{"job", "result"},
+		),
+		RunDuration: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "job_run_duration_seconds",
+				Help:    "Длительность прохода фоновой задачи в секундах",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"job"},
+		),
+		LastSuccess: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "job_last_success_timestamp_seconds",
+				Help: "Время последнего успешного прохода фоновой задачи",
+			},
+			[]string{"job"},
+		),
+		Leader: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "job_leader",
+				Help: "1, если реплика выполняет фоновую задачу как лидер",
+			},
+			[]string{"job"},
+		),
+	}
+}
+
+func (m *JobMetrics) JobRun(job string, duration time.Duration, err error) {
+	m.RunDuration.WithLabelValues(job).Observe(duration.Seconds())
+	if err != nil {
+		m.RunsTotal.WithLabelValues(job, "error").Inc()
+		return
+	}
+	m.RunsTotal.WithLabelValues(job, "success").Inc()
+	m.LastSuccess.WithLabelValues(job).SetToCurrentTime()
+}
+
+func (m *JobMetrics) JobLeader(job string, leader bool) {
+	value := 0.0
+	if leader {
+		value = 1
+	}
+	m.Leader.WithLabelValues(job).Set(value)
+}
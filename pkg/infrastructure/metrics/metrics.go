@@ -6,8 +6,12 @@ import (
 )
 
 type HTTPMetrics struct {
-	RequestsTotal   *prometheus.CounterVec
-	RequestDuration *prometheus.HistogramVec
+	RequestsTotal    *prometheus.CounterVec
+	RequestDuration  *prometheus.HistogramVec
+	RequestsInFlight prometheus.Gauge
+	ResponseSize     *prometheus.HistogramVec
+	// SLO учитывает запросы для расчета SLO; nil - не учитывать
+	SLO *SLOTracker
 }
 
 func NewHTTPMetrics() *HTTPMetrics {
@@ -28,18 +32,38 @@ func NewHTTPMetrics() *HTTPMetrics {
 		[]string{"method", "path", "status"},
 	)
 
+	requestsInFlight := promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "http_requests_in_flight",
+			Help: "Количество HTTP запросов, обрабатываемых в данный момент",
+		},
+	)
+
+	responseSize := promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "http_response_size_bytes",
+			Help:    "Размер тела HTTP ответа в байтах",
+			Buckets: prometheus.ExponentialBuckets(64, 4, 8),
+		},
+		[]string{"method", "path", "status"},
+	)
+
 	return &HTTPMetrics{
-		RequestsTotal:   requestsTotal,
-		RequestDuration: requestDuration,
+		RequestsTotal:    requestsTotal,
+		RequestDuration:  requestDuration,
+		RequestsInFlight: requestsInFlight,
+		ResponseSize:     responseSize,
 	}
 }
 
 type Registry struct {
-	HTTP *HTTPMetrics
+	HTTP     *HTTPMetrics
+	Business *BusinessMetrics
 }
 
 func NewRegistry() *Registry {
 	return &Registry{
-		HTTP: NewHTTPMetrics(),
+		HTTP:     NewHTTPMetrics(),
+		Business: NewBusinessMetrics(),
 	}
 }
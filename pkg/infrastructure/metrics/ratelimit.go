@@ -0,0 +1,27 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// RateLimitMetrics реализация метрик ограничения частоты запросов на Prometheus
+type RateLimitMetrics struct {
+	ThrottledTotal *prometheus.CounterVec
+}
+
+func NewRateLimitMetrics() *RateLimitMetrics {
+	return &RateLimitMetrics{
+		ThrottledTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "rate_limit_throttled_total",
+				Help: "Количество запросов, отклоненных ограничением частоты",
+			},
+			[]string{"transport", "route", "subject"},
+		),
+	}
+}
+
+func (m *RateLimitMetrics) Throttled(transport, route, subject string) {
+	m.ThrottledTotal.WithLabelValues(transport, route, subject).Inc()
+}
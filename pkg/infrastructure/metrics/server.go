@@ -2,37 +2,88 @@ package metrics
 
 import (
 	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
+	"net/http/pprof"
+	runtimepprof "runtime/pprof"
+	"strings"
 	"time"
 
+	"github.com/dkumancev/avito-pvz/config"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/buildinfo"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/logger"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+// ErrAdminTokenRequired возвращается при включенной отладке без токена
+var ErrAdminTokenRequired = errors.New("METRICS_DEBUG_ENABLED требует задать ключ доступа администратора")
+
 type Server struct {
 	httpServer *http.Server
-	port       string
+	cfg        config.MetricsConfig
 	logger     *slog.Logger
+	levels     *logger.Levels
+	slo        *SLOTracker
 }
 
-func NewServer(port string, logger *slog.Logger) *Server {
+// NewServer создает сервер метрик. Если переданы уровни логирования и задан
+// токен администратора, на сервере доступен /log/level для их просмотра и изменения
+func NewServer(cfg config.MetricsConfig, log *slog.Logger, levels *logger.Levels) *Server {
 	return &Server{
-		port:   port,
-		logger: logger,
+		cfg:    cfg,
+		logger: log,
+		levels: levels,
 	}
 }
 
-func (s *Server) Start() error {
+// WithSLO добавляет на сервер сводку SLO по адресу /slo. Вызывается до Start
+func (s *Server) WithSLO(tracker *SLOTracker) *Server {
+	s.slo = tracker
+	return s
+}
+
+// Handler возвращает обработчик запросов сервера метрик. Отладочные эндпоинты
+// и /log/level закрыты bearer токеном; без токена /log/level не подключается
+func (s *Server) Handler() http.Handler {
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.Handler())
+	if s.slo != nil {
+		mux.Handle("/slo", s.slo.Handler())
+	}
+	if s.levels != nil && s.cfg.AdminToken != "" {
+		mux.Handle("/log/level", s.protect(logger.LevelsHandler(s.levels)))
+	}
+
+	if s.cfg.DebugEnabled {
+		mux.Handle("/debug/pprof/", s.protect(http.HandlerFunc(pprof.Index)))
+		mux.Handle("/debug/pprof/cmdline", s.protect(http.HandlerFunc(pprof.Cmdline)))
+		mux.Handle("/debug/pprof/profile", s.protect(http.HandlerFunc(pprof.Profile)))
+		mux.Handle("/debug/pprof/symbol", s.protect(http.HandlerFunc(pprof.Symbol)))
+		mux.Handle("/debug/pprof/trace", s.protect(http.HandlerFunc(pprof.Trace)))
+		mux.Handle("/debug/goroutines", s.protect(http.HandlerFunc(goroutinesHandler)))
+		mux.Handle("/debug/buildinfo", s.protect(http.HandlerFunc(buildInfoHandler)))
+	}
+
+	return mux
+}
+
+func (s *Server) Start() error {
+	if s.cfg.DebugEnabled && s.cfg.AdminToken == "" {
+		return ErrAdminTokenRequired
+	}
 
 	s.httpServer = &http.Server{
-		Addr:              ":" + s.port,
-		Handler:           mux,
+		Addr:              ":" + s.cfg.Port,
+		Handler:           s.Handler(),
 		ReadHeaderTimeout: 3 * time.Second,
 	}
 
-	s.logger.Info("Запуск сервера метрик", "port", s.port)
+	s.logger.Info("Запуск сервера метрик",
+		"port", s.cfg.Port,
+		"debug_enabled", s.cfg.DebugEnabled)
 
 	go func() {
 		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -47,3 +98,31 @@ func (s *Server) Stop(ctx context.Context) error {
 	s.logger.Info("Остановка сервера метрик")
 	return s.httpServer.Shutdown(ctx)
 }
+
+// protect пропускает запрос только с заголовком Authorization: Bearer <токен>.
+// Без настроенного токена запросы отклоняются
+func (s *Server) protect(next http.Handler) http.Handler {
+	expected := []byte(s.cfg.AdminToken)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || len(expected) == 0 || subtle.ConstantTimeCompare([]byte(token), expected) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "Требуется токен администратора", http.StatusUnauthorized)
+			return
+		}
+
+		s.logger.InfoContext(r.Context(), "Запрос к служебному эндпоинту", "path", r.URL.Path)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// goroutinesHandler отдает стеки всех горутин в текстовом виде
+func goroutinesHandler(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_ = runtimepprof.Lookup("goroutine").WriteTo(w, 2)
+}
+
+func buildInfoHandler(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(buildinfo.Get())
+}
@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dkumancev/avito-pvz/config"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/buildinfo"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T, cfg config.MetricsConfig) http.Handler {
+	t.Helper()
+
+	levels, err := logger.NewLevels(logger.LevelInfo, nil)
+	require.NoError(t, err)
+
+	return NewServer(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), levels).Handler()
+}
+
+func serve(handler http.Handler, method, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServer_DebugDisabled(t *testing.T) {
+	handler := newTestServer(t, config.MetricsConfig{})
+
+	assert.Equal(t, http.StatusOK, serve(handler, http.MethodGet, "/metrics", "").Code)
+	assert.Equal(t, http.StatusNotFound, serve(handler, http.MethodGet, "/debug/pprof/", "").Code)
+	assert.Equal(t, http.StatusNotFound, serve(handler, http.MethodGet, "/debug/buildinfo", "").Code)
+}
+
+func TestServer_LogLevelRequiresToken(t *testing.T) {
+	// без токена уровни логирования нельзя менять извне
+	handler := newTestServer(t, config.MetricsConfig{})
+	assert.Equal(t, http.StatusNotFound, serve(handler, http.MethodGet, "/log/level", "").Code)
+	assert.Equal(t, http.StatusNotFound, serve(handler, http.MethodPut, "/log/level", "").Code)
+
+	handler = newTestServer(t, config.MetricsConfig{AdminToken: "secret"})
+	assert.Equal(t, http.StatusUnauthorized, serve(handler, http.MethodGet, "/log/level", "").Code)
+	assert.Equal(t, http.StatusOK, serve(handler, http.MethodGet, "/log/level", "secret").Code)
+}
+
+func TestServer_DebugRequiresToken(t *testing.T) {
+	handler := newTestServer(t, config.MetricsConfig{DebugEnabled: true, AdminToken: "secret"})
+
+	for _, path := range []string{"/debug/pprof/", "/debug/goroutines", "/debug/buildinfo", "/log/level"} {
+		rec := serve(handler, http.MethodGet, path, "")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, path)
+		assert.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"), path)
+
+		assert.Equal(t, http.StatusUnauthorized, serve(handler, http.MethodGet, path, "wrong").Code, path)
+		assert.Equal(t, http.StatusOK, serve(handler, http.MethodGet, path, "secret").Code, path)
+	}
+
+	// метрики остаются открытыми для Prometheus
+	assert.Equal(t, http.StatusOK, serve(handler, http.MethodGet, "/metrics", "").Code)
+}
+
+func TestServer_Diagnostics(t *testing.T) {
+	handler := newTestServer(t, config.MetricsConfig{DebugEnabled: true, AdminToken: "secret"})
+
+	rec := serve(handler, http.MethodGet, "/debug/buildinfo", "secret")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var info buildinfo.Info
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &info))
+	assert.Equal(t, buildinfo.Version, info.Version)
+	assert.True(t, strings.HasPrefix(info.GoVersion, "go"))
+
+	rec = serve(handler, http.MethodGet, "/debug/goroutines", "secret")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "goroutine ")
+}
+
+func TestServer_StartRequiresTokenForDebug(t *testing.T) {
+	server := NewServer(config.MetricsConfig{Port: "0", DebugEnabled: true},
+		slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+
+	assert.ErrorIs(t, server.Start(), ErrAdminTokenRequired)
+}
@@ -0,0 +1,299 @@
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/config"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	SLOAvailability = "availability"
+	SLOLatency      = "latency"
+
+	sloResultSuccess = "success"
+	sloResultError   = "error"
+
+	// число корзин в самом коротком окне: чем больше, тем точнее скольжение
+	sloBucketsPerWindow = 30
+)
+
+// множители порога задержки для корзин гистограммы: значения плотнее всего
+// около самого порога, чтобы по гистограмме можно было считать долю быстрых запросов
+var sloBucketFactors = []float64{0.1, 0.25, 0.5, 0.75, 0.9, 1, 1.1, 1.25, 1.5, 2, 3, 5, 10, 25}
+
+// SLOTracker считает SLI HTTP API: гистограмму задержек с корзинами вокруг
+// порога и доли успешных и быстрых запросов по маршрутам в скользящих окнах.
+// Из них вычисляется оставшийся бюджет ошибок. Метрики отдаются через
+// интерфейс prometheus.Collector, отчет - через Handler
+type SLOTracker struct {
+	cfg      config.SLOConfig
+	bucket   time.Duration
+	size     int
+	duration *prometheus.HistogramVec
+	now      func() time.Time
+
+	mu     sync.Mutex
+	routes map[sloRoute]*sloWindow
+
+	attainmentDesc *prometheus.Desc
+	budgetDesc     *prometheus.Desc
+}
+
+type sloRoute struct {
+	method string
+	path   string
+}
+
+// sloWindow - кольцевой буфер корзин фиксированной длительности
+type sloWindow struct {
+	buckets []sloBucket
+}
+
+type sloBucket struct {
+	index  int64 // номер корзины от начала эпохи
+	total  uint64
+	errors uint64
+	slow   uint64
+}
+
+// NewSLOTracker создает трекер SLO. Метрики нужно зарегистрировать отдельно:
+//
+//	prometheus.Register(tracker)
+func NewSLOTracker(cfg config.SLOConfig) *SLOTracker {
+	windows := append([]time.Duration(nil), cfg.Windows...)
+	sort.Slice(windows, func(i, j int) bool { return windows[i] < windows[j] })
+	cfg.Windows = windows
+
+	bucket := time.Second
+	size := 1
+	if len(windows) > 0 {
+		bucket = max(windows[0]/sloBucketsPerWindow, time.Second)
+		size = int(windows[len(windows)-1]/bucket) + 1
+	}
+
+	buckets := make([]float64, 0, len(sloBucketFactors))
+	for _, factor := range sloBucketFactors {
+		buckets = append(buckets, cfg.LatencyThreshold.Seconds()*factor)
+	}
+
+	return &SLOTracker{
+		cfg:    cfg,
+		bucket: bucket,
+		size:   size,
+		duration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "http_sli_request_duration_seconds",
+				Help:    "Длительность HTTP запроса в секундах с корзинами вокруг порога SLO",
+				Buckets: buckets,
+			},
+			[]string{"method", "path", "result"},
+		),
+		now:    time.Now,
+		routes: make(map[sloRoute]*sloWindow),
+		attainmentDesc: prometheus.NewDesc(
+			"slo_attainment_ratio",
+			"Доля успешных (availability) или быстрых (latency) запросов в скользящем окне",
+			[]string{"method", "path", "slo", "window"}, nil,
+		),
+		budgetDesc: prometheus.NewDesc(
+			"slo_error_budget_remaining_ratio",
+			"Оставшаяся доля бюджета ошибок в скользящем окне; меньше 0 - SLO нарушен",
+			[]string{"method", "path", "slo", "window"}, nil,
+		),
+	}
+}
+
+// Observe учитывает завершенный запрос. Ошибкой считаются ответы 5xx
+func (t *SLOTracker) Observe(method, path string, status int, duration time.Duration) {
+	failed := status >= http.StatusInternalServerError
+	result := sloResultSuccess
+	if failed {
+		result = sloResultError
+	}
+	t.duration.WithLabelValues(method, path, result).Observe(duration.Seconds())
+
+	index := t.now().UnixNano() / int64(t.bucket)
+	key := sloRoute{method: method, path: path}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	window, ok := t.routes[key]
+	if !ok {
+		window = &sloWindow{buckets: make([]sloBucket, t.size)}
+		t.routes[key] = window
+	}
+
+	b := &window.buckets[index%int64(t.size)]
+	if b.index != index {
+		*b = sloBucket{index: index}
+	}
+	b.total++
+	if failed {
+		b.errors++
+	}
+	if duration > t.cfg.LatencyThreshold {
+		b.slow++
+	}
+}
+
+// SLOObjectives - цели SLO
+type SLOObjectives struct {
+	LatencyThreshold   string  `json:"latencyThreshold"`
+	LatencyTarget      float64 `json:"latencyTarget"`
+	AvailabilityTarget float64 `json:"availabilityTarget"`
+}
+
+// SLOWindowReport - выполнение SLO маршрута в одном окне
+type SLOWindowReport struct {
+	Window                      string  `json:"window"`
+	Requests                    uint64  `json:"requests"`
+	Errors                      uint64  `json:"errors"`
+	Slow                        uint64  `json:"slow"`
+	Availability                float64 `json:"availability"`
+	LatencyAttainment           float64 `json:"latencyAttainment"`
+	AvailabilityBudgetRemaining float64 `json:"availabilityBudgetRemaining"`
+	LatencyBudgetRemaining      float64 `json:"latencyBudgetRemaining"`
+	Met                         bool    `json:"met"`
+}
+
+// SLORouteReport - выполнение SLO маршрута во всех окнах
+type SLORouteReport struct {
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Windows []SLOWindowReport `json:"windows"`
+}
+
+// SLOReport - сводка по всем маршрутам
+type SLOReport struct {
+	Objectives SLOObjectives    `json:"objectives"`
+	Routes     []SLORouteReport `json:"routes"`
+}
+
+// Report возвращает выполнение SLO по маршрутам, отсортированным по пути и методу
+func (t *SLOTracker) Report() SLOReport {
+	report := SLOReport{
+		Objectives: SLOObjectives{
+			LatencyThreshold:   t.cfg.LatencyThreshold.String(),
+			LatencyTarget:      t.cfg.LatencyTarget,
+			AvailabilityTarget: t.cfg.AvailabilityTarget,
+		},
+		Routes: []SLORouteReport{},
+	}
+
+	current := t.now().UnixNano() / int64(t.bucket)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for key, window := range t.routes {
+		route := SLORouteReport{Method: key.method, Path: key.path}
+		for _, duration := range t.cfg.Windows {
+			route.Windows = append(route.Windows, t.windowReport(window, current, duration))
+		}
+		report.Routes = append(report.Routes, route)
+	}
+
+	sort.Slice(report.Routes, func(i, j int) bool {
+		if report.Routes[i].Path != report.Routes[j].Path {
+			return report.Routes[i].Path < report.Routes[j].Path
+		}
+		return report.Routes[i].Method < report.Routes[j].Method
+	})
+
+	return report
+}
+
+// windowReport суммирует корзины, попадающие в окно. Вызывается под t.mu
+func (t *SLOTracker) windowReport(window *sloWindow, current int64, duration time.Duration) SLOWindowReport {
+	count := int64(duration / t.bucket)
+	result := SLOWindowReport{Window: formatWindow(duration)}
+
+	for _, b := range window.buckets {
+		if b.index > current-count && b.index <= current {
+			result.Requests += b.total
+			result.Errors += b.errors
+			result.Slow += b.slow
+		}
+	}
+
+	result.Availability = attainment(result.Requests, result.Errors)
+	result.LatencyAttainment = attainment(result.Requests, result.Slow)
+	result.AvailabilityBudgetRemaining = budgetRemaining(result.Availability, t.cfg.AvailabilityTarget)
+	result.LatencyBudgetRemaining = budgetRemaining(result.LatencyAttainment, t.cfg.LatencyTarget)
+	result.Met = result.Availability >= t.cfg.AvailabilityTarget &&
+		result.LatencyAttainment >= t.cfg.LatencyTarget
+
+	return result
+}
+
+// formatWindow убирает нулевые хвосты: 24h0m0s -> 24h, 5m0s -> 5m
+func formatWindow(duration time.Duration) string {
+	value := duration.String()
+	if strings.HasSuffix(value, "m0s") {
+		value = strings.TrimSuffix(value, "0s")
+	}
+	if strings.HasSuffix(value, "h0m") {
+		value = strings.TrimSuffix(value, "0m")
+	}
+	return value
+}
+
+// attainment - доля хороших запросов; без запросов SLO считается выполненным
+func attainment(total, bad uint64) float64 {
+	if total == 0 {
+		return 1
+	}
+	return float64(total-bad) / float64(total)
+}
+
+// budgetRemaining - доля бюджета ошибок (1 - target), которая еще не израсходована
+func budgetRemaining(attained, target float64) float64 {
+	budget := 1 - target
+	if budget <= 0 {
+		if attained >= 1 {
+			return 1
+		}
+		return 0
+	}
+	return 1 - (1-attained)/budget
+}
+
+// Handler отдает сводку SLO в JSON
+func (t *SLOTracker) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(t.Report())
+	})
+}
+
+// Describe реализует prometheus.Collector
+func (t *SLOTracker) Describe(ch chan<- *prometheus.Desc) {
+	t.duration.Describe(ch)
+	ch <- t.attainmentDesc
+	ch <- t.budgetDesc
+}
+
+// Collect реализует prometheus.Collector. Значения окон считаются в момент сбора
+func (t *SLOTracker) Collect(ch chan<- prometheus.Metric) {
+	t.duration.Collect(ch)
+
+	for _, route := range t.Report().Routes {
+		for _, window := range route.Windows {
+			ch <- prometheus.MustNewConstMetric(t.attainmentDesc, prometheus.GaugeValue,
+				window.Availability, route.Method, route.Path, SLOAvailability, window.Window)
+			ch <- prometheus.MustNewConstMetric(t.attainmentDesc, prometheus.GaugeValue,
+				window.LatencyAttainment, route.Method, route.Path, SLOLatency, window.Window)
+			ch <- prometheus.MustNewConstMetric(t.budgetDesc, prometheus.GaugeValue,
+				window.AvailabilityBudgetRemaining, route.Method, route.Path, SLOAvailability, window.Window)
+			ch <- prometheus.MustNewConstMetric(t.budgetDesc, prometheus.GaugeValue,
+				window.LatencyBudgetRemaining, route.Method, route.Path, SLOLatency, window.Window)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSLOTracker(now *time.Time) *SLOTracker {
+	tracker := NewSLOTracker(config.SLOConfig{
+		LatencyThreshold:   100 * time.Millisecond,
+		LatencyTarget:      0.9,
+		AvailabilityTarget: 0.99,
+		Windows:            []time.Duration{time.Hour, 5 * time.Minute},
+	})
+	tracker.now = func() time.Time { return *now }
+	return tracker
+}
+
+func TestSLOTracker_SlidingWindows(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tracker := newTestSLOTracker(&now)
+
+	// 10 минут назад: ошибка и медленный запрос
+	now = now.Add(-10 * time.Minute)
+	tracker.Observe(http.MethodGet, "/pvz", http.StatusInternalServerError, 10*time.Millisecond)
+	tracker.Observe(http.MethodGet, "/pvz", http.StatusOK, 300*time.Millisecond)
+
+	// сейчас: 10 быстрых успешных запросов и один 4xx, который ошибкой не считается
+	now = now.Add(10 * time.Minute)
+	for i := 0; i < 10; i++ {
+		tracker.Observe(http.MethodGet, "/pvz", http.StatusOK, 20*time.Millisecond)
+	}
+	tracker.Observe(http.MethodGet, "/pvz", http.StatusBadRequest, 20*time.Millisecond)
+
+	report := tracker.Report()
+	require.Len(t, report.Routes, 1)
+	route := report.Routes[0]
+	require.Len(t, route.Windows, 2)
+
+	short, long := route.Windows[0], route.Windows[1]
+	assert.Equal(t, "5m", short.Window)
+	assert.Equal(t, uint64(11), short.Requests)
+	assert.Equal(t, uint64(0), short.Errors)
+	assert.Equal(t, 1.0, short.Availability)
+	assert.Equal(t, 1.0, short.AvailabilityBudgetRemaining)
+	assert.True(t, short.Met)
+
+	assert.Equal(t, "1h", long.Window)
+	assert.Equal(t, uint64(13), long.Requests)
+	assert.Equal(t, uint64(1), long.Errors)
+	assert.Equal(t, uint64(1), long.Slow)
+	assert.InDelta(t, 12.0/13, long.Availability, 1e-9)
+	// доля ошибок 1/13 при бюджете 1% - бюджет исчерпан с избытком
+	assert.Less(t, long.AvailabilityBudgetRemaining, 0.0)
+	// доля медленных 1/13 при бюджете 10% - израсходовано ~77%
+	assert.InDelta(t, 1-(1.0/13)/0.1, long.LatencyBudgetRemaining, 1e-9)
+	assert.False(t, long.Met)
+
+	// через два часа все корзины выходят из окон
+	now = now.Add(2 * time.Hour)
+	report = tracker.Report()
+	assert.Equal(t, uint64(0), report.Routes[0].Windows[1].Requests)
+	assert.Equal(t, 1.0, report.Routes[0].Windows[1].Availability)
+}
+
+func TestSLOTracker_Collector(t *testing.T) {
+	// цели с точным представлением во float64, чтобы сравнивать значения как есть
+	tracker := NewSLOTracker(config.SLOConfig{
+		LatencyThreshold:   100 * time.Millisecond,
+		LatencyTarget:      0.75,
+		AvailabilityTarget: 0.5,
+		Windows:            []time.Duration{5 * time.Minute, time.Hour},
+	})
+	tracker.Observe(http.MethodPost, "/pvz", http.StatusOK, 50*time.Millisecond)
+	tracker.Observe(http.MethodPost, "/pvz", http.StatusServiceUnavailable, 150*time.Millisecond)
+
+	registry := prometheus.NewPedanticRegistry()
+	require.NoError(t, registry.Register(tracker))
+
+	expected := `
+# HELP slo_error_budget_remaining_ratio Оставшаяся доля бюджета ошибок в скользящем окне; меньше 0 - SLO нарушен
+# TYPE slo_error_budget_remaining_ratio gauge
+slo_error_budget_remaining_ratio{method="POST",path="/pvz",slo="availability",window="1h"} 0
+slo_error_budget_remaining_ratio{method="POST",path="/pvz",slo="availability",window="5m"} 0
+slo_error_budget_remaining_ratio{method="POST",path="/pvz",slo="latency",window="1h"} -1
+slo_error_budget_remaining_ratio{method="POST",path="/pvz",slo="latency",window="5m"} -1
+`
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "slo_error_budget_remaining_ratio"))
+
+	count, err := testutil.GatherAndCount(registry, "http_sli_request_duration_seconds")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestSLOTracker_Handler(t *testing.T) {
+	now := time.Now()
+	tracker := newTestSLOTracker(&now)
+	tracker.Observe(http.MethodGet, "/pvz", http.StatusOK, time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	tracker.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slo", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var report SLOReport
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, "100ms", report.Objectives.LatencyThreshold)
+	assert.Equal(t, 0.99, report.Objectives.AvailabilityTarget)
+	require.Len(t, report.Routes, 1)
+	assert.Equal(t, "/pvz", report.Routes[0].Path)
+}
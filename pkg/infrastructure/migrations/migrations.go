@@ -1,75 +1,144 @@
 package migrations
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"path/filepath"
+	"time"
 
-	"github.com/dkumancev/avito-pvz/config"
+	schema "github.com/dkumancev/avito-pvz/migrations"
 	"github.com/pressly/goose/v3"
 )
 
+// migrationsDir - корень встроенной файловой системы с миграциями
+const migrationsDir = "."
+
+// lockKey - ключ advisory lock, под которым реплики выполняют миграции по очереди
+const lockKey int64 = 0x70767a5f6d6967 // "pvz_mig"
+
+// lockRetryInterval - пауза между попытками взять блокировку
+const lockRetryInterval = 500 * time.Millisecond
+
+// ErrSchemaBehind возвращается, если схема БД старше той, с которой собран сервис
+var ErrSchemaBehind = errors.New("схема БД отстает от версии сервиса")
+
+// Runner выполняет миграции, встроенные в бинарник
 type Runner struct {
-	db  *sql.DB
-	cfg *config.Config
+	db     *sql.DB
+	logger *slog.Logger
 }
 
-func NewRunner(db *sql.DB, cfg *config.Config) *Runner {
+func NewRunner(db *sql.DB, logger *slog.Logger) *Runner {
 	return &Runner{
-		db:  db,
-		cfg: cfg,
+		db:     db,
+		logger: logger,
 	}
 }
 
-// RunMigrationsUp выполняет миграции базы данных вверх до последней версии
-func (r *Runner) RunMigrationsUp() error {
-	log.Println("Запуск миграций базы данных...")
+// prepare настраивает goose на встроенные миграции. Настройки goose глобальные,
+// поэтому задаются перед каждой операцией
+func prepare() error {
+	goose.SetBaseFS(schema.FS)
 	if err := goose.SetDialect("postgres"); err != nil {
 		return fmt.Errorf("ошибка установки диалекта: %w", err)
 	}
+	return nil
+}
 
-	if err := goose.Up(r.db, r.cfg.App.MigrationsDir); err != nil {
-		return fmt.Errorf("ошибка выполнения миграций up: %w", err)
-	}
+// RunMigrationsUp выполняет миграции базы данных вверх до последней версии.
+// Миграции выполняются под advisory lock: если их одновременно запускают
+// несколько реплик, остальные ждут и затем видят актуальную схему
+func (r *Runner) RunMigrationsUp(ctx context.Context) error {
+	return r.withLock(ctx, func() error {
+		r.logger.InfoContext(ctx, "Запуск миграций базы данных")
+		if err := prepare(); err != nil {
+			return err
+		}
 
-	log.Println("Миграции успешно выполнены")
-	return nil
+		if err := goose.UpContext(ctx, r.db, migrationsDir); err != nil {
+			return fmt.Errorf("ошибка выполнения миграций up: %w", err)
+		}
+
+		r.logger.InfoContext(ctx, "Миграции успешно выполнены")
+		return nil
+	})
 }
 
 // RunMigrationsDown откатывает все миграции
-func (r *Runner) RunMigrationsDown() error {
-	log.Println("Откат всех миграций базы данных...")
-	if err := goose.SetDialect("postgres"); err != nil {
-		return fmt.Errorf("ошибка установки диалекта: %w", err)
-	}
+func (r *Runner) RunMigrationsDown(ctx context.Context) error {
+	return r.withLock(ctx, func() error {
+		r.logger.InfoContext(ctx, "Откат всех миграций базы данных")
+		if err := prepare(); err != nil {
+			return err
+		}
 
-	if err := goose.Reset(r.db, r.cfg.App.MigrationsDir); err != nil {
-		return fmt.Errorf("ошибка отката миграций: %w", err)
-	}
+		if err := goose.ResetContext(ctx, r.db, migrationsDir); err != nil {
+			return fmt.Errorf("ошибка отката миграций: %w", err)
+		}
 
-	log.Println("Откат миграций успешно выполнен")
-	return nil
+		r.logger.InfoContext(ctx, "Откат миграций успешно выполнен")
+		return nil
+	})
+}
+
+// RollbackMigration откатывает последнюю примененную миграцию
+func (r *Runner) RollbackMigration(ctx context.Context) error {
+	return r.withLock(ctx, func() error {
+		r.logger.InfoContext(ctx, "Откат последней миграции базы данных")
+		if err := prepare(); err != nil {
+			return err
+		}
+
+		if err := goose.DownContext(ctx, r.db, migrationsDir); err != nil {
+			return fmt.Errorf("ошибка отката миграции: %w", err)
+		}
+
+		r.logger.InfoContext(ctx, "Откат миграции успешно выполнен")
+		return nil
+	})
+}
+
+// RollbackToVersion откатывает миграции новее указанной версии. Сама версия остается примененной
+func (r *Runner) RollbackToVersion(ctx context.Context, version int64) error {
+	return r.withLock(ctx, func() error {
+		r.logger.InfoContext(ctx, "Откат базы данных до версии", "version", version)
+		if err := prepare(); err != nil {
+			return err
+		}
+
+		if err := goose.DownToContext(ctx, r.db, migrationsDir, version); err != nil {
+			return fmt.Errorf("ошибка отката до версии %d: %w", version, err)
+		}
+
+		r.logger.InfoContext(ctx, "Откат до версии успешно выполнен", "version", version)
+		return nil
+	})
 }
 
 // RunMigrationToVersion выполняет миграции до указанной версии
-func (r *Runner) RunMigrationToVersion(version int64) error {
-	log.Printf("Миграция базы данных до версии %d...\n", version)
-	if err := goose.SetDialect("postgres"); err != nil {
-		return fmt.Errorf("ошибка установки диалекта: %w", err)
-	}
+func (r *Runner) RunMigrationToVersion(ctx context.Context, version int64) error {
+	return r.withLock(ctx, func() error {
+		r.logger.InfoContext(ctx, "Миграция базы данных до версии", "version", version)
+		if err := prepare(); err != nil {
+			return err
+		}
 
-	if err := goose.UpTo(r.db, r.cfg.App.MigrationsDir, version); err != nil {
-		return fmt.Errorf("ошибка миграции до версии %d: %w", version, err)
-	}
+		if err := goose.UpToContext(ctx, r.db, migrationsDir, version); err != nil {
+			return fmt.Errorf("ошибка миграции до версии %d: %w", version, err)
+		}
 
-	log.Printf("Миграция до версии %d успешно выполнена\n", version)
-	return nil
+		r.logger.InfoContext(ctx, "Миграция до версии успешно выполнена", "version", version)
+		return nil
+	})
 }
 
 // GetCurrentVersion возвращает текущую версию миграции
 func (r *Runner) GetCurrentVersion() (int64, error) {
-	if err := goose.SetDialect("postgres"); err != nil {
-		return 0, fmt.Errorf("ошибка установки диалекта: %w", err)
+	if err := prepare(); err != nil {
+		return 0, err
 	}
 
 	// В goose v3 для программного получения текущей версии
@@ -89,3 +158,172 @@ func (r *Runner) GetCurrentVersion() (int64, error) {
 
 	return version, nil
 }
+
+// ExpectedVersion возвращает версию последней встроенной миграции,
+// т.е. версию схемы, с которой должен работать сервис
+func (r *Runner) ExpectedVersion() (int64, error) {
+	return ExpectedVersion()
+}
+
+// ExpectedVersion возвращает версию последней встроенной миграции
+func ExpectedVersion() (int64, error) {
+	if err := prepare(); err != nil {
+		return 0, err
+	}
+
+	migrations, err := goose.CollectMigrations(migrationsDir, 0, goose.MaxVersion)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка чтения миграций: %w", err)
+	}
+
+	last, err := migrations.Last()
+	if err != nil {
+		return 0, fmt.Errorf("ошибка получения последней миграции: %w", err)
+	}
+
+	return last.Version, nil
+}
+
+// MigrationStatus - состояние встроенной миграции в БД
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Status возвращает встроенные миграции по возрастанию версии с отметкой,
+// применены ли они к БД
+func (r *Runner) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if err := prepare(); err != nil {
+		return nil, err
+	}
+
+	migrations, err := goose.CollectMigrations(migrationsDir, 0, goose.MaxVersion)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения миграций: %w", err)
+	}
+
+	// таблица версий создается goose при первом запуске миграций
+	var exists bool
+	err = r.db.QueryRowContext(ctx, "SELECT to_regclass($1) IS NOT NULL", goose.TableName()).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка проверки таблицы версий: %w", err)
+	}
+
+	applied := make(map[int64]time.Time)
+	if exists {
+		query := fmt.Sprintf("SELECT version_id, is_applied, tstamp FROM %s ORDER BY id", goose.TableName())
+		rows, err := r.db.QueryContext(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка получения примененных миграций: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var (
+				version   int64
+				isApplied bool
+				appliedAt time.Time
+			)
+			err = rows.Scan(&version, &isApplied, &appliedAt)
+			if err != nil {
+				return nil, fmt.Errorf("ошибка чтения версии миграции: %w", err)
+			}
+			if isApplied {
+				applied[version] = appliedAt
+			} else {
+				delete(applied, version)
+			}
+		}
+		if err = rows.Err(); err != nil {
+			return nil, fmt.Errorf("ошибка чтения версий миграций: %w", err)
+		}
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		appliedAt, ok := applied[migration.Version]
+		statuses = append(statuses, MigrationStatus{
+			Version:   migration.Version,
+			Name:      filepath.Base(migration.Source),
+			Applied:   ok,
+			AppliedAt: appliedAt,
+		})
+	}
+
+	return statuses, nil
+}
+
+// CheckVersion проверяет, что схема БД не старше встроенных миграций.
+// Более новая схема допускается: при выкатке старые реплики работают рядом с
+// уже обновленной БД
+func (r *Runner) CheckVersion() error {
+	expected, err := r.ExpectedVersion()
+	if err != nil {
+		return err
+	}
+
+	current, err := r.GetCurrentVersion()
+	if err != nil {
+		return err
+	}
+
+	err = compareVersions(current, expected)
+	if err != nil {
+		return err
+	}
+
+	if current > expected {
+		r.logger.Warn("Схема БД новее версии сервиса", "current", current, "expected", expected)
+	}
+	return nil
+}
+
+func compareVersions(current, expected int64) error {
+	if current < expected {
+		return fmt.Errorf("%w: версия схемы %d, ожидается %d", ErrSchemaBehind, current, expected)
+	}
+	return nil
+}
+
+// withLock выполняет fn под сессионным advisory lock. Блокировка берется на
+// отдельном соединении и ожидается, пока не отменен ctx
+func (r *Runner) withLock(ctx context.Context, fn func() error) error {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("ошибка получения соединения для блокировки миграций: %w", err)
+	}
+	defer conn.Close()
+
+	for waiting := false; ; waiting = true {
+		var locked bool
+		err = conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", lockKey).Scan(&locked)
+		if err != nil {
+			return fmt.Errorf("ошибка получения блокировки миграций: %w", err)
+		}
+		if locked {
+			break
+		}
+
+		if !waiting {
+			r.logger.InfoContext(ctx, "Миграции выполняет другой экземпляр, ожидание блокировки")
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("не дождались блокировки миграций: %w", ctx.Err())
+		case <-time.After(lockRetryInterval):
+		}
+	}
+
+	defer func() {
+		// контекст может быть уже отменен, а блокировку нужно снять в любом случае
+		_, unlockErr := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockKey)
+		if unlockErr != nil {
+			r.logger.Error("Ошибка снятия блокировки миграций", "error", unlockErr)
+		}
+	}()
+
+	return fn()
+}
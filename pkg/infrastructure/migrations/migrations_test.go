@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"errors"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	schema "github.com/dkumancev/avito-pvz/migrations"
+)
+
+func TestExpectedVersion_EmbeddedMigrations(t *testing.T) {
+	files, err := fs.Glob(schema.FS, "*.sql")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("Миграции не встроены в бинарник: %v", err)
+	}
+	sort.Strings(files)
+
+	prefix, _, _ := strings.Cut(files[len(files)-1], "_")
+	want, err := strconv.ParseInt(prefix, 10, 64)
+	if err != nil {
+		t.Fatalf("Неверное имя файла миграции %s: %v", files[len(files)-1], err)
+	}
+
+	got, err := ExpectedVersion()
+	if err != nil {
+		t.Fatalf("Ошибка получения ожидаемой версии: %v", err)
+	}
+	if got != want {
+		t.Errorf("Ожидалась версия %d, получено %d", want, got)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	if err := compareVersions(5, 6); !errors.Is(err, ErrSchemaBehind) {
+		t.Errorf("Для отстающей схемы ожидалась ErrSchemaBehind, получено %v", err)
+	}
+	if err := compareVersions(6, 6); err != nil {
+		t.Errorf("Для актуальной схемы ошибки быть не должно: %v", err)
+	}
+	if err := compareVersions(7, 6); err != nil {
+		t.Errorf("Более новая схема допускается: %v", err)
+	}
+}
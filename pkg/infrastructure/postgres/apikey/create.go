@@ -0,0 +1,49 @@
+package apikey
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
+)
+
+// Create сохраняет новый API-ключ
+func (r *Repository) Create(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error) {
+	defer db.ObserveQuery(ctx, "apikey", "Create")()
+
+	if key.ID == "" {
+		key.ID = uuid.New().String()
+	}
+
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now()
+	}
+
+	model := &models.APIKeyModel{}
+	model.FromEntity(key)
+
+	query := `
+		INSERT INTO api_keys (id, name, prefix, key_hash, scopes, created_by, created_at, expires_at)
+		VALUES (:id, :name, :prefix, :key_hash, :scopes, :created_by, :created_at, :expires_at)
+		RETURNING id, name, prefix, key_hash, scopes, created_by, created_at, expires_at, last_used_at, revoked_at
+	`
+
+	stmt, err := db.Conn(ctx, r.db).PrepareNamedContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка подготовки запроса: %w", err)
+	}
+	defer stmt.Close()
+
+	err = stmt.QueryRowxContext(ctx, model).StructScan(model)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания API-ключа: %w", err)
+	}
+
+	result := model.ToEntity()
+	return result, nil
+}
@@ -0,0 +1,36 @@
+package apikey
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
+)
+
+// GetByHash получает API-ключ по хэшу секрета
+func (r *Repository) GetByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	defer db.ObserveQuery(ctx, "apikey", "GetByHash")()
+
+	query := `
+		SELECT id, name, prefix, key_hash, scopes, created_by, created_at, expires_at, last_used_at, revoked_at
+		FROM api_keys
+		WHERE key_hash = $1
+	`
+
+	model := &models.APIKeyModel{}
+	err := db.Conn(ctx, r.db).GetContext(ctx, model, query, keyHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("API-ключ не найден: %w", repositories.ErrNotFound)
+		}
+		return nil, fmt.Errorf("ошибка получения API-ключа: %w", err)
+	}
+
+	result := model.ToEntity()
+	return result, nil
+}
@@ -0,0 +1,35 @@
+package apikey
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
+)
+
+// GetByID получает API-ключ по идентификатору
+func (r *Repository) GetByID(ctx context.Context, id string) (*domain.APIKey, error) {
+	defer db.ObserveQuery(ctx, "apikey", "GetByID")()
+
+	query := `
+		SELECT id, name, prefix, key_hash, scopes, created_by, created_at, expires_at, last_used_at, revoked_at
+		FROM api_keys
+		WHERE id = $1
+	`
+
+	model := &models.APIKeyModel{}
+	err := db.Conn(ctx, r.db).GetContext(ctx, model, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("API-ключ с ID %s не найден", id)
+		}
+		return nil, fmt.Errorf("ошибка получения API-ключа: %w", err)
+	}
+
+	result := model.ToEntity()
+	return result, nil
+}
@@ -0,0 +1,34 @@
+package apikey
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
+)
+
+// List возвращает все API-ключи, начиная с последних созданных
+func (r *Repository) List(ctx context.Context) ([]*domain.APIKey, error) {
+	defer db.ObserveQuery(ctx, "apikey", "List")()
+
+	query := `
+		SELECT id, name, prefix, key_hash, scopes, created_by, created_at, expires_at, last_used_at, revoked_at
+		FROM api_keys
+		ORDER BY created_at DESC
+	`
+
+	var keyModels []models.APIKeyModel
+	err := db.Conn(ctx, r.db).SelectContext(ctx, &keyModels, query)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении списка API-ключей: %w", err)
+	}
+
+	result := make([]*domain.APIKey, 0, len(keyModels))
+	for _, model := range keyModels {
+		result = append(result, model.ToEntity())
+	}
+
+	return result, nil
+}
@@ -0,0 +1,53 @@
+package apikey
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
+)
+
+// Update обновляет изменяемые поля API-ключа (название, права, сроки)
+func (r *Repository) Update(ctx context.Context, key *domain.APIKey) error {
+	defer db.ObserveQuery(ctx, "apikey", "Update")()
+
+	model := &models.APIKeyModel{}
+	model.FromEntity(key)
+
+	query := `
+		UPDATE api_keys
+		SET name = :name, scopes = :scopes, expires_at = :expires_at, revoked_at = :revoked_at
+		WHERE id = :id
+	`
+
+	result, err := db.Conn(ctx, r.db).NamedExecContext(ctx, query, model)
+	if err != nil {
+		return fmt.Errorf("ошибка при обновлении API-ключа: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка получения количества обновленных записей: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("API-ключ с ID %s не найден", key.ID)
+	}
+
+	return nil
+}
+
+// UpdateLastUsed фиксирует время последнего использования ключа
+func (r *Repository) UpdateLastUsed(ctx context.Context, id string, usedAt time.Time) error {
+	defer db.ObserveQuery(ctx, "apikey", "UpdateLastUsed")()
+
+	_, err := db.Conn(ctx, r.db).ExecContext(ctx, `UPDATE api_keys SET last_used_at = $1 WHERE id = $2`, usedAt, id)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления времени использования API-ключа: %w", err)
+	}
+
+	return nil
+}
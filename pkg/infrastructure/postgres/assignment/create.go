@@ -0,0 +1,44 @@
+package assignment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
+)
+
+// Create закрепляет сотрудника за ПВЗ. Повторное закрепление не считается ошибкой
+func (r *Repository) Create(ctx context.Context, assignment *domain.Assignment) (*domain.Assignment, error) {
+	defer db.ObserveQuery(ctx, "assignment", "Create")()
+
+	model := &models.AssignmentModel{}
+	model.FromEntity(assignment)
+
+	if model.AssignedAt.IsZero() {
+		model.AssignedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO employee_pvz (user_id, pvz_id, assigned_at)
+		VALUES (:user_id, :pvz_id, :assigned_at)
+		ON CONFLICT (user_id, pvz_id) DO UPDATE SET user_id = EXCLUDED.user_id
+		RETURNING user_id, pvz_id, assigned_at
+	`
+
+	stmt, err := db.Conn(ctx, r.db).PrepareNamedContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка подготовки запроса: %w", err)
+	}
+	defer stmt.Close()
+
+	err = stmt.QueryRowxContext(ctx, model).StructScan(model)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка закрепления сотрудника за ПВЗ: %w", err)
+	}
+
+	result := model.ToEntity()
+	return result, nil
+}
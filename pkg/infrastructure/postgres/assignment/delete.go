@@ -0,0 +1,31 @@
+package assignment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+)
+
+// Delete открепляет сотрудника от ПВЗ
+func (r *Repository) Delete(ctx context.Context, userID, pvzID string) error {
+	defer db.ObserveQuery(ctx, "assignment", "Delete")()
+
+	query := `DELETE FROM employee_pvz WHERE user_id = $1 AND pvz_id = $2`
+
+	result, err := db.Conn(ctx, r.db).ExecContext(ctx, query, userID, pvzID)
+	if err != nil {
+		return fmt.Errorf("ошибка при откреплении сотрудника от ПВЗ: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка получения количества удаленных записей: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("сотрудник %s не закреплен за ПВЗ %s", userID, pvzID)
+	}
+
+	return nil
+}
@@ -0,0 +1,21 @@
+package assignment
+
+import (
+	"context"
+
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+)
+
+// Exists проверяет, закреплен ли сотрудник за ПВЗ
+func (r *Repository) Exists(ctx context.Context, userID, pvzID string) (bool, error) {
+	defer db.ObserveQuery(ctx, "assignment", "Exists")()
+
+	query := `
+		SELECT EXISTS(SELECT 1 FROM employee_pvz WHERE user_id = $1 AND pvz_id = $2)
+	`
+
+	var exists bool
+	err := db.Conn(ctx, r.db).QueryRowxContext(ctx, query, userID, pvzID).Scan(&exists)
+
+	return exists, err
+}
@@ -0,0 +1,35 @@
+package assignment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
+)
+
+// GetByPVZID получает список сотрудников, закрепленных за ПВЗ
+func (r *Repository) GetByPVZID(ctx context.Context, pvzID string) ([]*domain.Assignment, error) {
+	defer db.ObserveQuery(ctx, "assignment", "GetByPVZID")()
+
+	query := `
+		SELECT user_id, pvz_id, assigned_at
+		FROM employee_pvz
+		WHERE pvz_id = $1
+		ORDER BY assigned_at
+	`
+
+	var assignmentModels []models.AssignmentModel
+	err := db.Conn(ctx, r.db).SelectContext(ctx, &assignmentModels, query, pvzID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении сотрудников ПВЗ: %w", err)
+	}
+
+	result := make([]*domain.Assignment, 0, len(assignmentModels))
+	for _, model := range assignmentModels {
+		result = append(result, model.ToEntity())
+	}
+
+	return result, nil
+}
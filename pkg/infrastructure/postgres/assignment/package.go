@@ -0,0 +1,11 @@
+package assignment
+
+import (
+	"github.com/jmoiron/sqlx"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
+)
+
+func New(db *sqlx.DB) repositories.AssignmentRepository {
+	return NewRepository(db)
+}
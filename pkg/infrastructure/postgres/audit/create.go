@@ -0,0 +1,41 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
+)
+
+// Create добавляет запись в журнал аудита. Вызывается в транзакции изменения
+func (r *Repository) Create(ctx context.Context, entry *domain.AuditEntry) error {
+	defer db.ObserveQuery(ctx, "audit", "Create")()
+
+	if entry.ID == "" {
+		entry.ID = uuid.New().String()
+	}
+
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	model := &models.AuditLogModel{}
+	model.FromEntity(entry)
+
+	query := `
+		INSERT INTO audit_log (id, actor_id, actor_role, action, entity_type, entity_id, before, after, created_at)
+		VALUES (:id, :actor_id, :actor_role, :action, :entity_type, :entity_id, :before, :after, :created_at)
+	`
+
+	_, err := db.Conn(ctx, r.db).NamedExecContext(ctx, query, model)
+	if err != nil {
+		return fmt.Errorf("ошибка записи в журнал аудита: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
+)
+
+// List возвращает записи журнала аудита по фильтру, начиная с последних
+func (r *Repository) List(ctx context.Context, filter repositories.AuditFilter) ([]*domain.AuditEntry, error) {
+	defer db.ObserveQuery(ctx, "audit", "List")()
+
+	query := `
+		SELECT id, actor_id, actor_role, action, entity_type, entity_id, before, after, created_at
+		FROM audit_log
+		WHERE 1=1
+	`
+
+	var args []interface{}
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		query += fmt.Sprintf(" AND "+condition, len(args))
+	}
+
+	if filter.ActorID != "" {
+		addCondition("actor_id = $%d", filter.ActorID)
+	}
+	if filter.EntityType != "" {
+		addCondition("entity_type = $%d", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		addCondition("entity_id = $%d", filter.EntityID)
+	}
+	if filter.From != nil {
+		addCondition("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		addCondition("created_at <= $%d", *filter.To)
+	}
+
+	offset := (filter.Page - 1) * filter.Limit
+	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, offset)
+
+	var entryModels []models.AuditLogModel
+	err := db.Conn(ctx, r.db).SelectContext(ctx, &entryModels, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении журнала аудита: %w", err)
+	}
+
+	result := make([]*domain.AuditEntry, 0, len(entryModels))
+	for _, model := range entryModels {
+		result = append(result, model.ToEntity())
+	}
+
+	return result, nil
+}
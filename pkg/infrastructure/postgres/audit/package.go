@@ -0,0 +1,11 @@
+package audit
+
+import (
+	"github.com/jmoiron/sqlx"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
+)
+
+func New(db *sqlx.DB) repositories.AuditRepository {
+	return NewRepository(db)
+}
@@ -1,27 +1,78 @@
 package db
 
 import (
+	"context"
+	"database/sql/driver"
+	"errors"
 	"fmt"
+	"log/slog"
+	"time"
 
+	"github.com/XSAM/otelsql"
 	"github.com/dkumancev/avito-pvz/config"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
 func New(cfg config.PostgresConfig) (*sqlx.DB, error) {
-	db, err := sqlx.Connect("postgres", cfg.ConnURI())
+	// каждый запрос репозиториев оборачивается в span OpenTelemetry
+	sqlDB, err := otelsql.Open("postgres", cfg.ConnURI(),
+		otelsql.WithAttributes(semconv.DBSystemPostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			OmitConnResetSession: true,
+			OmitRows:             true,
+		}),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("error connecting to PostgreSQL: %w", err)
 	}
+	db := sqlx.NewDb(sqlDB, "postgres")
 
 	db.SetMaxOpenConns(cfg.MaxOpenConns)
 	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 	db.SetMaxIdleConns(cfg.MaxIdleConns)
 	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 
-	if err = db.Ping(); err != nil {
+	err = connect(db, cfg)
+	if err != nil {
+		db.Close()
 		return nil, fmt.Errorf("error checking connection to PostgreSQL: %w", err)
 	}
 
 	return db, nil
 }
+
+// connectTimeout ограничивает одну попытку подключения
+const connectTimeout = 5 * time.Second
+
+// connect проверяет соединение, повторяя попытки с растущей задержкой, пока
+// БД недоступна: при одновременном старте контейнеров PostgreSQL может
+// подняться позже сервиса. Ошибки, которые не исправятся ожиданием (неверный
+// пароль, нет базы), возвращаются сразу
+func connect(db *sqlx.DB, cfg config.PostgresConfig) error {
+	policy := RetryPolicy{
+		Attempts: cfg.ConnectAttempts,
+		Backoff:  Backoff{Initial: cfg.ConnectBackoff, Max: cfg.ConnectMaxBackoff},
+	}
+
+	return retryWithLog(context.Background(), policy, slog.Default(), "connect", func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+		defer cancel()
+
+		err := db.PingContext(ctx)
+		if errors.Is(err, context.DeadlineExceeded) {
+			// таймаут попытки - не отмена всей операции
+			return fmt.Errorf("таймаут подключения: %w", driver.ErrBadConn)
+		}
+		return err
+	})
+}
+
+// RetryPolicyFromConfig возвращает политику повторов чтений и транзакций
+func RetryPolicyFromConfig(cfg config.PostgresConfig) RetryPolicy {
+	return RetryPolicy{
+		Attempts: cfg.RetryAttempts,
+		Backoff:  Backoff{Initial: cfg.RetryBackoff, Max: cfg.RetryMaxBackoff},
+	}
+}
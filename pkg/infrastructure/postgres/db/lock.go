@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// AdvisoryLock - сессионный advisory lock PostgreSQL для выбора лидера среди
+// реплик. Блокировка держится на отдельном соединении: если соединение
+// рвется, PostgreSQL снимает блокировку и лидером может стать другая реплика
+type AdvisoryLock struct {
+	db  *sqlx.DB
+	key int64
+
+	mu   sync.Mutex
+	conn *sql.Conn // не nil, пока блокировка взята
+}
+
+func NewAdvisoryLock(db *sqlx.DB, key int64) *AdvisoryLock {
+	return &AdvisoryLock{
+		db:  db,
+		key: key,
+	}
+}
+
+// TryLock пытается взять блокировку без ожидания. Если блокировка уже взята,
+// проверяет, что соединение с ней живо
+func (l *AdvisoryLock) TryLock(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn != nil {
+		err := l.conn.PingContext(ctx)
+		if err == nil {
+			return true, nil
+		}
+		// соединение потеряно вместе с блокировкой
+		l.conn.Close()
+		l.conn = nil
+		return false, fmt.Errorf("соединение с блокировкой потеряно: %w", err)
+	}
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("ошибка получения соединения для блокировки: %w", err)
+	}
+
+	var locked bool
+	err = conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", l.key).Scan(&locked)
+	if err != nil {
+		conn.Close()
+		return false, fmt.Errorf("ошибка получения блокировки: %w", err)
+	}
+	if !locked {
+		conn.Close()
+		return false, nil
+	}
+
+	l.conn = conn
+	return true, nil
+}
+
+// Unlock снимает блокировку и возвращает соединение в пул
+func (l *AdvisoryLock) Unlock(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+	defer func() {
+		l.conn.Close()
+		l.conn = nil
+	}()
+
+	_, err := l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.key)
+	if err != nil {
+		return fmt.Errorf("ошибка снятия блокировки: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package db
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// QueryRecorder принимает длительность выполнения методов репозиториев
+type QueryRecorder interface {
+	ObserveQuery(repository, method string, duration time.Duration)
+}
+
+type queryInstrumentation struct {
+	recorder      QueryRecorder
+	slowThreshold time.Duration
+	logger        *slog.Logger
+}
+
+var instrumentation atomic.Pointer[queryInstrumentation]
+
+// SetQueryInstrumentation включает замер методов репозиториев. Вызывается
+// один раз при старте; slowThreshold <= 0 или пустой logger отключают лог
+// медленных запросов
+func SetQueryInstrumentation(recorder QueryRecorder, slowThreshold time.Duration, logger *slog.Logger) {
+	instrumentation.Store(&queryInstrumentation{
+		recorder:      recorder,
+		slowThreshold: slowThreshold,
+		logger:        logger,
+	})
+}
+
+// ObserveQuery начинает замер метода репозитория. Возвращаемую функцию нужно
+// вызвать по завершении метода:
+//
+//	defer db.ObserveQuery(ctx, "pvz", "GetByID")()
+func ObserveQuery(ctx context.Context, repository, method string) func() {
+	start := time.Now()
+
+	return func() {
+		current := instrumentation.Load()
+		if current == nil {
+			return
+		}
+
+		duration := time.Since(start)
+		if current.recorder != nil {
+			current.recorder.ObserveQuery(repository, method, duration)
+		}
+
+		if current.logger != nil && current.slowThreshold > 0 && duration >= current.slowThreshold {
+			current.logger.WarnContext(ctx, "Медленный запрос к БД",
+				"repository", repository,
+				"method", method,
+				"duration", duration.String(),
+				"threshold", current.slowThreshold.String())
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+type recordedQuery struct {
+	repository string
+	method     string
+	duration   time.Duration
+}
+
+type fakeRecorder struct {
+	queries []recordedQuery
+}
+
+func (r *fakeRecorder) ObserveQuery(repository, method string, duration time.Duration) {
+	r.queries = append(r.queries, recordedQuery{repository, method, duration})
+}
+
+// captureLogs возвращает логгер для замеров; он же становится логгером по
+// умолчанию, чтобы поймать записи мимо внедренного логгера
+func captureLogs(t *testing.T) (*bytes.Buffer, *slog.Logger) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	log := slog.New(slog.NewTextHandler(&buf, nil))
+	prev := slog.Default()
+	slog.SetDefault(log)
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	return &buf, log
+}
+
+func TestObserveQuery_RecordsDuration(t *testing.T) {
+	logs, log := captureLogs(t)
+	recorder := &fakeRecorder{}
+	SetQueryInstrumentation(recorder, time.Hour, log)
+	t.Cleanup(func() { instrumentation.Store(nil) })
+
+	ObserveQuery(context.Background(), "pvz", "GetByID")()
+
+	if len(recorder.queries) != 1 {
+		t.Fatalf("Ожидался 1 замер, получено %d", len(recorder.queries))
+	}
+	query := recorder.queries[0]
+	if query.repository != "pvz" || query.method != "GetByID" {
+		t.Errorf("Неверные метки замера: %+v", query)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("Быстрый запрос не должен логироваться: %s", logs.String())
+	}
+}
+
+func TestObserveQuery_LogsSlowQuery(t *testing.T) {
+	logs, log := captureLogs(t)
+	SetQueryInstrumentation(&fakeRecorder{}, time.Millisecond, log)
+	t.Cleanup(func() { instrumentation.Store(nil) })
+
+	done := ObserveQuery(context.Background(), "reception", "GetByPVZID")
+	time.Sleep(2 * time.Millisecond)
+	done()
+
+	output := logs.String()
+	if !strings.Contains(output, "Медленный запрос к БД") || !strings.Contains(output, "method=GetByPVZID") {
+		t.Errorf("Ожидалась запись о медленном запросе, получено: %s", output)
+	}
+}
+
+func TestObserveQuery_WithoutInstrumentation(t *testing.T) {
+	logs, _ := captureLogs(t)
+	instrumentation.Store(nil)
+
+	ObserveQuery(context.Background(), "pvz", "List")()
+
+	if logs.Len() != 0 {
+		t.Errorf("Без настройки замер не должен ничего писать: %s", logs.String())
+	}
+}
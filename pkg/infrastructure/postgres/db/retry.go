@@ -0,0 +1,157 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Backoff - экспоненциальная задержка между попытками: Initial перед второй
+// попыткой, затем вдвое больше, но не больше Max. К задержке добавляется
+// случайная составляющая, чтобы реплики не повторяли запросы одновременно
+type Backoff struct {
+	Initial time.Duration
+	Max     time.Duration
+}
+
+// Delay возвращает задержку перед попыткой attempt+1 (attempt считается с 1).
+// Результат лежит в диапазоне [d/2, d], где d - экспоненциальная задержка
+func (b Backoff) Delay(attempt int) time.Duration {
+	delay := b.Initial
+	for i := 1; i < attempt && delay < b.Max; i++ {
+		delay *= 2
+	}
+	if b.Max > 0 && delay > b.Max {
+		delay = b.Max
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+// RetryPolicy - сколько раз и с какой задержкой повторять операцию
+type RetryPolicy struct {
+	Attempts int // всего попыток, включая первую
+	Backoff  Backoff
+}
+
+// NoRetry выполняет операцию один раз
+var NoRetry = RetryPolicy{Attempts: 1}
+
+type retrySettings struct {
+	policy RetryPolicy
+	logger *slog.Logger
+}
+
+var retry atomic.Pointer[retrySettings]
+
+// SetRetryPolicy задает повторы чтений вне транзакций и транзакций Transactor
+// при временных ошибках БД. Вызывается один раз при старте; без вызова
+// операции не повторяются
+func SetRetryPolicy(policy RetryPolicy, logger *slog.Logger) {
+	retry.Store(&retrySettings{policy: policy, logger: logger})
+}
+
+func currentRetry() retrySettings {
+	if current := retry.Load(); current != nil {
+		return *current
+	}
+	return retrySettings{policy: NoRetry}
+}
+
+// Retry выполняет fn, повторяя ее при временных ошибках (IsRetryable), пока не
+// исчерпаны попытки или не отменен ctx. Возвращает последнюю ошибку
+func Retry(ctx context.Context, policy RetryPolicy, fn func() error) error {
+	return retryWithLog(ctx, policy, nil, "", fn)
+}
+
+func retryWithLog(ctx context.Context, policy RetryPolicy, logger *slog.Logger, operation string, fn func() error) error {
+	attempts := max(policy.Attempts, 1)
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil || attempt >= attempts || !IsRetryable(err) {
+			return err
+		}
+
+		delay := policy.Backoff.Delay(attempt)
+		if logger != nil {
+			logger.WarnContext(ctx, "Временная ошибка БД, повтор",
+				"operation", operation,
+				"attempt", attempt,
+				"delay", delay.String(),
+				"error", err)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// Коды ошибок PostgreSQL, после которых операцию можно повторить
+var retryableCodes = map[pq.ErrorCode]bool{
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+	"55P03": true, // lock_not_available
+	"53300": true, // too_many_connections
+	"57P01": true, // admin_shutdown
+	"57P02": true, // crash_shutdown
+	"57P03": true, // cannot_connect_now
+}
+
+// IsRetryable сообщает, что ошибка временная и операцию можно повторить:
+// конфликт сериализации, взаимная блокировка, недоступность или разрыв
+// соединения. Отмена контекста временной ошибкой не считается
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var commitErr commitError
+	if errors.As(err, &commitErr) {
+		return isConflict(err)
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		// класс 08 - ошибки соединения
+		return retryableCodes[pqErr.Code] || pqErr.Code.Class() == "08"
+	}
+
+	if errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// isConflict - транзакция отменена сервером из-за конфликта и точно не
+// зафиксирована, поэтому ее можно выполнить заново даже после ошибки Commit
+func isConflict(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && (pqErr.Code == "40001" || pqErr.Code == "40P01")
+}
@@ -0,0 +1,125 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"нет ошибки", nil, false},
+		{"конфликт сериализации", &pq.Error{Code: "40001"}, true},
+		{"взаимная блокировка", fmt.Errorf("ошибка обновления: %w", &pq.Error{Code: "40P01"}), true},
+		{"сервер запускается", &pq.Error{Code: "57P03"}, true},
+		{"ошибка соединения", &pq.Error{Code: "08006"}, true},
+		{"нарушение уникальности", &pq.Error{Code: "23505"}, false},
+		{"неверный пароль", &pq.Error{Code: "28P01"}, false},
+		{"разрыв соединения", fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{"плохое соединение", driver.ErrBadConn, true},
+		{"сетевая ошибка", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+		{"отмена контекста", fmt.Errorf("query: %w", context.Canceled), false},
+		{"обычная ошибка", errors.New("приемка не найдена"), false},
+		{"разрыв при фиксации", commitError{fmt.Errorf("commit: %w", driver.ErrBadConn)}, false},
+		{"конфликт при фиксации", commitError{fmt.Errorf("commit: %w", &pq.Error{Code: "40001"})}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoff_Delay(t *testing.T) {
+	backoff := Backoff{Initial: 100 * time.Millisecond, Max: time.Second}
+
+	tests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{10, time.Second},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			delay := backoff.Delay(tt.attempt)
+			if delay < tt.max/2 || delay > tt.max {
+				t.Fatalf("Delay(%d) = %v, want in [%v, %v]", tt.attempt, delay, tt.max/2, tt.max)
+			}
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	policy := RetryPolicy{Attempts: 3, Backoff: Backoff{Initial: time.Millisecond, Max: time.Millisecond}}
+	transient := &pq.Error{Code: "40001"}
+
+	t.Run("успех после временных ошибок", func(t *testing.T) {
+		calls := 0
+		err := Retry(context.Background(), policy, func() error {
+			calls++
+			if calls < 3 {
+				return transient
+			}
+			return nil
+		})
+		if err != nil || calls != 3 {
+			t.Errorf("Expected success on third call, got err=%v calls=%d", err, calls)
+		}
+	})
+
+	t.Run("попытки исчерпаны", func(t *testing.T) {
+		calls := 0
+		err := Retry(context.Background(), policy, func() error {
+			calls++
+			return transient
+		})
+		if !errors.Is(err, transient) || calls != 3 {
+			t.Errorf("Expected last error after 3 calls, got err=%v calls=%d", err, calls)
+		}
+	})
+
+	t.Run("постоянная ошибка не повторяется", func(t *testing.T) {
+		calls := 0
+		permanent := &pq.Error{Code: "23505"}
+		err := Retry(context.Background(), policy, func() error {
+			calls++
+			return permanent
+		})
+		if !errors.Is(err, permanent) || calls != 1 {
+			t.Errorf("Expected single call, got err=%v calls=%d", err, calls)
+		}
+	})
+
+	t.Run("отмена контекста прерывает ожидание", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		slow := RetryPolicy{Attempts: 5, Backoff: Backoff{Initial: time.Hour, Max: time.Hour}}
+
+		calls := 0
+		err := Retry(ctx, slow, func() error {
+			calls++
+			cancel()
+			return transient
+		})
+		if !errors.Is(err, transient) || calls != 1 {
+			t.Errorf("Expected to stop after cancel, got err=%v calls=%d", err, calls)
+		}
+	})
+}
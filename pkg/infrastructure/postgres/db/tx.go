@@ -0,0 +1,150 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
+	"github.com/jmoiron/sqlx"
+)
+
+// Querier - общие методы *sqlx.DB и *sqlx.Tx, которые используют репозитории
+type Querier interface {
+	sqlx.ExtContext
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
+	PrepareNamedContext(ctx context.Context, query string) (*sqlx.NamedStmt, error)
+}
+
+// Tx - транзакция репозитория
+type Tx interface {
+	Querier
+	Commit() error
+	Rollback() error
+}
+
+type txContextKey struct{}
+
+// Conn возвращает транзакцию, начатую через Transactor, или соединение с БД.
+// Вне транзакции чтения GetContext и SelectContext повторяются при временных
+// ошибках по политике SetRetryPolicy
+func Conn(ctx context.Context, db *sqlx.DB) Querier {
+	if tx, ok := ctx.Value(txContextKey{}).(*sqlx.Tx); ok {
+		return tx
+	}
+	return readRetryDB{db}
+}
+
+// readRetryDB повторяет чтения. Остальные методы не повторяются: они могут
+// изменять данные, и повтор после разрыва соединения применил бы изменение дважды
+type readRetryDB struct {
+	*sqlx.DB
+}
+
+func (d readRetryDB) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	settings := currentRetry()
+	return retryWithLog(ctx, settings.policy, settings.logger, "read", func() error {
+		return d.DB.GetContext(ctx, dest, query, args...)
+	})
+}
+
+func (d readRetryDB) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	settings := currentRetry()
+	return retryWithLog(ctx, settings.policy, settings.logger, "read", func() error {
+		// sqlx дописывает строки в срез, поэтому перед повтором он очищается
+		reflect.ValueOf(dest).Elem().SetZero()
+		return d.DB.SelectContext(ctx, dest, query, args...)
+	})
+}
+
+// BeginTx начинает транзакцию репозитория. Если контекст уже содержит
+// транзакцию Transactor, репозиторий работает в ней, а Commit и Rollback
+// не выполняют действий: транзакцию завершает ее владелец
+func BeginTx(ctx context.Context, db *sqlx.DB) (Tx, error) {
+	if tx, ok := ctx.Value(txContextKey{}).(*sqlx.Tx); ok {
+		return nestedTx{tx}, nil
+	}
+	return db.BeginTxx(ctx, nil)
+}
+
+type nestedTx struct {
+	*sqlx.Tx
+}
+
+func (nestedTx) Commit() error   { return nil }
+func (nestedTx) Rollback() error { return nil }
+
+type transactor struct {
+	db     *sqlx.DB
+	policy *RetryPolicy // nil - политика SetRetryPolicy
+}
+
+// NewTransactor возвращает реализацию repositories.Transactor на PostgreSQL
+func NewTransactor(db *sqlx.DB) repositories.Transactor {
+	return &transactor{db: db}
+}
+
+// NewTransactorWithPolicy возвращает Transactor, который повторяет транзакции
+// по policy вместо SetRetryPolicy. С NoRetry fn выполняется не больше одного
+// раза, поэтому может иметь побочные эффекты вне БД
+func NewTransactorWithPolicy(db *sqlx.DB, policy RetryPolicy) repositories.Transactor {
+	return &transactor{db: db, policy: &policy}
+}
+
+// WithinTransaction выполняет fn в транзакции. При временной ошибке
+// транзакция выполняется заново по политике транзактора, поэтому fn не
+// должна иметь побочных эффектов вне БД
+func (t *transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	// вложенный вызов продолжает внешнюю транзакцию
+	if _, ok := ctx.Value(txContextKey{}).(*sqlx.Tx); ok {
+		return fn(ctx)
+	}
+
+	settings := currentRetry()
+	if t.policy != nil {
+		settings.policy = *t.policy
+	}
+	return retryWithLog(ctx, settings.policy, settings.logger, "transaction", func() error {
+		return t.run(ctx, fn)
+	})
+}
+
+func (t *transactor) run(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	tx, err := t.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	err = fn(context.WithValue(ctx, txContextKey{}, tx))
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return commitError{fmt.Errorf("ошибка фиксации транзакции: %w", err)}
+	}
+
+	return nil
+}
+
+// commitError - ошибка Commit. После разрыва соединения неизвестно,
+// зафиксирована ли транзакция, поэтому повторять ее можно только при конфликте
+type commitError struct {
+	err error
+}
+
+func (e commitError) Error() string { return e.err.Error() }
+func (e commitError) Unwrap() error { return e.err }
@@ -1,9 +1,11 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/lib/pq"
 )
 
 // модель ПВЗ в БД
@@ -35,6 +37,11 @@ type ReceptionModel struct {
 	DateTime time.Time `db:"date_time"`
 	PVZID    string    `db:"pvz_id"`
 	Status   string    `db:"status"`
+
+	ClosedAt    *time.Time `db:"closed_at"`
+	ClosedBy    *string    `db:"closed_by"`
+	CloseReason *string    `db:"close_reason"`
+	StaleSince  *time.Time `db:"stale_since"`
 }
 
 // ToEntity преобразует модель БД в доменную сущность
@@ -45,6 +52,15 @@ func (r *ReceptionModel) ToEntity() *domain.Reception {
 		PVZID:    r.PVZID,
 		Status:   r.Status,
 		Products: make([]domain.Product, 0),
+
+		ClosedAt:   r.ClosedAt,
+		StaleSince: r.StaleSince,
+	}
+	if r.ClosedBy != nil {
+		reception.ClosedBy = *r.ClosedBy
+	}
+	if r.CloseReason != nil {
+		reception.CloseReason = *r.CloseReason
 	}
 	return reception
 }
@@ -55,6 +71,18 @@ func (r *ReceptionModel) FromEntity(reception *domain.Reception) {
 	r.DateTime = reception.DateTime
 	r.PVZID = reception.PVZID
 	r.Status = reception.Status
+	r.ClosedAt = reception.ClosedAt
+	r.ClosedBy = nullableString(reception.ClosedBy)
+	r.CloseReason = nullableString(reception.CloseReason)
+	r.StaleSince = reception.StaleSince
+}
+
+// nullableString возвращает nil для пустой строки, чтобы в БД записался NULL
+func nullableString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
 }
 
 // модель товара в БД
@@ -118,3 +146,307 @@ func (u *UserModel) FromDomain(user domain.User) {
 	u.Role = string(user.Role)
 	u.CreatedAt = user.CreatedAt
 }
+
+// модель закрепления сотрудника за ПВЗ в БД
+type AssignmentModel struct {
+	UserID     string    `db:"user_id"`
+	PVZID      string    `db:"pvz_id"`
+	AssignedAt time.Time `db:"assigned_at"`
+}
+
+// ToEntity преобразует модель БД в доменную сущность
+func (a *AssignmentModel) ToEntity() *domain.Assignment {
+	return &domain.Assignment{
+		UserID:     a.UserID,
+		PVZID:      a.PVZID,
+		AssignedAt: a.AssignedAt,
+	}
+}
+
+// FromEntity преобразует доменную сущность в модель БД
+func (a *AssignmentModel) FromEntity(assignment *domain.Assignment) {
+	a.UserID = assignment.UserID
+	a.PVZID = assignment.PVZID
+	a.AssignedAt = assignment.AssignedAt
+}
+
+// модель API-ключа в БД
+type APIKeyModel struct {
+	ID         string         `db:"id"`
+	Name       string         `db:"name"`
+	Prefix     string         `db:"prefix"`
+	KeyHash    string         `db:"key_hash"`
+	Scopes     pq.StringArray `db:"scopes"`
+	CreatedBy  *string        `db:"created_by"`
+	CreatedAt  time.Time      `db:"created_at"`
+	ExpiresAt  *time.Time     `db:"expires_at"`
+	LastUsedAt *time.Time     `db:"last_used_at"`
+	RevokedAt  *time.Time     `db:"revoked_at"`
+}
+
+// ToEntity преобразует модель БД в доменную сущность
+func (k *APIKeyModel) ToEntity() *domain.APIKey {
+	key := &domain.APIKey{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		KeyHash:    k.KeyHash,
+		Scopes:     []string(k.Scopes),
+		CreatedAt:  k.CreatedAt,
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+	}
+	if k.CreatedBy != nil {
+		key.CreatedBy = *k.CreatedBy
+	}
+	return key
+}
+
+// FromEntity преобразует доменную сущность в модель БД
+func (k *APIKeyModel) FromEntity(key *domain.APIKey) {
+	k.ID = key.ID
+	k.Name = key.Name
+	k.Prefix = key.Prefix
+	k.KeyHash = key.KeyHash
+	k.Scopes = pq.StringArray(key.Scopes)
+	k.CreatedBy = nil
+	if key.CreatedBy != "" {
+		createdBy := key.CreatedBy
+		k.CreatedBy = &createdBy
+	}
+	k.CreatedAt = key.CreatedAt
+	k.ExpiresAt = key.ExpiresAt
+	k.LastUsedAt = key.LastUsedAt
+	k.RevokedAt = key.RevokedAt
+}
+
+// модель настроек 2FA в БД
+type TwoFactorModel struct {
+	UserID             string         `db:"user_id"`
+	Secret             string         `db:"secret"`
+	EnabledAt          *time.Time     `db:"enabled_at"`
+	LastUsedStep       int64          `db:"last_used_step"`
+	RecoveryCodeHashes pq.StringArray `db:"recovery_code_hashes"`
+	CreatedAt          time.Time      `db:"created_at"`
+}
+
+// ToEntity преобразует модель БД в доменную сущность
+func (t *TwoFactorModel) ToEntity() *domain.TwoFactor {
+	return &domain.TwoFactor{
+		UserID:             t.UserID,
+		Secret:             t.Secret,
+		EnabledAt:          t.EnabledAt,
+		LastUsedStep:       t.LastUsedStep,
+		RecoveryCodeHashes: []string(t.RecoveryCodeHashes),
+		CreatedAt:          t.CreatedAt,
+	}
+}
+
+// FromEntity преобразует доменную сущность в модель БД
+func (t *TwoFactorModel) FromEntity(twoFactor *domain.TwoFactor) {
+	t.UserID = twoFactor.UserID
+	t.Secret = twoFactor.Secret
+	t.EnabledAt = twoFactor.EnabledAt
+	t.LastUsedStep = twoFactor.LastUsedStep
+	// пустой список, а не NULL: колонка объявлена NOT NULL
+	t.RecoveryCodeHashes = pq.StringArray{}
+	if twoFactor.RecoveryCodeHashes != nil {
+		t.RecoveryCodeHashes = pq.StringArray(twoFactor.RecoveryCodeHashes)
+	}
+	t.CreatedAt = twoFactor.CreatedAt
+}
+
+// модель записи журнала аудита в БД
+type AuditLogModel struct {
+	ID         string    `db:"id"`
+	ActorID    *string   `db:"actor_id"`
+	ActorRole  string    `db:"actor_role"`
+	Action     string    `db:"action"`
+	EntityType string    `db:"entity_type"`
+	EntityID   string    `db:"entity_id"`
+	Before     *string   `db:"before"` // JSONB передается строкой: []byte драйвер отправил бы как bytea
+	After      *string   `db:"after"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// ToEntity преобразует модель БД в доменную сущность
+func (a *AuditLogModel) ToEntity() *domain.AuditEntry {
+	entry := &domain.AuditEntry{
+		ID:         a.ID,
+		ActorRole:  a.ActorRole,
+		Action:     a.Action,
+		EntityType: a.EntityType,
+		EntityID:   a.EntityID,
+		CreatedAt:  a.CreatedAt,
+	}
+	if a.ActorID != nil {
+		entry.ActorID = *a.ActorID
+	}
+	if a.Before != nil {
+		entry.Before = json.RawMessage(*a.Before)
+	}
+	if a.After != nil {
+		entry.After = json.RawMessage(*a.After)
+	}
+	return entry
+}
+
+// FromEntity преобразует доменную сущность в модель БД
+func (a *AuditLogModel) FromEntity(entry *domain.AuditEntry) {
+	a.ID = entry.ID
+	a.ActorID = nil
+	if entry.ActorID != "" {
+		actorID := entry.ActorID
+		a.ActorID = &actorID
+	}
+	a.ActorRole = entry.ActorRole
+	a.Action = entry.Action
+	a.EntityType = entry.EntityType
+	a.EntityID = entry.EntityID
+	a.Before = nil
+	if entry.Before != nil {
+		before := string(entry.Before)
+		a.Before = &before
+	}
+	a.After = nil
+	if entry.After != nil {
+		after := string(entry.After)
+		a.After = &after
+	}
+	a.CreatedAt = entry.CreatedAt
+}
+
+// модель события outbox в БД
+type OutboxModel struct {
+	ID            string    `db:"id"`
+	EventType     string    `db:"event_type"`
+	AggregateType string    `db:"aggregate_type"`
+	AggregateID   string    `db:"aggregate_id"`
+	Payload       string    `db:"payload"` // JSONB передается строкой, как в AuditLogModel
+	OccurredAt    time.Time `db:"occurred_at"`
+}
+
+// ToEntity преобразует модель БД в доменную сущность
+func (o *OutboxModel) ToEntity() *domain.Event {
+	return &domain.Event{
+		ID:            o.ID,
+		Type:          o.EventType,
+		AggregateType: o.AggregateType,
+		AggregateID:   o.AggregateID,
+		Payload:       json.RawMessage(o.Payload),
+		OccurredAt:    o.OccurredAt,
+	}
+}
+
+// FromEntity преобразует доменную сущность в модель БД
+func (o *OutboxModel) FromEntity(event *domain.Event) {
+	o.ID = event.ID
+	o.EventType = event.Type
+	o.AggregateType = event.AggregateType
+	o.AggregateID = event.AggregateID
+	o.Payload = string(event.Payload)
+	o.OccurredAt = event.OccurredAt
+}
+
+// модель вебхука в БД
+type WebhookModel struct {
+	ID         string         `db:"id"`
+	URL        string         `db:"url"`
+	EventTypes pq.StringArray `db:"event_types"`
+	Secret     string         `db:"secret"`
+	CreatedBy  *string        `db:"created_by"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
+
+// ToEntity преобразует модель БД в доменную сущность
+func (w *WebhookModel) ToEntity() *domain.Webhook {
+	webhook := &domain.Webhook{
+		ID:         w.ID,
+		URL:        w.URL,
+		EventTypes: []string(w.EventTypes),
+		Secret:     w.Secret,
+		CreatedAt:  w.CreatedAt,
+	}
+	if w.CreatedBy != nil {
+		webhook.CreatedBy = *w.CreatedBy
+	}
+	return webhook
+}
+
+// FromEntity преобразует доменную сущность в модель БД
+func (w *WebhookModel) FromEntity(webhook *domain.Webhook) {
+	w.ID = webhook.ID
+	w.URL = webhook.URL
+	w.EventTypes = pq.StringArray(webhook.EventTypes)
+	w.Secret = webhook.Secret
+	w.CreatedBy = nil
+	if webhook.CreatedBy != "" {
+		createdBy := webhook.CreatedBy
+		w.CreatedBy = &createdBy
+	}
+	w.CreatedAt = webhook.CreatedAt
+}
+
+// модель доставки вебхука в БД
+type WebhookDeliveryModel struct {
+	ID             string     `db:"id"`
+	WebhookID      string     `db:"webhook_id"`
+	EventID        string     `db:"event_id"`
+	EventType      string     `db:"event_type"`
+	Payload        string     `db:"payload"`
+	Status         string     `db:"status"`
+	Attempts       int        `db:"attempts"`
+	NextAttemptAt  time.Time  `db:"next_attempt_at"`
+	LastStatusCode *int       `db:"last_status_code"`
+	LastError      *string    `db:"last_error"`
+	CreatedAt      time.Time  `db:"created_at"`
+	DeliveredAt    *time.Time `db:"delivered_at"`
+}
+
+// ToEntity преобразует модель БД в доменную сущность
+func (d *WebhookDeliveryModel) ToEntity() *domain.WebhookDelivery {
+	delivery := &domain.WebhookDelivery{
+		ID:            d.ID,
+		WebhookID:     d.WebhookID,
+		EventID:       d.EventID,
+		EventType:     d.EventType,
+		Payload:       json.RawMessage(d.Payload),
+		Status:        d.Status,
+		Attempts:      d.Attempts,
+		NextAttemptAt: d.NextAttemptAt,
+		CreatedAt:     d.CreatedAt,
+		DeliveredAt:   d.DeliveredAt,
+	}
+	if d.LastStatusCode != nil {
+		delivery.LastStatusCode = *d.LastStatusCode
+	}
+	if d.LastError != nil {
+		delivery.LastError = *d.LastError
+	}
+	return delivery
+}
+
+// FromEntity преобразует доменную сущность в модель БД
+func (d *WebhookDeliveryModel) FromEntity(delivery *domain.WebhookDelivery) {
+	d.ID = delivery.ID
+	d.WebhookID = delivery.WebhookID
+	d.EventID = delivery.EventID
+	d.EventType = delivery.EventType
+	d.Payload = string(delivery.Payload)
+	d.Status = delivery.Status
+	d.Attempts = delivery.Attempts
+	d.NextAttemptAt = delivery.NextAttemptAt
+	d.LastStatusCode = nil
+	if delivery.LastStatusCode != 0 {
+		statusCode := delivery.LastStatusCode
+		d.LastStatusCode = &statusCode
+	}
+	d.LastError = nil
+	if delivery.LastError != "" {
+		lastError := delivery.LastError
+		d.LastError = &lastError
+	}
+	d.CreatedAt = delivery.CreatedAt
+	d.DeliveredAt = delivery.DeliveredAt
+}
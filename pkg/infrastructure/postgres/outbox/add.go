@@ -0,0 +1,41 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
+)
+
+// Add добавляет событие в outbox. Вызывается в транзакции изменения
+func (r *Repository) Add(ctx context.Context, event *domain.Event) error {
+	defer db.ObserveQuery(ctx, "outbox", "Add")()
+
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	model := &models.OutboxModel{}
+	model.FromEntity(event)
+
+	query := `
+		INSERT INTO outbox (id, event_type, aggregate_type, aggregate_id, payload, occurred_at)
+		VALUES (:id, :event_type, :aggregate_type, :aggregate_id, :payload, :occurred_at)
+	`
+
+	_, err := db.Conn(ctx, r.db).NamedExecContext(ctx, query, model)
+	if err != nil {
+		return fmt.Errorf("ошибка записи события в outbox: %w", err)
+	}
+
+	return nil
+}
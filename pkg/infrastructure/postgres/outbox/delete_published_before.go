@@ -0,0 +1,28 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+)
+
+// DeletePublishedBefore удаляет события, опубликованные раньше before
+func (r *Repository) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	defer db.ObserveQuery(ctx, "outbox", "DeletePublishedBefore")()
+
+	query := `DELETE FROM outbox WHERE published_at IS NOT NULL AND published_at < $1`
+
+	result, err := db.Conn(ctx, r.db).ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка удаления опубликованных событий: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("ошибка удаления опубликованных событий: %w", err)
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,38 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
+)
+
+// ListPending возвращает неопубликованные события в порядке записи. Строки
+// блокируются до конца транзакции; занятые другой транзакцией пропускаются
+func (r *Repository) ListPending(ctx context.Context, limit int) ([]*domain.Event, error) {
+	defer db.ObserveQuery(ctx, "outbox", "ListPending")()
+
+	query := `
+		SELECT id, event_type, aggregate_type, aggregate_id, payload, occurred_at
+		FROM outbox
+		WHERE published_at IS NULL
+		ORDER BY seq
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	var rows []models.OutboxModel
+	err := db.Conn(ctx, r.db).SelectContext(ctx, &rows, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения событий outbox: %w", err)
+	}
+
+	events := make([]*domain.Event, 0, len(rows))
+	for i := range rows {
+		events = append(events, rows[i].ToEntity())
+	}
+
+	return events, nil
+}
@@ -0,0 +1,24 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+)
+
+// MarkFailed увеличивает счетчик попыток публикации и сохраняет причину ошибки
+func (r *Repository) MarkFailed(ctx context.Context, ids []string, reason string) error {
+	defer db.ObserveQuery(ctx, "outbox", "MarkFailed")()
+
+	query := `UPDATE outbox SET attempts = attempts + 1, last_error = $2 WHERE id = ANY($1)`
+
+	_, err := db.Conn(ctx, r.db).ExecContext(ctx, query, pq.Array(ids), reason)
+	if err != nil {
+		return fmt.Errorf("ошибка записи неудачной публикации событий: %w", err)
+	}
+
+	return nil
+}
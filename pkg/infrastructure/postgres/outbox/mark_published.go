@@ -0,0 +1,24 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+)
+
+// MarkPublished отмечает события опубликованными
+func (r *Repository) MarkPublished(ctx context.Context, ids []string) error {
+	defer db.ObserveQuery(ctx, "outbox", "MarkPublished")()
+
+	query := `UPDATE outbox SET published_at = NOW() WHERE id = ANY($1)`
+
+	_, err := db.Conn(ctx, r.db).ExecContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("ошибка отметки публикации событий: %w", err)
+	}
+
+	return nil
+}
@@ -4,24 +4,39 @@ import (
 	"github.com/jmoiron/sqlx"
 
 	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/apikey"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/assignment"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/audit"
+	postgresdb "github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/product"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/pvz"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/reception"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/twofactor"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/user"
 )
 
 type Repositories struct {
-	User      repositories.UserRepository
-	PVZ       repositories.PVZRepository
-	Reception *reception.Repository
-	Product   *product.Repository
+	User       repositories.UserRepository
+	PVZ        repositories.PVZRepository
+	Reception  *reception.Repository
+	Product    *product.Repository
+	Assignment repositories.AssignmentRepository
+	APIKey     repositories.APIKeyRepository
+	TwoFactor  repositories.TwoFactorRepository
+	Audit      repositories.AuditRepository
+	Transactor repositories.Transactor
 }
 
 func NewRepositories(db *sqlx.DB) *Repositories {
 	return &Repositories{
-		User:      user.New(db),
-		PVZ:       pvz.New(db),
-		Reception: reception.New(db),
-		Product:   product.New(db),
+		User:       user.New(db),
+		PVZ:        pvz.New(db),
+		Reception:  reception.New(db),
+		Product:    product.New(db),
+		Assignment: assignment.New(db),
+		APIKey:     apikey.New(db),
+		TwoFactor:  twofactor.New(db),
+		Audit:      audit.New(db),
+		Transactor: postgresdb.NewTransactor(db),
 	}
 }
@@ -6,12 +6,15 @@ import (
 	"time"
 
 	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
 )
 
 // Create создает новый товар и добавляет его в очередь приемки
 func (r *Repository) Create(ctx context.Context, product *domain.Product, receptionID string) (*domain.Product, error) {
-	tx, err := r.db.BeginTxx(ctx, nil)
+	defer db.ObserveQuery(ctx, "product", "Create")()
+
+	tx, err := db.BeginTx(ctx, r.db)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка начала транзакции: %w", err)
 	}
@@ -5,11 +5,15 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
 )
 
 // DeleteByID удаляет товар по его ID
 func (r *Repository) DeleteByID(ctx context.Context, id string) error {
-	tx, err := r.db.BeginTxx(ctx, nil)
+	defer db.ObserveQuery(ctx, "product", "DeleteByID")()
+
+	tx, err := db.BeginTx(ctx, r.db)
 	if err != nil {
 		return fmt.Errorf("ошибка начала транзакции: %w", err)
 	}
@@ -5,11 +5,15 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
 )
 
 // DeleteLastByReceptionID удаляет последний добавленный товар из приемки
 func (r *Repository) DeleteLastByReceptionID(ctx context.Context, receptionID string) error {
-	tx, err := r.db.BeginTxx(ctx, nil)
+	defer db.ObserveQuery(ctx, "product", "DeleteLastByReceptionID")()
+
+	tx, err := db.BeginTx(ctx, r.db)
 	if err != nil {
 		return fmt.Errorf("ошибка начала транзакции: %w", err)
 	}
@@ -5,12 +5,15 @@ import (
 	"fmt"
 
 	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
 )
 
 // GetByReceptionID получает список товаров для конкретной приемки
 // в порядке их добавления в очередь
 func (r *Repository) GetByReceptionID(ctx context.Context, receptionID string) ([]*domain.Product, error) {
+	defer db.ObserveQuery(ctx, "product", "GetByReceptionID")()
+
 	query := `
 		SELECT p.id, p.date_time, p.type, p.reception_id
 		FROM product p
@@ -20,7 +23,7 @@ func (r *Repository) GetByReceptionID(ctx context.Context, receptionID string) (
 	`
 
 	var productModels []models.ProductModel
-	err := r.db.SelectContext(ctx, &productModels, query, receptionID)
+	err := db.Conn(ctx, r.db).SelectContext(ctx, &productModels, query, receptionID)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка при получении товаров для приемки: %w", err)
 	}
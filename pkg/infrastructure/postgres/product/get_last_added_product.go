@@ -7,11 +7,14 @@ import (
 	"fmt"
 
 	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
 )
 
 // GetLastAddedProduct получает последний добавленный товар в приемку
 func (r *Repository) GetLastAddedProduct(ctx context.Context, receptionID string) (*domain.Product, error) {
+	defer db.ObserveQuery(ctx, "product", "GetLastAddedProduct")()
+
 	query := `
         SELECT p.id, p.date_time, p.type, p.reception_id
         FROM product p
@@ -22,7 +25,7 @@ func (r *Repository) GetLastAddedProduct(ctx context.Context, receptionID string
     `
 
 	model := &models.ProductModel{}
-	err := r.db.GetContext(ctx, model, query, receptionID)
+	err := db.Conn(ctx, r.db).GetContext(ctx, model, query, receptionID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("товары для приемки с ID %s не найдены", receptionID)
@@ -9,11 +9,14 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
 )
 
 // Create создает новый ПВЗ в базе данных
 func (r *Repository) Create(ctx context.Context, pvz *domain.PVZ) (*domain.PVZ, error) {
+	defer db.ObserveQuery(ctx, "pvz", "Create")()
+
 	if pvz.ID == "" {
 		pvz.ID = uuid.New().String()
 	}
@@ -32,7 +35,7 @@ func (r *Repository) Create(ctx context.Context, pvz *domain.PVZ) (*domain.PVZ,
 		RETURNING id, registration_date, city
 	`
 
-	stmt, err := r.db.PrepareNamedContext(ctx, query)
+	stmt, err := db.Conn(ctx, r.db).PrepareNamedContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка подготовки запроса: %w", err)
 	}
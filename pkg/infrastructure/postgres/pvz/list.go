@@ -6,11 +6,14 @@ import (
 
 	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
 	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
 )
 
 // List возвращает список ПВЗ с возможностью фильтрации
 func (r *Repository) List(ctx context.Context, filter repositories.PVZFilter) ([]*domain.PVZ, error) {
+	defer db.ObserveQuery(ctx, "pvz", "List")()
+
 	baseQuery := `
 		SELECT p.id, p.registration_date, p.city
 		FROM pvz p
@@ -46,7 +49,7 @@ func (r *Repository) List(ctx context.Context, filter repositories.PVZFilter) ([
 	query := baseQuery + joinClause + whereClause + limitClause
 
 	var pvzModels []models.PVZModel
-	err := r.db.SelectContext(ctx, &pvzModels, query, args...)
+	err := db.Conn(ctx, r.db).SelectContext(ctx, &pvzModels, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка при получении списка ПВЗ: %w", err)
 	}
@@ -6,12 +6,15 @@ import (
 	"time"
 
 	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
 )
 
 // Create создает новую приемку товаров в базе данных
 func (r *Repository) Create(ctx context.Context, reception *domain.Reception) (*domain.Reception, error) {
-	tx, err := r.db.BeginTxx(ctx, nil)
+	defer db.ObserveQuery(ctx, "reception", "Create")()
+
+	tx, err := db.BeginTx(ctx, r.db)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка начала транзакции: %w", err)
 	}
@@ -31,7 +34,7 @@ func (r *Repository) Create(ctx context.Context, reception *domain.Reception) (*
 	query := `
 		INSERT INTO reception (date_time, pvz_id, status) 
 		VALUES (:date_time, :pvz_id, :status) 
-		RETURNING id, date_time, pvz_id, status
+		RETURNING id, date_time, pvz_id, status, closed_at, closed_by, close_reason, stale_since
 	`
 
 	stmt, err := tx.PrepareNamedContext(ctx, query)
@@ -7,15 +7,18 @@ import (
 	"fmt"
 
 	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
 )
 
 // GetByID получает приемку по её идентификатору
 func (r *Repository) GetByID(ctx context.Context, id string) (*domain.Reception, error) {
-	query := `SELECT id, date_time, pvz_id, status FROM reception WHERE id = $1`
+	defer db.ObserveQuery(ctx, "reception", "GetByID")()
+
+	query := `SELECT id, date_time, pvz_id, status, closed_at, closed_by, close_reason, stale_since FROM reception WHERE id = $1`
 
 	model := &models.ReceptionModel{}
-	err := r.db.GetContext(ctx, model, query, id)
+	err := db.Conn(ctx, r.db).GetContext(ctx, model, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("приемка с ID %s не найдена", id)
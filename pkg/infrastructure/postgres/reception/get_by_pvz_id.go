@@ -5,20 +5,23 @@ import (
 	"fmt"
 
 	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
 )
 
 // GetByPVZID получает список всех приемок для конкретного ПВЗ
 func (r *Repository) GetByPVZID(ctx context.Context, pvzID string) ([]*domain.Reception, error) {
+	defer db.ObserveQuery(ctx, "reception", "GetByPVZID")()
+
 	query := `
-		SELECT id, date_time, pvz_id, status 
+		SELECT id, date_time, pvz_id, status, closed_at, closed_by, close_reason, stale_since 
 		FROM reception 
 		WHERE pvz_id = $1 
 		ORDER BY date_time DESC
 	`
 
 	var receptionModels []models.ReceptionModel
-	err := r.db.SelectContext(ctx, &receptionModels, query, pvzID)
+	err := db.Conn(ctx, r.db).SelectContext(ctx, &receptionModels, query, pvzID)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка при получении приемок для ПВЗ: %w", err)
 	}
@@ -7,13 +7,16 @@ import (
 	"fmt"
 
 	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
 )
 
 // GetLastActiveByPVZID получает последнюю активную приемку для конкретного ПВЗ
 func (r *Repository) GetLastActiveByPVZID(ctx context.Context, pvzID string) (*domain.Reception, error) {
+	defer db.ObserveQuery(ctx, "reception", "GetLastActiveByPVZID")()
+
 	query := `
-		SELECT id, date_time, pvz_id, status 
+		SELECT id, date_time, pvz_id, status, closed_at, closed_by, close_reason, stale_since 
 		FROM reception 
 		WHERE pvz_id = $1 AND status = $2 
 		ORDER BY date_time DESC 
@@ -21,7 +24,7 @@ func (r *Repository) GetLastActiveByPVZID(ctx context.Context, pvzID string) (*d
 	`
 
 	model := &models.ReceptionModel{}
-	err := r.db.GetContext(ctx, model, query, pvzID, domain.ReceptionStatusInProgress)
+	err := db.Conn(ctx, r.db).GetContext(ctx, model, query, pvzID, domain.ReceptionStatusInProgress)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("активная приемка для ПВЗ %s не найдена", pvzID)
@@ -5,11 +5,14 @@ import (
 	"fmt"
 
 	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
 )
 
 // getProductsByReceptionID получает список товаров для конкретной приемки
 func (r *Repository) getProductsByReceptionID(ctx context.Context, receptionID string) ([]domain.Product, error) {
+	defer db.ObserveQuery(ctx, "reception", "getProductsByReceptionID")()
+
 	query := `
 		SELECT p.id, p.date_time, p.type, p.reception_id
 		FROM product p
@@ -19,7 +22,7 @@ func (r *Repository) getProductsByReceptionID(ctx context.Context, receptionID s
 	`
 
 	var productModels []models.ProductModel
-	err := r.db.SelectContext(ctx, &productModels, query, receptionID)
+	err := db.Conn(ctx, r.db).SelectContext(ctx, &productModels, query, receptionID)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка при получении товаров для приемки: %w", err)
 	}
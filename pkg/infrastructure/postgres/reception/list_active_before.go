@@ -0,0 +1,36 @@
+package reception
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
+)
+
+// ListActiveBefore получает незакрытые приемки, открытые раньше указанного времени
+func (r *Repository) ListActiveBefore(ctx context.Context, before time.Time) ([]*domain.Reception, error) {
+	defer db.ObserveQuery(ctx, "reception", "ListActiveBefore")()
+
+	query := `
+		SELECT id, date_time, pvz_id, status, closed_at, closed_by, close_reason, stale_since
+		FROM reception
+		WHERE status = $1 AND date_time < $2
+		ORDER BY date_time
+	`
+
+	var receptionModels []models.ReceptionModel
+	err := db.Conn(ctx, r.db).SelectContext(ctx, &receptionModels, query, domain.ReceptionStatusInProgress, before)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении незакрытых приемок: %w", err)
+	}
+
+	result := make([]*domain.Reception, 0, len(receptionModels))
+	for _, model := range receptionModels {
+		result = append(result, model.ToEntity())
+	}
+
+	return result, nil
+}
@@ -4,13 +4,19 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
 	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
 )
 
-// Update обновляет информацию о приемке в базе данных
+// Update обновляет информацию о приемке в базе данных. Условие на статус
+// защищает от гонки: если приемку закрыли после чтения, обновление не
+// применяется, и закрытая приемка не переоткрывается и не закрывается повторно
 func (r *Repository) Update(ctx context.Context, reception *domain.Reception) error {
-	tx, err := r.db.BeginTxx(ctx, nil)
+	defer db.ObserveQuery(ctx, "reception", "Update")()
+
+	tx, err := db.BeginTx(ctx, r.db)
 	if err != nil {
 		return fmt.Errorf("ошибка начала транзакции: %w", err)
 	}
@@ -25,8 +31,10 @@ func (r *Repository) Update(ctx context.Context, reception *domain.Reception) er
 
 	query := `
 		UPDATE reception 
-		SET status = :status, date_time = :date_time 
-		WHERE id = :id
+		SET status = :status, date_time = :date_time,
+			closed_at = :closed_at, closed_by = :closed_by, close_reason = :close_reason,
+			stale_since = :stale_since
+		WHERE id = :id AND status = 'in_progress'
 	`
 
 	stmt, err := tx.PrepareNamedContext(ctx, query)
@@ -47,7 +55,17 @@ func (r *Repository) Update(ctx context.Context, reception *domain.Reception) er
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("приемка с ID %s не найдена", reception.ID)
+		var exists bool
+		err = tx.GetContext(ctx, &exists, `SELECT EXISTS(SELECT 1 FROM reception WHERE id = $1)`, reception.ID)
+		if err != nil {
+			return fmt.Errorf("ошибка проверки приемки: %w", err)
+		}
+		if exists {
+			err = fmt.Errorf("приемка с ID %s: %w", reception.ID, repositories.ErrReceptionNotActive)
+			return err
+		}
+		err = fmt.Errorf("приемка с ID %s не найдена", reception.ID)
+		return err
 	}
 
 	if err = tx.Commit(); err != nil {
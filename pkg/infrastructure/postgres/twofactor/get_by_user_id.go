@@ -0,0 +1,35 @@
+package twofactor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
+)
+
+// GetByUserID получает настройки 2FA пользователя
+func (r *Repository) GetByUserID(ctx context.Context, userID string) (*domain.TwoFactor, error) {
+	defer db.ObserveQuery(ctx, "twofactor", "GetByUserID")()
+
+	query := `
+		SELECT user_id, secret, enabled_at, last_used_step, recovery_code_hashes, created_at
+		FROM user_two_factor
+		WHERE user_id = $1
+	`
+
+	model := &models.TwoFactorModel{}
+	err := db.Conn(ctx, r.db).GetContext(ctx, model, query, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ошибка получения настроек 2FA: %w", err)
+	}
+
+	result := model.ToEntity()
+	return result, nil
+}
@@ -0,0 +1,38 @@
+package twofactor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+)
+
+// RegisterChallengeAttempt увеличивает счетчик попыток промежуточного токена
+// и попутно удаляет истекшие токены пользователя
+func (r *Repository) RegisterChallengeAttempt(ctx context.Context, challengeID, userID string, expiresAt time.Time) (int, error) {
+	defer db.ObserveQuery(ctx, "twofactor", "RegisterChallengeAttempt")()
+
+	conn := db.Conn(ctx, r.db)
+
+	_, err := conn.ExecContext(ctx, `DELETE FROM two_factor_challenges WHERE user_id = $1 AND expires_at < NOW()`, userID)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка удаления истекших попыток входа: %w", err)
+	}
+
+	query := `
+		INSERT INTO two_factor_challenges (challenge_id, user_id, attempts, expires_at)
+		VALUES ($1, $2, 1, $3)
+		ON CONFLICT (challenge_id) DO UPDATE
+		SET attempts = two_factor_challenges.attempts + 1
+		RETURNING attempts
+	`
+
+	var attempts int
+	err = conn.QueryRowxContext(ctx, query, challengeID, userID, expiresAt).Scan(&attempts)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка учета попытки входа: %w", err)
+	}
+
+	return attempts, nil
+}
@@ -0,0 +1,41 @@
+package twofactor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
+)
+
+// Save создает или полностью перезаписывает настройки 2FA пользователя
+func (r *Repository) Save(ctx context.Context, twoFactor *domain.TwoFactor) error {
+	defer db.ObserveQuery(ctx, "twofactor", "Save")()
+
+	if twoFactor.CreatedAt.IsZero() {
+		twoFactor.CreatedAt = time.Now()
+	}
+
+	model := &models.TwoFactorModel{}
+	model.FromEntity(twoFactor)
+
+	query := `
+		INSERT INTO user_two_factor (user_id, secret, enabled_at, last_used_step, recovery_code_hashes, created_at)
+		VALUES (:user_id, :secret, :enabled_at, :last_used_step, :recovery_code_hashes, :created_at)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret,
+			enabled_at = EXCLUDED.enabled_at,
+			last_used_step = EXCLUDED.last_used_step,
+			recovery_code_hashes = EXCLUDED.recovery_code_hashes,
+			created_at = EXCLUDED.created_at
+	`
+
+	_, err := db.Conn(ctx, r.db).NamedExecContext(ctx, query, model)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения настроек 2FA: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package twofactor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+)
+
+// UseRecoveryCode удаляет хэш кода восстановления. Из двух параллельных
+// запросов с одним кодом строку изменит только первый
+func (r *Repository) UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	defer db.ObserveQuery(ctx, "twofactor", "UseRecoveryCode")()
+
+	query := `
+		UPDATE user_two_factor
+		SET recovery_code_hashes = array_remove(recovery_code_hashes, $2)
+		WHERE user_id = $1 AND enabled_at IS NOT NULL AND $2 = ANY(recovery_code_hashes)
+	`
+
+	result, err := db.Conn(ctx, r.db).ExecContext(ctx, query, userID, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("ошибка погашения кода восстановления: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("ошибка погашения кода восстановления: %w", err)
+	}
+
+	return affected == 1, nil
+}
@@ -0,0 +1,31 @@
+package twofactor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+)
+
+// UseStep фиксирует принятый интервал, только если он новее последнего использованного
+func (r *Repository) UseStep(ctx context.Context, userID string, step int64) (bool, error) {
+	defer db.ObserveQuery(ctx, "twofactor", "UseStep")()
+
+	query := `
+		UPDATE user_two_factor
+		SET last_used_step = $2
+		WHERE user_id = $1 AND enabled_at IS NOT NULL AND last_used_step < $2
+	`
+
+	result, err := db.Conn(ctx, r.db).ExecContext(ctx, query, userID, step)
+	if err != nil {
+		return false, fmt.Errorf("ошибка фиксации кода 2FA: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("ошибка фиксации кода 2FA: %w", err)
+	}
+
+	return affected == 1, nil
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 
 	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
 	"github.com/google/uuid"
 )
 
 // Create создает нового пользователя в базе данных
 func (r *Repository) Create(ctx context.Context, user domain.User) (domain.User, error) {
+	defer db.ObserveQuery(ctx, "user", "Create")()
+
 	query := `
 		INSERT INTO users (id, email, password_hash, role, created_at) 
 		VALUES ($1, $2, $3, $4, $5)
@@ -19,7 +22,7 @@ func (r *Repository) Create(ctx context.Context, user domain.User) (domain.User,
 	id := uuid.New().String()
 
 	var userModel models.UserModel
-	err := r.db.QueryRowxContext(
+	err := db.Conn(ctx, r.db).QueryRowxContext(
 		ctx,
 		query,
 		id,
@@ -1,15 +1,21 @@
 package user
 
-import "context"
+import (
+	"context"
+
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+)
 
 // Exists проверяет, существует ли пользователь с данным email
 func (r *Repository) Exists(ctx context.Context, email string) (bool, error) {
+	defer db.ObserveQuery(ctx, "user", "Exists")()
+
 	query := `
 		SELECT EXISTS(SELECT 1 FROM users WHERE email = $1)
 	`
 
 	var exists bool
-	err := r.db.QueryRowxContext(ctx, query, email).Scan(&exists)
+	err := db.Conn(ctx, r.db).QueryRowxContext(ctx, query, email).Scan(&exists)
 
 	return exists, err
 }
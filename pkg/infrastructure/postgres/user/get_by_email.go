@@ -6,11 +6,14 @@ import (
 	"errors"
 
 	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
 	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
 )
 
 // GetByEmail получает пользователя по email
 func (r *Repository) GetByEmail(ctx context.Context, email string) (domain.User, error) {
+	defer db.ObserveQuery(ctx, "user", "GetByEmail")()
+
 	query := `
 		SELECT id, email, password_hash, role, created_at
 		FROM users 
@@ -18,7 +21,7 @@ func (r *Repository) GetByEmail(ctx context.Context, email string) (domain.User,
 	`
 
 	var userModel models.UserModel
-	err := r.db.QueryRowxContext(ctx, query, email).StructScan(&userModel)
+	err := db.Conn(ctx, r.db).QueryRowxContext(ctx, query, email).StructScan(&userModel)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
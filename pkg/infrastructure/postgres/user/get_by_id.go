@@ -0,0 +1,34 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
+)
+
+// GetByID получает пользователя по идентификатору
+func (r *Repository) GetByID(ctx context.Context, id string) (domain.User, error) {
+	defer db.ObserveQuery(ctx, "user", "GetByID")()
+
+	query := `
+		SELECT id, email, password_hash, role, created_at
+		FROM users 
+		WHERE id = $1
+	`
+
+	var userModel models.UserModel
+	err := db.Conn(ctx, r.db).QueryRowxContext(ctx, query, id).StructScan(&userModel)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.User{}, errors.New("user not found")
+		}
+		return domain.User{}, err
+	}
+
+	return userModel.ToDomain(), nil
+}
@@ -0,0 +1,49 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
+)
+
+// Create сохраняет новый вебхук
+func (r *Repository) Create(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	defer db.ObserveQuery(ctx, "webhook", "Create")()
+
+	if webhook.ID == "" {
+		webhook.ID = uuid.New().String()
+	}
+
+	if webhook.CreatedAt.IsZero() {
+		webhook.CreatedAt = time.Now()
+	}
+
+	model := &models.WebhookModel{}
+	model.FromEntity(webhook)
+
+	query := `
+		INSERT INTO webhooks (id, url, event_types, secret, created_by, created_at)
+		VALUES (:id, :url, :event_types, :secret, :created_by, :created_at)
+		RETURNING id, url, event_types, secret, created_by, created_at
+	`
+
+	stmt, err := db.Conn(ctx, r.db).PrepareNamedContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка подготовки запроса: %w", err)
+	}
+	defer stmt.Close()
+
+	err = stmt.QueryRowxContext(ctx, model).StructScan(model)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания вебхука: %w", err)
+	}
+
+	result := model.ToEntity()
+	return result, nil
+}
@@ -0,0 +1,29 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+)
+
+// Delete удаляет вебхук. Журнал доставок удаляется каскадно
+func (r *Repository) Delete(ctx context.Context, id string) error {
+	defer db.ObserveQuery(ctx, "webhook", "Delete")()
+
+	result, err := db.Conn(ctx, r.db).ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("ошибка при удалении вебхука: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка получения количества удаленных записей: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("вебхук с ID %s не найден", id)
+	}
+
+	return nil
+}
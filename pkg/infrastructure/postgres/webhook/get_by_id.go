@@ -0,0 +1,36 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
+)
+
+// GetByID получает вебхук по идентификатору
+func (r *Repository) GetByID(ctx context.Context, id string) (*domain.Webhook, error) {
+	defer db.ObserveQuery(ctx, "webhook", "GetByID")()
+
+	query := `
+		SELECT id, url, event_types, secret, created_by, created_at
+		FROM webhooks
+		WHERE id = $1
+	`
+
+	model := &models.WebhookModel{}
+	err := db.Conn(ctx, r.db).GetContext(ctx, model, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("вебхук с ID %s не найден: %w", id, repositories.ErrNotFound)
+		}
+		return nil, fmt.Errorf("ошибка получения вебхука: %w", err)
+	}
+
+	result := model.ToEntity()
+	return result, nil
+}
@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
+)
+
+// List возвращает все вебхуки, начиная с последних созданных
+func (r *Repository) List(ctx context.Context) ([]*domain.Webhook, error) {
+	defer db.ObserveQuery(ctx, "webhook", "List")()
+
+	query := `
+		SELECT id, url, event_types, secret, created_by, created_at
+		FROM webhooks
+		ORDER BY created_at DESC
+	`
+
+	var webhookModels []models.WebhookModel
+	err := db.Conn(ctx, r.db).SelectContext(ctx, &webhookModels, query)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении списка вебхуков: %w", err)
+	}
+
+	return toEntities(webhookModels), nil
+}
+
+// ListByEventType возвращает вебхуки, подписанные на тип события
+func (r *Repository) ListByEventType(ctx context.Context, eventType string) ([]*domain.Webhook, error) {
+	defer db.ObserveQuery(ctx, "webhook", "ListByEventType")()
+
+	query := `
+		SELECT id, url, event_types, secret, created_by, created_at
+		FROM webhooks
+		WHERE $1 = ANY(event_types)
+		ORDER BY created_at
+	`
+
+	var webhookModels []models.WebhookModel
+	err := db.Conn(ctx, r.db).SelectContext(ctx, &webhookModels, query, eventType)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении вебхуков события: %w", err)
+	}
+
+	return toEntities(webhookModels), nil
+}
+
+func toEntities(webhookModels []models.WebhookModel) []*domain.Webhook {
+	result := make([]*domain.Webhook, 0, len(webhookModels))
+	for _, model := range webhookModels {
+		result = append(result, model.ToEntity())
+	}
+	return result
+}
@@ -0,0 +1,45 @@
+package webhookdelivery
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
+)
+
+// Create добавляет доставку. Вызывается в транзакции публикации событий;
+// доставка события, уже поставленного этому вебхуку, пропускается
+func (r *Repository) Create(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	defer db.ObserveQuery(ctx, "webhookdelivery", "Create")()
+
+	if delivery.ID == "" {
+		delivery.ID = uuid.New().String()
+	}
+
+	if delivery.CreatedAt.IsZero() {
+		delivery.CreatedAt = time.Now()
+	}
+
+	model := &models.WebhookDeliveryModel{}
+	model.FromEntity(delivery)
+
+	query := `
+		INSERT INTO webhook_deliveries (id, webhook_id, event_id, event_type, payload, status, attempts,
+			next_attempt_at, created_at)
+		VALUES (:id, :webhook_id, :event_id, :event_type, :payload, :status, :attempts,
+			:next_attempt_at, :created_at)
+		ON CONFLICT (webhook_id, event_id) DO NOTHING
+	`
+
+	_, err := db.Conn(ctx, r.db).NamedExecContext(ctx, query, model)
+	if err != nil {
+		return fmt.Errorf("ошибка создания доставки вебхука: %w", err)
+	}
+
+	return nil
+}
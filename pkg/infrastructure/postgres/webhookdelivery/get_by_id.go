@@ -0,0 +1,31 @@
+package webhookdelivery
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
+)
+
+// GetByID получает доставку по идентификатору
+func (r *Repository) GetByID(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	defer db.ObserveQuery(ctx, "webhookdelivery", "GetByID")()
+
+	query := `SELECT ` + columns + ` FROM webhook_deliveries WHERE id = $1`
+
+	model := &models.WebhookDeliveryModel{}
+	err := db.Conn(ctx, r.db).GetContext(ctx, model, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("доставка с ID %s не найдена", id)
+		}
+		return nil, fmt.Errorf("ошибка получения доставки вебхука: %w", err)
+	}
+
+	result := model.ToEntity()
+	return result, nil
+}
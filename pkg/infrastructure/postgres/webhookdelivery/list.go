@@ -0,0 +1,36 @@
+package webhookdelivery
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
+)
+
+// List возвращает журнал доставок вебхука, начиная с последних
+func (r *Repository) List(ctx context.Context, filter repositories.WebhookDeliveryFilter) ([]*domain.WebhookDelivery, error) {
+	defer db.ObserveQuery(ctx, "webhookdelivery", "List")()
+
+	query := `SELECT ` + columns + ` FROM webhook_deliveries WHERE webhook_id = $1`
+	args := []interface{}{filter.WebhookID}
+
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		query += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+
+	offset := (filter.Page - 1) * filter.Limit
+	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, offset)
+
+	var rows []models.WebhookDeliveryModel
+	err := db.Conn(ctx, r.db).SelectContext(ctx, &rows, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении журнала доставок: %w", err)
+	}
+
+	return toEntities(rows), nil
+}
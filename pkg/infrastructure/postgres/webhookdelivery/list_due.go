@@ -0,0 +1,40 @@
+package webhookdelivery
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
+)
+
+// ListDue возвращает ожидающие доставки, которым пора отправляться
+func (r *Repository) ListDue(ctx context.Context, now time.Time, limit int) ([]*domain.WebhookDelivery, error) {
+	defer db.ObserveQuery(ctx, "webhookdelivery", "ListDue")()
+
+	query := `
+		SELECT ` + columns + `
+		FROM webhook_deliveries
+		WHERE status = $1 AND next_attempt_at <= $2
+		ORDER BY next_attempt_at
+		LIMIT $3
+	`
+
+	var rows []models.WebhookDeliveryModel
+	err := db.Conn(ctx, r.db).SelectContext(ctx, &rows, query, domain.DeliveryPending, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения доставок вебхуков: %w", err)
+	}
+
+	return toEntities(rows), nil
+}
+
+func toEntities(rows []models.WebhookDeliveryModel) []*domain.WebhookDelivery {
+	result := make([]*domain.WebhookDelivery, 0, len(rows))
+	for i := range rows {
+		result = append(result, rows[i].ToEntity())
+	}
+	return result
+}
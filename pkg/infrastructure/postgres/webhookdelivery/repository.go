@@ -0,0 +1,19 @@
+package webhookdelivery
+
+import (
+	"github.com/jmoiron/sqlx"
+)
+
+// колонки доставки в порядке полей models.WebhookDeliveryModel
+const columns = `id, webhook_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+		last_status_code, last_error, created_at, delivered_at`
+
+type Repository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
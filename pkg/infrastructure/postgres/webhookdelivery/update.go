@@ -0,0 +1,41 @@
+package webhookdelivery
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/db"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/postgres/models"
+)
+
+// Update сохраняет состояние доставки после попытки или повторной постановки в очередь
+func (r *Repository) Update(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	defer db.ObserveQuery(ctx, "webhookdelivery", "Update")()
+
+	model := &models.WebhookDeliveryModel{}
+	model.FromEntity(delivery)
+
+	query := `
+		UPDATE webhook_deliveries
+		SET status = :status, attempts = :attempts, next_attempt_at = :next_attempt_at,
+			last_status_code = :last_status_code, last_error = :last_error, delivered_at = :delivered_at
+		WHERE id = :id
+	`
+
+	result, err := db.Conn(ctx, r.db).NamedExecContext(ctx, query, model)
+	if err != nil {
+		return fmt.Errorf("ошибка при обновлении доставки вебхука: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка получения количества обновленных записей: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("доставка с ID %s не найдена", delivery.ID)
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+// Package tracing настраивает OpenTelemetry: провайдер трассировки, экспортер и W3C пропагацию
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/dkumancev/avito-pvz/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// Экспортеры трассировки
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// ShutdownFunc отправляет накопленные спаны и останавливает провайдер
+type ShutdownFunc func(ctx context.Context) error
+
+// Init устанавливает глобальный провайдер трассировки и W3C propagator (traceparent, baggage).
+// Propagator устанавливается всегда, чтобы входящий контекст передавался дальше
+// даже при выключенном экспорте
+func Init(ctx context.Context, cfg config.TracingConfig) (ShutdownFunc, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.Exporter {
+	case ExporterNone, "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterOTLP:
+		exporter, err = otlptracegrpc.New(ctx,
+			otlptracegrpc.WithEndpoint(cfg.OTLPEndpoint),
+			otlptracegrpc.WithInsecure(),
+		)
+	default:
+		return nil, fmt.Errorf("неизвестный экспортер трассировки %q", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания экспортера трассировки: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания ресурса трассировки: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
@@ -0,0 +1,103 @@
+// Package webhooks отправляет доставки вебхуков по HTTP
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/webhooks"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/infrastructure/buildinfo"
+)
+
+// maxResponseBody - сколько байт ответа получателя читается, чтобы
+// переиспользовать соединение; сам ответ не сохраняется
+const maxResponseBody = 64 << 10
+
+// HTTPSender отправляет доставку POST-запросом с подписью в заголовках
+type HTTPSender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewHTTPSender возвращает отправителя, который не соединяется с адресами
+// внутренней сети. Адрес проверяется после разрешения имени, непосредственно
+// перед соединением, поэтому имя, которое после проверки при создании
+// вебхука стало указывать на внутренний адрес, тоже отклоняется
+func NewHTTPSender(timeout time.Duration) *HTTPSender {
+	return newHTTPSender(timeout, denyInternal)
+}
+
+// newHTTPSender позволяет тестам отключить проверку адреса (control == nil)
+func newHTTPSender(timeout time.Duration, control func(network, address string, c syscall.RawConn) error) *HTTPSender {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// через прокси проверялся бы адрес прокси, а не получателя
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+		Control:   control,
+	}).DialContext
+
+	return &HTTPSender{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			// перенаправление могло бы увести подписанный запрос на другой адрес
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		now: time.Now,
+	}
+}
+
+// denyInternal запрещает соединение с адресом внутренней сети
+func denyInternal(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("неверный адрес получателя %s: %w", address, err)
+	}
+	if domain.IsInternalAddress(addrPort.Addr()) {
+		return fmt.Errorf("адрес получателя %s относится к внутренней сети", addrPort.Addr())
+	}
+	return nil
+}
+
+// Send считает доставку успешной при ответе 2xx
+func (s *HTTPSender) Send(ctx context.Context, req webhooks.Request) (int, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return 0, fmt.Errorf("ошибка формирования запроса: %w", err)
+	}
+
+	timestamp := s.now().Unix()
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", "pvz-webhooks/"+buildinfo.Get().Version)
+	httpReq.Header.Set(webhooks.HeaderEventType, req.EventType)
+	httpReq.Header.Set(webhooks.HeaderEventID, req.EventID)
+	httpReq.Header.Set(webhooks.HeaderDelivery, req.DeliveryID)
+	httpReq.Header.Set(webhooks.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	httpReq.Header.Set(webhooks.HeaderSignature, webhooks.Sign(req.Secret, timestamp, req.Body))
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка отправки запроса: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("получатель ответил %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,179 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dkumancev/avito-pvz/pkg/application/jobs"
+	"github.com/dkumancev/avito-pvz/pkg/application/webhooks"
+	"github.com/dkumancev/avito-pvz/pkg/domain"
+	"github.com/dkumancev/avito-pvz/pkg/tests"
+)
+
+const testSecret = "0123456789abcdef"
+
+// receiver - получатель вебхуков, проверяющий подпись
+type receiver struct {
+	mu        sync.Mutex
+	failFirst int
+	received  []string
+	invalid   int
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	body, _ := io.ReadAll(r.Body)
+	timestamp, _ := strconv.ParseInt(r.Header.Get(webhooks.HeaderTimestamp), 10, 64)
+	if !webhooks.Verify(testSecret, timestamp, body, r.Header.Get(webhooks.HeaderSignature)) {
+		rc.invalid++
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if rc.failFirst > 0 {
+		rc.failFirst--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	rc.received = append(rc.received, r.Header.Get(webhooks.HeaderEventType))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestHTTPSender_SignsRequest(t *testing.T) {
+	rc := &receiver{}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+
+	sender := newHTTPSender(time.Second, nil)
+	status, err := sender.Send(context.Background(), webhooks.Request{
+		URL:       server.URL,
+		Secret:    testSecret,
+		EventType: domain.EventPVZCreated,
+		Body:      []byte(`{"id":"event-1"}`),
+	})
+	if err != nil || status != http.StatusNoContent {
+		t.Fatalf("Ожидалась успешная доставка, получено: %d, %v", status, err)
+	}
+
+	status, err = sender.Send(context.Background(), webhooks.Request{
+		URL:    server.URL,
+		Secret: "another-secret-value",
+		Body:   []byte(`{"id":"event-1"}`),
+	})
+	if err == nil || status != http.StatusUnauthorized || rc.invalid != 1 {
+		t.Errorf("Получатель должен отклонить чужую подпись: %d, %v", status, err)
+	}
+}
+
+func TestHTTPSender_DoesNotFollowRedirects(t *testing.T) {
+	target := &receiver{}
+	targetServer := httptest.NewServer(target)
+	defer targetServer.Close()
+	redirect := httptest.NewServer(http.RedirectHandler(targetServer.URL, http.StatusTemporaryRedirect))
+	defer redirect.Close()
+
+	status, err := newHTTPSender(time.Second, nil).Send(context.Background(), webhooks.Request{
+		URL: redirect.URL, Secret: testSecret, Body: []byte(`{}`),
+	})
+	if err == nil || status != http.StatusTemporaryRedirect || len(target.received) != 0 {
+		t.Errorf("Перенаправление должно считаться неудачной доставкой: %d, %v", status, err)
+	}
+}
+
+func TestHTTPSender_RejectsInternalAddresses(t *testing.T) {
+	rc := &receiver{}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+
+	// httptest слушает loopback; имя localhost разрешается в тот же адрес
+	// уже при соединении, поэтому проверка при создании вебхука его не заменяет
+	for _, url := range []string{server.URL, strings.Replace(server.URL, "127.0.0.1", "localhost", 1)} {
+		_, err := NewHTTPSender(time.Second).Send(context.Background(), webhooks.Request{
+			URL: url, Secret: testSecret, Body: []byte(`{}`),
+		})
+		if err == nil {
+			t.Errorf("Отправка на %s должна быть отклонена", url)
+		}
+	}
+	if len(rc.received) != 0 || rc.invalid != 0 {
+		t.Errorf("Запрос не должен дойти до получателя")
+	}
+}
+
+func TestDenyInternal(t *testing.T) {
+	cases := []struct {
+		address string
+		denied  bool
+	}{
+		{"127.0.0.1:443", true},
+		{"10.0.0.1:443", true},
+		{"169.254.169.254:80", true},
+		{"0.1.2.3:443", true},
+		{"100.64.0.1:443", true},
+		{"100.127.255.254:443", true},
+		{"198.18.0.1:443", true},
+		{"[::ffff:100.64.0.1]:443", true},
+		{"[::1]:443", true},
+		{"100.128.0.1:443", false},
+		{"198.20.0.1:443", false},
+		{"93.184.216.34:443", false},
+	}
+
+	for _, tt := range cases {
+		err := denyInternal("tcp", tt.address, nil)
+		if (err != nil) != tt.denied {
+			t.Errorf("%s: ожидался запрет %v, получено %v", tt.address, tt.denied, err)
+		}
+	}
+}
+
+// Доставка события от постановки в очередь до получателя с повтором после 503
+func TestWebhookDelivery_EndToEnd(t *testing.T) {
+	ctx := context.Background()
+	rc := &receiver{failFirst: 1}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+
+	webhookRepo := tests.NewMockWebhookRepository()
+	deliveryRepo := tests.NewMockWebhookDeliveryRepository()
+	webhook, _ := domain.NewWebhook("https://partner.example/hooks", []string{domain.EventReceptionClosed}, testSecret, "")
+	// httptest слушает loopback, который NewWebhook не принимает
+	webhook.URL = server.URL
+	webhookRepo.Create(ctx, webhook)
+
+	event, _ := domain.NewEvent(domain.EventReceptionClosed, domain.AuditEntityReception, "reception-1", map[string]string{})
+	event.ID = "event-1"
+	err := webhooks.NewEnqueuer(webhookRepo, deliveryRepo).Publish(ctx, []*domain.Event{event})
+	if err != nil {
+		t.Fatalf("Ошибка постановки доставки: %v", err)
+	}
+
+	dispatcher := jobs.NewWebhookDispatcher(webhookRepo, deliveryRepo, newHTTPSender(time.Second, nil),
+		jobs.WebhookDispatcherConfig{BatchSize: 10, MaxAttempts: 3}, tests.NewTestLogger())
+
+	// нулевая задержка повтора: вторая попытка выполняется следующим запуском
+	for i := 0; i < 2; i++ {
+		err = dispatcher.Run(ctx)
+		if err != nil {
+			t.Fatalf("Ошибка отправки: %v", err)
+		}
+	}
+
+	delivery := deliveryRepo.Deliveries[0]
+	if delivery.Status != domain.DeliveryDelivered || delivery.Attempts != 2 || delivery.LastStatusCode != http.StatusNoContent {
+		t.Errorf("Ожидалась доставка со второй попытки, получено: %+v", delivery)
+	}
+	if len(rc.received) != 1 || rc.received[0] != domain.EventReceptionClosed {
+		t.Errorf("Получатель должен получить событие один раз: %v", rc.received)
+	}
+}
@@ -25,9 +25,10 @@ func TestFullReceptionProcess(t *testing.T) {
 	mockPVZRepo := NewMockPVZRepository()
 	mockReceptionRepo := NewMockReceptionRepository()
 	mockProductRepo := NewMockProductRepository()
+	businessMetrics := NewMockBusinessMetrics()
 
-	pvzService := services.NewPVZService(mockPVZRepo)
-	receptionService := services.NewReceptionService(mockPVZRepo, mockReceptionRepo, mockProductRepo)
+	pvzService := services.NewPVZService(mockPVZRepo, NewMockAuditRepository(), NewMockOutboxRepository(), NewMockTransactor(), businessMetrics, NewTestLogger())
+	receptionService := services.NewReceptionService(mockPVZRepo, mockReceptionRepo, mockProductRepo, NewMockAssignmentRepository(), NewMockAuditRepository(), NewMockOutboxRepository(), NewMockTransactor(), NewTestPolicy(), businessMetrics, NewTestLogger())
 
 	// Act & Assert
 
@@ -93,6 +94,18 @@ func TestFullReceptionProcess(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error when adding product to closed reception, got nil")
 	}
+
+	// 5. Бизнес-метрики
+	if businessMetrics.CreatedPVZs["Москва"] != 1 {
+		t.Errorf("Expected 1 PVZ created metric, got %d", businessMetrics.CreatedPVZs["Москва"])
+	}
+	if businessMetrics.OpenedReceptions["Москва"] != 1 || businessMetrics.ClosedReceptions["Москва"] != 1 {
+		t.Errorf("Expected 1 opened and 1 closed reception, got %d and %d",
+			businessMetrics.OpenedReceptions["Москва"], businessMetrics.ClosedReceptions["Москва"])
+	}
+	if businessMetrics.AddedProducts["Москва/"+domain.ProductTypeElectronics] != 17 {
+		t.Errorf("Expected 17 electronics added, got %d", businessMetrics.AddedProducts["Москва/"+domain.ProductTypeElectronics])
+	}
 }
 
 // TestFullReceptionProcessWithAuth интеграционный тест с проверкой авторизации и ролей
@@ -107,13 +120,14 @@ func TestFullReceptionProcessWithAuth(t *testing.T) {
 	mockPVZRepo := NewMockPVZRepository()
 	mockReceptionRepo := NewMockReceptionRepository()
 	mockProductRepo := NewMockProductRepository()
+	businessMetrics := NewMockBusinessMetrics()
 
 	// создаем серамсы
 	jwtSecret := []byte("test-secret")
 	tokenDuration := 24 * time.Hour
-	userService := services.NewUserService(mockUserRepo, jwtSecret, tokenDuration)
-	pvzService := services.NewPVZService(mockPVZRepo)
-	receptionService := services.NewReceptionService(mockPVZRepo, mockReceptionRepo, mockProductRepo)
+	userService := services.NewUserService(mockUserRepo, NewMockTwoFactorRepository(), NewMockAuditRepository(), NewMockTransactor(), jwtSecret, tokenDuration)
+	pvzService := services.NewPVZService(mockPVZRepo, NewMockAuditRepository(), NewMockOutboxRepository(), NewMockTransactor(), businessMetrics, NewTestLogger())
+	receptionService := services.NewReceptionService(mockPVZRepo, mockReceptionRepo, mockProductRepo, NewMockAssignmentRepository(), NewMockAuditRepository(), NewMockOutboxRepository(), NewMockTransactor(), NewTestPolicy(), businessMetrics, NewTestLogger())
 
 	// 1. Регистрация пользователей с разными ролями
 	moderator, err := userService.Register(ctx, "moderator@example.com", "password123", domain.ModeratorRole)
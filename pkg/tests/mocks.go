@@ -3,7 +3,13 @@ package tests
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
 
+	"github.com/dkumancev/avito-pvz/pkg/application/auth"
 	"github.com/dkumancev/avito-pvz/pkg/application/repositories"
 	"github.com/dkumancev/avito-pvz/pkg/domain"
 )
@@ -24,6 +30,11 @@ func (m *MockPVZRepository) Create(ctx context.Context, pvz *domain.PVZ) (*domai
 	return pvz, nil
 }
 
+// Add сохраняет ПВЗ под его собственным ID, в отличие от Create
+func (m *MockPVZRepository) Add(pvz *domain.PVZ) {
+	m.pvzs[pvz.ID] = pvz
+}
+
 func (m *MockPVZRepository) GetByID(ctx context.Context, id string) (*domain.PVZ, error) {
 	pvz, ok := m.pvzs[id]
 	if !ok {
@@ -42,20 +53,30 @@ func (m *MockPVZRepository) List(ctx context.Context, filter repositories.PVZFil
 
 type MockReceptionRepository struct {
 	receptions map[string]*domain.Reception
+	// закрытые в хранилище приемки: Update для них не применяется, как в PostgreSQL
+	closed map[string]bool
 }
 
 func NewMockReceptionRepository() *MockReceptionRepository {
 	return &MockReceptionRepository{
 		receptions: make(map[string]*domain.Reception),
+		closed:     make(map[string]bool),
 	}
 }
 
 func (m *MockReceptionRepository) Create(ctx context.Context, reception *domain.Reception) (*domain.Reception, error) {
 	reception.ID = "mock-reception-id"
 	m.receptions[reception.ID] = reception
+	m.closed[reception.ID] = !reception.IsActive()
 	return reception, nil
 }
 
+// Add сохраняет приемку под ее собственным ID, в отличие от Create
+func (m *MockReceptionRepository) Add(reception *domain.Reception) {
+	m.receptions[reception.ID] = reception
+	m.closed[reception.ID] = !reception.IsActive()
+}
+
 func (m *MockReceptionRepository) GetByID(ctx context.Context, id string) (*domain.Reception, error) {
 	reception, ok := m.receptions[id]
 	if !ok {
@@ -69,7 +90,11 @@ func (m *MockReceptionRepository) Update(ctx context.Context, reception *domain.
 	if _, ok := m.receptions[reception.ID]; !ok {
 		return errors.New("reception not found")
 	}
+	if m.closed[reception.ID] {
+		return repositories.ErrReceptionNotActive
+	}
 	m.receptions[reception.ID] = reception
+	m.closed[reception.ID] = !reception.IsActive()
 	return nil
 }
 
@@ -92,6 +117,16 @@ func (m *MockReceptionRepository) GetByPVZID(ctx context.Context, pvzID string)
 	return result, nil
 }
 
+func (m *MockReceptionRepository) ListActiveBefore(ctx context.Context, before time.Time) ([]*domain.Reception, error) {
+	var result []*domain.Reception
+	for _, reception := range m.receptions {
+		if reception.IsActive() && reception.DateTime.Before(before) {
+			result = append(result, reception)
+		}
+	}
+	return result, nil
+}
+
 type MockProductRepository struct {
 	products          map[string]*domain.Product
 	receptionProducts map[string][]string
@@ -186,3 +221,471 @@ func (m *MockUserRepository) Exists(ctx context.Context, email string) (bool, er
 	_, ok := m.users[email]
 	return ok, nil
 }
+
+type MockAssignmentRepository struct {
+	assignments map[string]*domain.Assignment
+}
+
+func NewMockAssignmentRepository() *MockAssignmentRepository {
+	return &MockAssignmentRepository{
+		assignments: make(map[string]*domain.Assignment),
+	}
+}
+
+func (m *MockAssignmentRepository) Create(ctx context.Context, assignment *domain.Assignment) (*domain.Assignment, error) {
+	m.assignments[assignment.UserID+"/"+assignment.PVZID] = assignment
+	return assignment, nil
+}
+
+func (m *MockAssignmentRepository) Delete(ctx context.Context, userID, pvzID string) error {
+	key := userID + "/" + pvzID
+	if _, ok := m.assignments[key]; !ok {
+		return errors.New("assignment not found")
+	}
+	delete(m.assignments, key)
+	return nil
+}
+
+func (m *MockAssignmentRepository) GetByPVZID(ctx context.Context, pvzID string) ([]*domain.Assignment, error) {
+	var result []*domain.Assignment
+	for _, assignment := range m.assignments {
+		if assignment.PVZID == pvzID {
+			result = append(result, assignment)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockAssignmentRepository) Exists(ctx context.Context, userID, pvzID string) (bool, error) {
+	_, ok := m.assignments[userID+"/"+pvzID]
+	return ok, nil
+}
+
+type MockAPIKeyRepository struct {
+	keys map[string]*domain.APIKey
+}
+
+func NewMockAPIKeyRepository() *MockAPIKeyRepository {
+	return &MockAPIKeyRepository{
+		keys: make(map[string]*domain.APIKey),
+	}
+}
+
+func (m *MockAPIKeyRepository) Create(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error) {
+	key.ID = fmt.Sprintf("mock-api-key-%d", len(m.keys)+1)
+	m.keys[key.ID] = key
+	return key, nil
+}
+
+func (m *MockAPIKeyRepository) GetByID(ctx context.Context, id string) (*domain.APIKey, error) {
+	key, ok := m.keys[id]
+	if !ok {
+		return nil, errors.New("api key not found")
+	}
+	return key, nil
+}
+
+func (m *MockAPIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	for _, key := range m.keys {
+		if key.KeyHash == keyHash {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("api key not found: %w", repositories.ErrNotFound)
+}
+
+func (m *MockAPIKeyRepository) List(ctx context.Context) ([]*domain.APIKey, error) {
+	var result []*domain.APIKey
+	for _, key := range m.keys {
+		result = append(result, key)
+	}
+	return result, nil
+}
+
+func (m *MockAPIKeyRepository) Update(ctx context.Context, key *domain.APIKey) error {
+	if _, ok := m.keys[key.ID]; !ok {
+		return errors.New("api key not found")
+	}
+	m.keys[key.ID] = key
+	return nil
+}
+
+func (m *MockAPIKeyRepository) UpdateLastUsed(ctx context.Context, id string, usedAt time.Time) error {
+	key, ok := m.keys[id]
+	if !ok {
+		return errors.New("api key not found")
+	}
+	key.LastUsedAt = &usedAt
+	return nil
+}
+
+type MockTwoFactorRepository struct {
+	mu                sync.Mutex
+	settings          map[string]*domain.TwoFactor
+	challengeAttempts map[string]int
+}
+
+func NewMockTwoFactorRepository() *MockTwoFactorRepository {
+	return &MockTwoFactorRepository{
+		settings:          make(map[string]*domain.TwoFactor),
+		challengeAttempts: make(map[string]int),
+	}
+}
+
+func (m *MockTwoFactorRepository) GetByUserID(ctx context.Context, userID string) (*domain.TwoFactor, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	twoFactor, ok := m.settings[userID]
+	if !ok {
+		return nil, nil
+	}
+	// копия, чтобы изменения сервиса попадали в хранилище только через Save
+	result := *twoFactor
+	result.RecoveryCodeHashes = append([]string(nil), twoFactor.RecoveryCodeHashes...)
+	return &result, nil
+}
+
+func (m *MockTwoFactorRepository) Save(ctx context.Context, twoFactor *domain.TwoFactor) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	saved := *twoFactor
+	m.settings[twoFactor.UserID] = &saved
+	return nil
+}
+
+func (m *MockTwoFactorRepository) UseStep(ctx context.Context, userID string, step int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	twoFactor, ok := m.settings[userID]
+	if !ok || !twoFactor.IsEnabled() || step <= twoFactor.LastUsedStep {
+		return false, nil
+	}
+	twoFactor.LastUsedStep = step
+	return true, nil
+}
+
+func (m *MockTwoFactorRepository) UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	twoFactor, ok := m.settings[userID]
+	if !ok || !twoFactor.IsEnabled() {
+		return false, nil
+	}
+	for i, hash := range twoFactor.RecoveryCodeHashes {
+		if hash == codeHash {
+			twoFactor.RecoveryCodeHashes = append(twoFactor.RecoveryCodeHashes[:i:i], twoFactor.RecoveryCodeHashes[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockTwoFactorRepository) RegisterChallengeAttempt(ctx context.Context, challengeID, userID string, expiresAt time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.challengeAttempts[challengeID]++
+	return m.challengeAttempts[challengeID], nil
+}
+
+// MockBusinessMetrics считает бизнес-события; ключ товарных метрик - "город/тип"
+type MockBusinessMetrics struct {
+	CreatedPVZs        map[string]int
+	OpenedReceptions   map[string]int
+	ClosedReceptions   map[string]int
+	ReceptionDurations []time.Duration
+	AddedProducts      map[string]int
+	RemovedProducts    map[string]int
+	AutoClosed         map[string]int
+	MarkedStale        map[string]int
+}
+
+func NewMockBusinessMetrics() *MockBusinessMetrics {
+	return &MockBusinessMetrics{
+		CreatedPVZs:      make(map[string]int),
+		OpenedReceptions: make(map[string]int),
+		ClosedReceptions: make(map[string]int),
+		AddedProducts:    make(map[string]int),
+		RemovedProducts:  make(map[string]int),
+		AutoClosed:       make(map[string]int),
+		MarkedStale:      make(map[string]int),
+	}
+}
+
+func (m *MockBusinessMetrics) PVZCreated(city string) {
+	m.CreatedPVZs[city]++
+}
+
+func (m *MockBusinessMetrics) ReceptionOpened(city string) {
+	m.OpenedReceptions[city]++
+}
+
+func (m *MockBusinessMetrics) ReceptionClosed(city string, duration time.Duration) {
+	m.ClosedReceptions[city]++
+	m.ReceptionDurations = append(m.ReceptionDurations, duration)
+}
+
+func (m *MockBusinessMetrics) ProductAdded(city, productType string) {
+	m.AddedProducts[city+"/"+productType]++
+}
+
+func (m *MockBusinessMetrics) ProductRemoved(city, productType string) {
+	m.RemovedProducts[city+"/"+productType]++
+}
+
+func (m *MockBusinessMetrics) ReceptionAutoClosed(city string) {
+	m.AutoClosed[city]++
+}
+
+func (m *MockBusinessMetrics) ReceptionMarkedStale(city string) {
+	m.MarkedStale[city]++
+}
+
+type MockAuditRepository struct {
+	Entries []*domain.AuditEntry
+	// если задана, Create возвращает эту ошибку (проверка отката изменения)
+	Err error
+}
+
+func NewMockAuditRepository() *MockAuditRepository {
+	return &MockAuditRepository{}
+}
+
+func (m *MockAuditRepository) Create(ctx context.Context, entry *domain.AuditEntry) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	entry.ID = fmt.Sprintf("mock-audit-%d", len(m.Entries)+1)
+	m.Entries = append(m.Entries, entry)
+	return nil
+}
+
+func (m *MockAuditRepository) List(ctx context.Context, filter repositories.AuditFilter) ([]*domain.AuditEntry, error) {
+	var result []*domain.AuditEntry
+	for i := len(m.Entries) - 1; i >= 0; i-- {
+		entry := m.Entries[i]
+		if filter.ActorID != "" && entry.ActorID != filter.ActorID {
+			continue
+		}
+		if filter.EntityType != "" && entry.EntityType != filter.EntityType {
+			continue
+		}
+		if filter.EntityID != "" && entry.EntityID != filter.EntityID {
+			continue
+		}
+		if filter.From != nil && entry.CreatedAt.Before(*filter.From) {
+			continue
+		}
+		if filter.To != nil && entry.CreatedAt.After(*filter.To) {
+			continue
+		}
+		result = append(result, entry)
+	}
+	return result, nil
+}
+
+// MockOutboxRepository хранит события в порядке записи
+type MockOutboxRepository struct {
+	Events    []*domain.Event
+	Published map[string]bool
+	Attempts  map[string]int
+	// если задана, Add возвращает эту ошибку (проверка отката изменения)
+	Err error
+}
+
+func NewMockOutboxRepository() *MockOutboxRepository {
+	return &MockOutboxRepository{
+		Published: make(map[string]bool),
+		Attempts:  make(map[string]int),
+	}
+}
+
+func (m *MockOutboxRepository) Add(ctx context.Context, event *domain.Event) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	if event.ID == "" {
+		event.ID = fmt.Sprintf("mock-event-%d", len(m.Events)+1)
+	}
+	m.Events = append(m.Events, event)
+	return nil
+}
+
+func (m *MockOutboxRepository) ListPending(ctx context.Context, limit int) ([]*domain.Event, error) {
+	var result []*domain.Event
+	for _, event := range m.Events {
+		if len(result) == limit {
+			break
+		}
+		if !m.Published[event.ID] {
+			result = append(result, event)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockOutboxRepository) MarkPublished(ctx context.Context, ids []string) error {
+	for _, id := range ids {
+		m.Published[id] = true
+	}
+	return nil
+}
+
+func (m *MockOutboxRepository) MarkFailed(ctx context.Context, ids []string, reason string) error {
+	for _, id := range ids {
+		m.Attempts[id]++
+	}
+	return nil
+}
+
+func (m *MockOutboxRepository) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	var kept []*domain.Event
+	var deleted int64
+	for _, event := range m.Events {
+		if m.Published[event.ID] {
+			deleted++
+			continue
+		}
+		kept = append(kept, event)
+	}
+	m.Events = kept
+	return deleted, nil
+}
+
+type MockWebhookRepository struct {
+	webhooks []*domain.Webhook
+}
+
+func NewMockWebhookRepository() *MockWebhookRepository {
+	return &MockWebhookRepository{}
+}
+
+func (m *MockWebhookRepository) Create(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	webhook.ID = fmt.Sprintf("mock-webhook-%d", len(m.webhooks)+1)
+	m.webhooks = append(m.webhooks, webhook)
+	return webhook, nil
+}
+
+func (m *MockWebhookRepository) GetByID(ctx context.Context, id string) (*domain.Webhook, error) {
+	for _, webhook := range m.webhooks {
+		if webhook.ID == id {
+			return webhook, nil
+		}
+	}
+	return nil, fmt.Errorf("webhook not found: %w", repositories.ErrNotFound)
+}
+
+func (m *MockWebhookRepository) List(ctx context.Context) ([]*domain.Webhook, error) {
+	return append([]*domain.Webhook(nil), m.webhooks...), nil
+}
+
+func (m *MockWebhookRepository) ListByEventType(ctx context.Context, eventType string) ([]*domain.Webhook, error) {
+	var result []*domain.Webhook
+	for _, webhook := range m.webhooks {
+		if webhook.Subscribed(eventType) {
+			result = append(result, webhook)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockWebhookRepository) Delete(ctx context.Context, id string) error {
+	for i, webhook := range m.webhooks {
+		if webhook.ID == id {
+			m.webhooks = append(m.webhooks[:i], m.webhooks[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("webhook not found")
+}
+
+// MockWebhookDeliveryRepository хранит доставки в порядке создания
+type MockWebhookDeliveryRepository struct {
+	Deliveries []*domain.WebhookDelivery
+}
+
+func NewMockWebhookDeliveryRepository() *MockWebhookDeliveryRepository {
+	return &MockWebhookDeliveryRepository{}
+}
+
+func (m *MockWebhookDeliveryRepository) Create(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	for _, existing := range m.Deliveries {
+		if existing.WebhookID == delivery.WebhookID && existing.EventID == delivery.EventID {
+			return nil
+		}
+	}
+	delivery.ID = fmt.Sprintf("mock-delivery-%d", len(m.Deliveries)+1)
+	m.Deliveries = append(m.Deliveries, delivery)
+	return nil
+}
+
+func (m *MockWebhookDeliveryRepository) GetByID(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	for _, delivery := range m.Deliveries {
+		if delivery.ID == id {
+			return delivery, nil
+		}
+	}
+	return nil, errors.New("delivery not found")
+}
+
+func (m *MockWebhookDeliveryRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]*domain.WebhookDelivery, error) {
+	var result []*domain.WebhookDelivery
+	for _, delivery := range m.Deliveries {
+		if len(result) == limit {
+			break
+		}
+		if delivery.Status == domain.DeliveryPending && !delivery.NextAttemptAt.After(now) {
+			result = append(result, delivery)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockWebhookDeliveryRepository) List(ctx context.Context, filter repositories.WebhookDeliveryFilter) ([]*domain.WebhookDelivery, error) {
+	var result []*domain.WebhookDelivery
+	for i := len(m.Deliveries) - 1; i >= 0; i-- {
+		delivery := m.Deliveries[i]
+		if delivery.WebhookID == filter.WebhookID && (filter.Status == "" || delivery.Status == filter.Status) {
+			result = append(result, delivery)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockWebhookDeliveryRepository) Update(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	for i, existing := range m.Deliveries {
+		if existing.ID == delivery.ID {
+			m.Deliveries[i] = delivery
+			return nil
+		}
+	}
+	return errors.New("delivery not found")
+}
+
+// MockTransactor выполняет функцию без транзакции и считает вызовы
+type MockTransactor struct {
+	Calls int
+}
+
+func NewMockTransactor() *MockTransactor {
+	return &MockTransactor{}
+}
+
+func (m *MockTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.Calls++
+	return fn(ctx)
+}
+
+// NewTestLogger возвращает логгер, который ничего не пишет
+func NewTestLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// NewTestPolicy возвращает политику прав со встроенными ролями
+func NewTestPolicy() *auth.Policy {
+	return auth.NewPolicy(auth.DefaultRolePermissions())
+}